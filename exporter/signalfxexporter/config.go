@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -43,6 +44,8 @@ type Config struct {
 	// URL is specified. If a path is not included the exporter will
 	// automatically append the appropriate path, eg.: "v2/datapoint".
 	// If a path is specified it will use the one set by the config.
+	// The events are sent to "v2/event" under the same path prefix, i.e. the
+	// path without its "v2/datapoint" suffix.
 	IngestURL string `mapstructure:"ingest_url"`
 
 	// APIURL is the destination to where SignalFx metadata will be sent. This
@@ -83,6 +86,8 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 		return nil, fmt.Errorf("invalid \"ingest_url\": %v", err)
 	}
 
+	eventIngestURL := getEventIngestURL(ingestURL)
+
 	apiURL, err := cfg.getAPIURL()
 	if err != nil {
 		return nil, fmt.Errorf("invalid \"api_url\": %v", err)
@@ -102,6 +107,7 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 
 	return &exporterOptions{
 		ingestURL:        ingestURL,
+		eventIngestURL:   eventIngestURL,
 		apiURL:           apiURL,
 		httpTimeout:      cfg.Timeout,
 		token:            cfg.AccessToken,
@@ -147,6 +153,19 @@ func (cfg *Config) getIngestURL() (out *url.URL, err error) {
 	return out, err
 }
 
+// getEventIngestURL returns the URL the events are sent to, "v2/event" under
+// the same path prefix as the data points, e.g. "https://proxy/sfx/v2/event"
+// for "https://proxy/sfx/v2/datapoint" or "https://proxy/sfx".
+func getEventIngestURL(ingestURL *url.URL) *url.URL {
+	prefix := strings.TrimSuffix(ingestURL.Path, "v2/datapoint")
+	return &url.URL{
+		Scheme: ingestURL.Scheme,
+		User:   ingestURL.User,
+		Host:   ingestURL.Host,
+		Path:   path.Join("/", prefix, "v2/event"),
+	}
+}
+
 func (cfg *Config) getAPIURL() (*url.URL, error) {
 	if cfg.APIURL == "" {
 		return url.Parse(fmt.Sprintf("https://api.%s.signalfx.com", cfg.Realm))
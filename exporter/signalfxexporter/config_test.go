@@ -116,6 +116,40 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 					Host:   "ingest.us1.signalfx.com",
 					Path:   "/v2/datapoint",
 				},
+				eventIngestURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us1.signalfx.com",
+					Path:   "/v2/event",
+				},
+				apiURL: &url.URL{
+					Scheme: "https",
+					Host:   "api.us1.signalfx.com",
+					Path:   "/",
+				},
+				httpTimeout: 5 * time.Second,
+				token:       "access_token",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test ingest URL with a path prefix",
+			fields: fields{
+				Realm:       "us0",
+				AccessToken: "access_token",
+				IngestURL:   "https://proxy.example.com/sfx/v2/datapoint",
+				APIURL:      "https://api.us1.signalfx.com/",
+			},
+			want: &exporterOptions{
+				ingestURL: &url.URL{
+					Scheme: "https",
+					Host:   "proxy.example.com",
+					Path:   "/sfx/v2/datapoint",
+				},
+				eventIngestURL: &url.URL{
+					Scheme: "https",
+					Host:   "proxy.example.com",
+					Path:   "/sfx/v2/event",
+				},
 				apiURL: &url.URL{
 					Scheme: "https",
 					Host:   "api.us1.signalfx.com",
@@ -139,6 +173,11 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 					Host:   "ingest.us0.signalfx.com",
 					Path:   "/v2/datapoint",
 				},
+				eventIngestURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us0.signalfx.com",
+					Path:   "/v2/event",
+				},
 				apiURL: &url.URL{
 					Scheme: "https",
 					Host:   "api.us0.signalfx.com",
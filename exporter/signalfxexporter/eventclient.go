@@ -0,0 +1,89 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxexporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+// sfxEventClient sends the events to the SignalFx backend.
+type sfxEventClient struct {
+	ingestURL              *url.URL
+	headers                map[string]string
+	client                 *http.Client
+	logger                 *zap.Logger
+	accessTokenPassthrough bool
+}
+
+func (s *sfxEventClient) pushEvents(
+	ctx context.Context,
+	events []*sfxpb.Event,
+	accessToken string,
+) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	msg := sfxpb.EventUploadMessage{
+		Events: events,
+	}
+	body, err := msg.Marshal()
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.ingestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	if s.accessTokenPassthrough && accessToken != "" {
+		req.Header.Set(splunk.SFxAccessTokenHeader, accessToken)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	// SignalFx accepts all 2XX codes.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"HTTP %d %q",
+			resp.StatusCode,
+			http.StatusText(resp.StatusCode))
+	}
+
+	return nil
+}
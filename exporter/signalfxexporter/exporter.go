@@ -24,6 +24,7 @@ import (
 	"sync"
 	"time"
 
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
@@ -39,10 +40,12 @@ type signalfxExporter struct {
 	logger                 *zap.Logger
 	pushMetricsData        func(ctx context.Context, md consumerdata.MetricsData) (droppedTimeSeries int, err error)
 	pushKubernetesMetadata func(metadata []*collection.KubernetesMetadataUpdate) error
+	pushEvents             func(ctx context.Context, events []*sfxpb.Event, accessToken string) error
 }
 
 type exporterOptions struct {
 	ingestURL        *url.URL
+	eventIngestURL   *url.URL
 	apiURL           *url.URL
 	httpTimeout      time.Duration
 	token            string
@@ -87,6 +90,16 @@ func New(
 		metricTranslator:       options.metricTranslator,
 	}
 
+	eventClient := &sfxEventClient{
+		ingestURL: options.eventIngestURL,
+		headers:   headers,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		logger:                 logger,
+		accessTokenPassthrough: config.AccessTokenPassthrough,
+	}
+
 	dimClient := dimensions.NewDimensionClient(
 		context.Background(),
 		dimensions.DimensionClientOptions{
@@ -109,6 +122,7 @@ func New(
 		logger:                 logger,
 		pushMetricsData:        dpClient.pushMetricsData,
 		pushKubernetesMetadata: dimClient.PushKubernetesMetadata,
+		pushEvents:             eventClient.pushEvents,
 	}, nil
 }
 
@@ -130,6 +144,12 @@ func (se signalfxExporter) ConsumeMetricsData(ctx context.Context, md consumerda
 	return err
 }
 
+// ConsumeSignalFxEvents sends the events, e.g. received by the SignalFx
+// receiver, to SignalFx.
+func (se signalfxExporter) ConsumeSignalFxEvents(ctx context.Context, events []*sfxpb.Event, accessToken string) error {
+	return se.pushEvents(ctx, events, accessToken)
+}
+
 func (se signalfxExporter) ConsumeKubernetesMetadata(metadata []*collection.KubernetesMetadataUpdate) error {
 	return se.pushKubernetesMetadata(metadata)
 }
@@ -30,6 +30,7 @@ import (
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -270,6 +271,84 @@ func generateLargeBatch(t *testing.T) *consumerdata.MetricsData {
 	return md
 }
 
+func TestConsumeSignalFxEvents(t *testing.T) {
+	category := sfxpb.EventCategory_USER_DEFINED
+	events := []*sfxpb.Event{
+		{
+			EventType: "deploy",
+			Category:  &category,
+			Timestamp: 1574092046000,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "service", Value: "api"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                   string
+		accessTokenPassthrough bool
+		accessToken            string
+		httpResponseCode       int
+		expectedToken          string
+		wantErr                bool
+	}{
+		{
+			name:             "happy_path",
+			accessToken:      "ignored",
+			httpResponseCode: http.StatusOK,
+			expectedToken:    "fromHeaders",
+		},
+		{
+			name:                   "passthrough_access_token",
+			accessTokenPassthrough: true,
+			accessToken:            "fromRequest",
+			httpResponseCode:       http.StatusOK,
+			expectedToken:          "fromRequest",
+		},
+		{
+			name:             "response_forbidden",
+			httpResponseCode: http.StatusForbidden,
+			expectedToken:    "fromHeaders",
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/event", r.URL.Path)
+				assert.Equal(t, tt.expectedToken, r.Header.Get("x-sf-token"))
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				msg := &sfxpb.EventUploadMessage{}
+				require.NoError(t, msg.Unmarshal(body))
+				assert.Equal(t, events, msg.Events)
+				w.WriteHeader(tt.httpResponseCode)
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL + "/v2/event")
+			require.NoError(t, err)
+
+			eventClient := &sfxEventClient{
+				ingestURL: serverURL,
+				headers:   map[string]string{"X-Sf-Token": "fromHeaders"},
+				client: &http.Client{
+					Timeout: 1 * time.Second,
+				},
+				logger:                 zap.NewNop(),
+				accessTokenPassthrough: tt.accessTokenPassthrough,
+			}
+
+			err = eventClient.pushEvents(context.Background(), events, tt.accessToken)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestConsumeKubernetesMetadata(t *testing.T) {
 	type args struct {
 		metadata []*collection.KubernetesMetadataUpdate
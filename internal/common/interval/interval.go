@@ -0,0 +1,180 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interval runs Runnables periodically, either sequentially or in
+// parallel, until the Runner is stopped. It is shared by the receivers that
+// scrape their metrics on a collection interval.
+package interval
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Runnable must be implemented by types passed into the Runner constructor.
+type Runnable interface {
+	// Setup is called once, when the Runner is started.
+	Setup() error
+	// Run is called on the interval passed into NewRunner. The context is
+	// cancelled when the run times out or when the Runner is stopped, the
+	// runnable is expected to return as soon as possible then.
+	Run(ctx context.Context) error
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithTimeout sets the timeout of each run of a Runnable, the interval by
+// default. A timeout of 0 or less is ignored, the interval is used then.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Runner) {
+		if timeout > 0 {
+			r.timeout = timeout
+		}
+	}
+}
+
+// WithRandomStartOffset delays the first run by a random duration up to the
+// interval, so that the Runners started at the same time, e.g. on all of the
+// collectors of a cluster, do not all run at the same time.
+func WithRandomStartOffset() Option {
+	return func(r *Runner) {
+		r.randomStartOffset = true
+	}
+}
+
+// WithParallelRuns runs each Runnable on its own goroutine, on its own
+// schedule, instead of running all of them one after the other.
+func WithParallelRuns() Option {
+	return func(r *Runner) {
+		r.parallel = true
+	}
+}
+
+// WithErrorHandler sets the function the errors returned by Run are reported
+// to, they are dropped by default. The Runner keeps running either way.
+func WithErrorHandler(handler func(error)) Option {
+	return func(r *Runner) {
+		r.errorHandler = handler
+	}
+}
+
+// Returns a random duration in [0, max), replaced in tests.
+var randomOffset = func(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+// Runner takes a list of Runnables, calls Setup() on each of them and then
+// calls Run() on each of them on an interval until it is stopped. By default
+// the Runnables are run sequentially within the same goroutine, a run that
+// lasts longer than the interval delays the next one.
+type Runner struct {
+	interval          time.Duration
+	timeout           time.Duration
+	randomStartOffset bool
+	parallel          bool
+	errorHandler      func(error)
+	runnables         []Runnable
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner creates a new interval runner. Pass in a duration (time between
+// calls), the Runnables to be run on the defined interval and options.
+func NewRunner(interval time.Duration, runnables []Runnable, options ...Option) *Runner {
+	r := &Runner{
+		interval:  interval,
+		timeout:   interval,
+		runnables: runnables,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Start calls Setup() on the Runnables, returning the first error, then
+// starts running them in the background. The first run happens right away,
+// unless a random start offset is enabled.
+func (r *Runner) Start() error {
+	for _, runnable := range r.runnables {
+		if err := runnable.Setup(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	if r.parallel {
+		for _, runnable := range r.runnables {
+			r.wg.Add(1)
+			go r.loop(ctx, runnable)
+		}
+	} else {
+		r.wg.Add(1)
+		go r.loop(ctx, r.runnables...)
+	}
+	return nil
+}
+
+// Stop cancels the runs in progress and waits for them to return. No run is
+// started afterwards.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, runnables ...Runnable) {
+	defer r.wg.Done()
+
+	if r.randomStartOffset && r.interval > 0 {
+		timer := time.NewTimer(randomOffset(r.interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		for _, runnable := range runnables {
+			if ctx.Err() != nil {
+				return
+			}
+			r.run(ctx, runnable)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) run(ctx context.Context, runnable Runnable) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	if err := runnable.Run(ctx); err != nil && r.errorHandler != nil {
+		r.errorHandler(err)
+	}
+}
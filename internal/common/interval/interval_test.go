@@ -0,0 +1,185 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interval
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner(t *testing.T) {
+	first := &fakeRunnable{}
+	second := &fakeRunnable{}
+	r := NewRunner(10*time.Millisecond, []Runnable{first, second})
+	require.NoError(t, r.Start())
+	assert.Eventually(t, func() bool {
+		return first.runCount() >= 3 && second.runCount() >= 3
+	}, 5*time.Second, time.Millisecond)
+	r.Stop()
+
+	assert.Equal(t, 1, first.setups)
+	assert.Equal(t, 1, second.setups)
+	runs := first.runCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, first.runCount(), "ran after the runner was stopped")
+}
+
+func TestRunnerSetupError(t *testing.T) {
+	r := NewRunner(time.Second, []Runnable{&fakeRunnable{setupErr: errors.New("setup failed")}})
+	assert.EqualError(t, r.Start(), "setup failed")
+	// Stopping a runner that did not start is a no-op.
+	r.Stop()
+}
+
+func TestRunnerReportsErrors(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+	f := &fakeRunnable{runErr: errors.New("scrape failed")}
+	r := NewRunner(10*time.Millisecond, []Runnable{f}, WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}))
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	// The runner keeps running after an error.
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) >= 2
+	}, 5*time.Second, time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.EqualError(t, errs[0], "scrape failed")
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	f := &fakeRunnable{block: true}
+	r := NewRunner(time.Hour, []Runnable{f}, WithTimeout(10*time.Millisecond))
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	err := <-f.done
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestRunnerInvalidTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		r := NewRunner(time.Minute, nil, WithTimeout(timeout))
+		assert.Equal(t, time.Minute, r.timeout)
+	}
+}
+
+func TestRunnerStopCancelsRuns(t *testing.T) {
+	f := &fakeRunnable{block: true}
+	r := NewRunner(time.Hour, []Runnable{f})
+	require.NoError(t, r.Start())
+	assert.Eventually(t, func() bool {
+		return f.runCount() == 1
+	}, 5*time.Second, time.Millisecond)
+
+	r.Stop()
+	assert.Equal(t, context.Canceled, <-f.done)
+}
+
+func TestRunnerRandomStartOffset(t *testing.T) {
+	defer func(f func(time.Duration) time.Duration) { randomOffset = f }(randomOffset)
+	var max time.Duration
+	randomOffset = func(m time.Duration) time.Duration {
+		max = m
+		return time.Hour
+	}
+
+	f := &fakeRunnable{}
+	r := NewRunner(10*time.Millisecond, []Runnable{f}, WithRandomStartOffset())
+	require.NoError(t, r.Start())
+	time.Sleep(30 * time.Millisecond)
+	r.Stop()
+
+	assert.Equal(t, 10*time.Millisecond, max)
+	assert.Equal(t, 0, f.runCount())
+}
+
+func TestRunnerParallelRuns(t *testing.T) {
+	// Each runnable only returns once the other one ran, which never happens
+	// when they run sequentially.
+	first := &fakeRunnable{block: true}
+	second := &fakeRunnable{block: true}
+	first.waitFor, second.waitFor = second, first
+	r := NewRunner(time.Hour, []Runnable{first, second}, WithParallelRuns(), WithTimeout(5*time.Second))
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	assert.NoError(t, <-first.done)
+	assert.NoError(t, <-second.done)
+}
+
+type fakeRunnable struct {
+	setupErr error
+	runErr   error
+	// block makes Run return the error of its context once it is done, or nil
+	// once waitFor ran, and send it to done
+	block   bool
+	waitFor *fakeRunnable
+	done    chan error
+
+	setups int
+	mu     sync.Mutex
+	runs   int
+	ran    chan struct{}
+}
+
+func (f *fakeRunnable) Setup() error {
+	f.setups++
+	f.done = make(chan error, 1)
+	f.ran = make(chan struct{})
+	return f.setupErr
+}
+
+func (f *fakeRunnable) Run(ctx context.Context) error {
+	f.mu.Lock()
+	f.runs++
+	if f.runs == 1 {
+		close(f.ran)
+	}
+	f.mu.Unlock()
+
+	if f.block {
+		var waitFor chan struct{}
+		if f.waitFor != nil {
+			waitFor = f.waitFor.ran
+		}
+		select {
+		case <-ctx.Done():
+			f.done <- ctx.Err()
+		case <-waitFor:
+			f.done <- nil
+		}
+	}
+	return f.runErr
+}
+
+func (f *fakeRunnable) runCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.runs
+}
@@ -26,6 +26,8 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/config/configtls"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -60,7 +62,7 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint:  "0.0.0.0:5678",
 				Transport: "udp",
 			},
-			ProxyServer: &proxyServer{
+			ProxyServer: &proxy.Config{
 				TCPAddr: confignet.TCPAddr{
 					Endpoint: "0.0.0.0:2000",
 				},
@@ -89,7 +91,7 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint:  "0.0.0.0:2000",
 				Transport: "udp",
 			},
-			ProxyServer: &proxyServer{
+			ProxyServer: &proxy.Config{
 				TCPAddr: confignet.TCPAddr{
 					Endpoint: "0.0.0.0:1234",
 				},
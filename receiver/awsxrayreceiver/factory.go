@@ -19,12 +19,13 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 )
 
 // NewFactory creates a factory for AWS receiver.
@@ -50,7 +51,7 @@ func createDefaultConfig() configmodels.Receiver {
 			Endpoint:  "0.0.0.0:2000",
 			Transport: "udp",
 		},
-		ProxyServer: &proxyServer{
+		ProxyServer: &proxy.Config{
 			TCPAddr: confignet.TCPAddr{
 				Endpoint: "0.0.0.0:2000",
 			},
@@ -72,6 +73,6 @@ func createTraceReceiver(
 	params component.ReceiverCreateParams,
 	cfg configmodels.Receiver,
 	nextConsumer consumer.TraceConsumer) (component.TraceReceiver, error) {
-	// TODO: Finish the implementation
-	return nil, configerror.ErrDataTypeIsNotSupported
+	rcfg := cfg.(*Config)
+	return newReceiver(rcfg, nextConsumer, params.Logger)
 }
@@ -56,18 +56,22 @@ func TestCreateDefaultConfig(t *testing.T) {
 }
 
 func TestCreateTraceReceiver(t *testing.T) {
-	// TODO: Create proper tests after CreateTraceReceiver is implemented.
 	factory := NewFactory()
-	_, err := factory.CreateTraceReceiver(
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "127.0.0.1:0"
+	cfg.ProxyServer.Endpoint = "127.0.0.1:0"
+	cfg.ProxyServer.Region = "us-west-2"
+	tReceiver, err := factory.CreateTraceReceiver(
 		context.Background(),
 		component.ReceiverCreateParams{
 			Logger: zap.NewNop(),
 		},
-		factory.CreateDefaultConfig().(*Config),
+		cfg,
 		&mockTraceConsumer{},
 	)
-	assert.NotNil(t, err, "not implemented yet")
-	assert.EqualError(t, err, configerror.ErrDataTypeIsNotSupported.Error())
+	assert.NoError(t, err, "trace receiver can be created")
+	assert.NotNil(t, tReceiver)
+	assert.NoError(t, tReceiver.Shutdown(context.Background()))
 }
 
 func TestCreateMetricsReceiver(t *testing.T) {
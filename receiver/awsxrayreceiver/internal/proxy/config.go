@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// DefaultEndpoint is the TCP address the proxy server listens on when
+// no endpoint is configured, as in the X-Ray daemon.
+const DefaultEndpoint = "0.0.0.0:2000"
+
+// Config is the configuration for the local TCP proxy server.
+type Config struct {
+	// endpoint is the TCP address and port on which this receiver listens for
+	// calls from the X-Ray SDK and relays them to the AWS X-Ray backend to
+	// get sampling rules and report sampling statistics. It defaults to
+	// DefaultEndpoint.
+	confignet.TCPAddr `mapstructure:",squash"`
+
+	// ProxyAddress defines the proxy address that the local TCP server
+	// forwards HTTP requests to AWS X-Ray backend through.
+	ProxyAddress string `mapstructure:"proxy_address"`
+
+	// TLSSetting struct exposes TLS client configuration when forwarding
+	// calls to the AWS X-Ray backend.
+	TLSSetting configtls.TLSClientSetting `mapstructure:",squash"`
+
+	// Region is the AWS region the local TCP server forwards requests to.
+	Region string `mapstructure:"region"`
+
+	// RoleARN is the IAM role used by the local TCP server when
+	// communicating with the AWS X-Ray service.
+	RoleARN string `mapstructure:"role_arn"`
+
+	// AWSEndpoint is the X-Ray service endpoint which the local
+	// TCP server forwards requests to.
+	AWSEndpoint string `mapstructure:"aws_endpoint"`
+
+	// LocalMode determines whether the EC2 instance metadata endpoint
+	// will be called or not. Set to `true` to skip EC2 instance
+	// metadata check.
+	LocalMode *bool `mapstructure:"local_mode"`
+}
@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"go.uber.org/zap"
+)
+
+const (
+	awsRegionEnvVar        = "AWS_REGION"
+	awsDefaultRegionEnvVar = "AWS_DEFAULT_REGION"
+)
+
+var (
+	// ErrNoRegion is returned when the region of the X-Ray endpoint cannot
+	// be resolved.
+	ErrNoRegion = errors.New("could not fetch region from config file, environment variables, or EC2 metadata")
+	// ErrNoCredentials is returned when the credentials signing the relayed
+	// requests cannot be resolved.
+	ErrNoCredentials = errors.New("could not resolve the AWS credentials")
+
+	// newAWSSession and getEC2Region are variables so that they can be
+	// replaced in tests.
+	newAWSSession = func(roleARN string, region string, logger *zap.Logger) (*session.Session, error) {
+		sess, err := session.NewSession(&aws.Config{
+			Region: aws.String(region),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if roleARN == "" {
+			return sess, nil
+		}
+		logger.Debug("Assuming IAM role to sign the X-Ray requests",
+			zap.String("roleARN", roleARN))
+		return session.NewSession(&aws.Config{
+			Region:      aws.String(region),
+			Credentials: stscreds.NewCredentials(sess, roleARN),
+		})
+	}
+	getEC2Region = func(s *session.Session) (string, error) {
+		return ec2metadata.New(s).Region()
+	}
+)
+
+// getAWSConfigSession returns the AWS config and session the proxy
+// server signs the relayed requests with.
+func getAWSConfigSession(cfg *Config, logger *zap.Logger) (*aws.Config, *session.Session, error) {
+	awsRegion := cfg.Region
+	if awsRegion == "" {
+		awsRegion = regionFromEnv()
+		if awsRegion != "" {
+			logger.Debug("Fetched region from environment variables", zap.String("region", awsRegion))
+		}
+	}
+	if awsRegion == "" && (cfg.LocalMode == nil || !*cfg.LocalMode) {
+		sess, err := session.NewSession()
+		if err != nil {
+			return nil, nil, err
+		}
+		awsRegion, err = getEC2Region(sess)
+		if err != nil {
+			logger.Debug("Unable to fetch region from EC2 metadata", zap.Error(err))
+		} else {
+			logger.Debug("Fetched region from EC2 metadata", zap.String("region", awsRegion))
+		}
+	}
+	if awsRegion == "" {
+		return nil, nil, ErrNoRegion
+	}
+
+	sess, err := newAWSSession(cfg.RoleARN, awsRegion, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &aws.Config{
+		Region:   aws.String(awsRegion),
+		Endpoint: aws.String(cfg.AWSEndpoint),
+	}, sess, nil
+}
+
+func regionFromEnv() string {
+	if region := os.Getenv(awsRegionEnvVar); region != "" {
+		return region
+	}
+	return os.Getenv(awsDefaultRegionEnvVar)
+}
+
+// getServiceEndpoint returns the X-Ray endpoint the requests are relayed
+// to, which is either the configured one or the X-Ray endpoint of the
+// region.
+func getServiceEndpoint(awsCfg *aws.Config) (string, error) {
+	if endpoint := aws.StringValue(awsCfg.Endpoint); endpoint != "" {
+		return endpoint, nil
+	}
+	resolved, err := endpoints.DefaultResolver().EndpointFor(service, aws.StringValue(awsCfg.Region))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the X-Ray endpoint: %w", err)
+	}
+	return resolved.URL, nil
+}
+
+// newProxyServerTransport returns the HTTP transport used to relay
+// the requests to the X-Ray endpoint.
+func newProxyServerTransport(cfg *Config) (*http.Transport, error) {
+	tlsCfg, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg == nil {
+		// no tls.Config is loaded when insecure is set without a CA.
+		tlsCfg = &tls.Config{ServerName: cfg.TLSSetting.ServerName}
+	}
+	tlsCfg.InsecureSkipVerify = cfg.TLSSetting.Insecure
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyAddress != "" {
+		proxyURL, err := url.Parse(cfg.ProxyAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q: %w", cfg.ProxyAddress, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Transport{
+		MaxIdleConns:        idleConnectionCount,
+		MaxIdleConnsPerHost: idleConnectionCount,
+		IdleConnTimeout:     idleConnectionTimeout,
+		Proxy:               proxy,
+		TLSClientConfig:     tlsCfg,
+
+		// If not disabled the transport will add a gzip encoding header
+		// to requests with no `accept-encoding` header value. The header
+		// is added after we sign the request which invalidates the
+		// signature.
+		DisableCompression: true,
+	}, nil
+}
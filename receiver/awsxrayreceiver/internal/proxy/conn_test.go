@@ -0,0 +1,106 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+)
+
+func TestRegionPrecedence(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{awsRegionEnvVar: "eu-west-1", awsDefaultRegionEnvVar: ""})
+	defer restoreEnv()
+
+	awsCfg, _, err := getAWSConfigSession(&Config{Region: "us-west-2"}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", *awsCfg.Region)
+
+	awsCfg, _, err = getAWSConfigSession(&Config{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", *awsCfg.Region)
+}
+
+func TestRegionFromEC2(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{awsRegionEnvVar: "", awsDefaultRegionEnvVar: ""})
+	defer restoreEnv()
+
+	getEC2RegionOrig := getEC2Region
+	defer func() { getEC2Region = getEC2RegionOrig }()
+
+	getEC2Region = func(*session.Session) (string, error) { return "ap-south-1", nil }
+	awsCfg, _, err := getAWSConfigSession(&Config{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "ap-south-1", *awsCfg.Region)
+
+	getEC2Region = func(*session.Session) (string, error) { return "", errors.New("no metadata") }
+	_, _, err = getAWSConfigSession(&Config{}, zap.NewNop())
+	assert.Equal(t, ErrNoRegion, err)
+
+	// the EC2 metadata is not queried in local mode
+	getEC2Region = func(*session.Session) (string, error) { return "ap-south-1", nil }
+	_, _, err = getAWSConfigSession(&Config{LocalMode: aws.Bool(true)}, zap.NewNop())
+	assert.Equal(t, ErrNoRegion, err)
+}
+
+func TestServiceEndpoint(t *testing.T) {
+	endpoint, err := getServiceEndpoint(&aws.Config{Region: aws.String("us-west-2")})
+	require.NoError(t, err)
+	assert.Equal(t, "https://xray.us-west-2.amazonaws.com", endpoint)
+
+	endpoint, err = getServiceEndpoint(&aws.Config{
+		Region:   aws.String("us-west-2"),
+		Endpoint: aws.String("https://xray.example.com"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://xray.example.com", endpoint)
+}
+
+func TestProxyServerTransport(t *testing.T) {
+	transport, err := newProxyServerTransport(&Config{
+		ProxyAddress: "https://proxy.example.com:8080",
+	})
+	require.NoError(t, err)
+	assert.True(t, transport.DisableCompression)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "xray.us-west-2.amazonaws.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:8080", proxyURL.Host)
+
+	transport, err = newProxyServerTransport(&Config{
+		TLSSetting: configtls.TLSClientSetting{Insecure: true, ServerName: "xray.example.com"},
+	})
+	require.NoError(t, err)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "xray.example.com", transport.TLSClientConfig.ServerName)
+
+	_, err = newProxyServerTransport(&Config{ProxyAddress: "://invalid"})
+	assert.Error(t, err)
+
+	_, err = newProxyServerTransport(&Config{
+		TLSSetting: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: "/nonexistent/ca.pem"},
+		},
+	})
+	assert.Error(t, err)
+}
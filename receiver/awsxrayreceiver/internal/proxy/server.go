@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// service is the name of the AWS service the requests are signed for.
+	service = "xray"
+
+	connHeader = "Connection"
+
+	// reference the X-Ray daemon defaults:
+	// https://github.com/aws/aws-xray-daemon/blob/master/pkg/cfg/cfg.go
+	idleConnectionCount   = 8
+	idleConnectionTimeout = 30 * time.Second
+)
+
+// Server represents the HTTP server relaying the sampling calls
+// of the X-Ray SDK to the X-Ray backend.
+type Server interface {
+	// ListenAndServe listens on the configured TCP endpoint and relays
+	// the incoming requests. It always returns a non-nil error,
+	// http.ErrServerClosed once Close was called.
+	ListenAndServe() error
+	// Close closes the listener and the active connections.
+	Close() error
+}
+
+// NewServer creates a proxy server which signs the requests of the X-Ray
+// SDK (e.g. GetSamplingRules and GetSamplingTargets) with SigV4 and
+// forwards them to the X-Ray endpoint.
+func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	if _, err := net.ResolveTCPAddr("tcp", endpoint); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Using the X-Ray proxy address", zap.String("proxyAddress", cfg.ProxyAddress))
+
+	awsCfg, sess, err := getAWSConfigSession(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// the requests cannot be relayed without credentials to sign them.
+	if _, err = sess.Config.Credentials.Get(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoCredentials, err)
+	}
+
+	awsEndpoint, err := getServiceEndpoint(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+	awsURL, err := url.Parse(awsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newProxyServerTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := v4.NewSigner(sess.Config.Credentials)
+	region := *awsCfg.Region
+
+	reverseProxy := &httputil.ReverseProxy{
+		Transport: transport,
+		ErrorLog:  zap.NewStdLog(logger),
+		// the request is already rewritten and signed by the handler.
+		Director: func(*http.Request) {},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the X-Ray SDK may ask to keep the connection alive, which
+		// should not be relayed nor be part of the signature.
+		req.Header.Del(connHeader)
+
+		req.Host = awsURL.Host
+		req.URL.Scheme = awsURL.Scheme
+		req.URL.Host = awsURL.Host
+
+		// a request which cannot be signed is not relayed, X-Ray would reject it.
+		body, err := readBody(req)
+		if err != nil {
+			logger.Error("Unable to read the request body", zap.Error(err))
+			http.Error(w, "unable to read the request body", http.StatusBadRequest)
+			return
+		}
+		if _, err = signer.Sign(req, body, service, region, time.Now()); err != nil {
+			logger.Error("Unable to sign the request", zap.Error(err))
+			http.Error(w, "unable to sign the request", http.StatusInternalServerError)
+			return
+		}
+		reverseProxy.ServeHTTP(w, req)
+	})
+
+	return &http.Server{
+		Addr:    endpoint,
+		Handler: handler,
+	}, nil
+}
+
+// readBody consumes the request body so that it can be hashed
+// by the signer and returns it as an io.ReadSeeker.
+func readBody(req *http.Request) (io.ReadSeeker, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(buf), nil
+}
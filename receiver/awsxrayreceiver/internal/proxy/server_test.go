@@ -0,0 +1,188 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestInvalidEndpoint(t *testing.T) {
+	cfg := &Config{
+		TCPAddr: confignet.TCPAddr{Endpoint: "invalid-endpoint"},
+		Region:  "us-west-2",
+	}
+	_, err := NewServer(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestNoRegion(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{awsRegionEnvVar: "", awsDefaultRegionEnvVar: ""})
+	defer restoreEnv()
+
+	cfg := &Config{
+		TCPAddr:   confignet.TCPAddr{Endpoint: "127.0.0.1:0"},
+		LocalMode: aws.Bool(true),
+	}
+	_, err := NewServer(cfg, zap.NewNop())
+	assert.Equal(t, ErrNoRegion, err)
+}
+
+func TestNoCredentials(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":           "",
+		"AWS_SECRET_ACCESS_KEY":       "",
+		"AWS_SHARED_CREDENTIALS_FILE": "testdata/nonexistent",
+		"AWS_CONFIG_FILE":             "testdata/nonexistent",
+		"AWS_EC2_METADATA_DISABLED":   "true",
+	})
+	defer restoreEnv()
+
+	cfg := &Config{
+		TCPAddr:   confignet.TCPAddr{Endpoint: "127.0.0.1:0"},
+		Region:    "us-west-2",
+		LocalMode: aws.Bool(true),
+	}
+	_, err := NewServer(cfg, zap.NewNop())
+	assert.True(t, errors.Is(err, ErrNoCredentials), err)
+}
+
+func TestRelaySignedRequests(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":     "AKIDEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+	defer restoreEnv()
+
+	type relayed struct {
+		path, auth, conn, body string
+	}
+	received := make(chan relayed, 1)
+	xray := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- relayed{
+			path: r.URL.Path,
+			auth: r.Header.Get("Authorization"),
+			conn: r.Header.Get(connHeader),
+			body: string(body),
+		}
+		w.Write([]byte(`{"SamplingRuleRecords":[]}`))
+	}))
+	defer xray.Close()
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewServer(&Config{
+		TCPAddr:     confignet.TCPAddr{Endpoint: addr},
+		Region:      "us-west-2",
+		AWSEndpoint: xray.URL,
+		LocalMode:   aws.Bool(true),
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/GetSamplingRules", strings.NewReader(`{"NextToken":null}`))
+		req.Header.Set(connHeader, "keep-alive")
+		resp, err = http.DefaultClient.Do(req)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"SamplingRuleRecords":[]}`, string(body))
+
+	r := <-received
+	assert.Equal(t, "/GetSamplingRules", r.path)
+	assert.Equal(t, `{"NextToken":null}`, r.body)
+	assert.Empty(t, r.conn)
+	assert.True(t, strings.HasPrefix(r.auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), r.auth)
+	assert.Contains(t, r.auth, "/us-west-2/xray/aws4_request")
+}
+
+func TestUnreadableBodyNotRelayed(t *testing.T) {
+	restoreEnv := setEnv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":     "AKIDEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+	defer restoreEnv()
+
+	relayed := false
+	xray := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		relayed = true
+	}))
+	defer xray.Close()
+
+	srv, err := NewServer(&Config{
+		Region:      "us-west-2",
+		AWSEndpoint: xray.URL,
+		LocalMode:   aws.Bool(true),
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEndpoint, srv.(*http.Server).Addr)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/GetSamplingTargets", ioutil.NopCloser(errReader{}))
+	srv.(*http.Server).Handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.False(t, relayed, "a request which cannot be signed must not be relayed")
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// setEnv sets the given environment variables and returns
+// a function restoring their previous values.
+func setEnv(t *testing.T, vars map[string]string) func() {
+	previous := make(map[string]*string, len(vars))
+	for k, v := range vars {
+		if old, ok := os.LookupEnv(k); ok {
+			previous[k] = &old
+		} else {
+			previous[k] = nil
+		}
+		require.NoError(t, os.Setenv(k, v))
+	}
+	return func() {
+		for k, v := range previous {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracesegment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// ProtocolSeparator is the character that separates the header from
+	// the segment document in a datagram emitted by the X-Ray SDK.
+	ProtocolSeparator = '\n'
+
+	// ProtocolFormat is the only header format accepted by the X-Ray daemon.
+	ProtocolFormat = "json"
+	// ProtocolVersion is the only header version accepted by the X-Ray daemon.
+	ProtocolVersion = 1
+
+	// SegmentTypeSubsegment is the value of the `type` field that identifies
+	// subsegments sent independently of their parent segment.
+	SegmentTypeSubsegment = "subsegment"
+)
+
+var (
+	// ErrMissingSeparator is returned when a datagram does not contain the
+	// separator between the header and the segment document.
+	ErrMissingSeparator = errors.New("missing header separator")
+)
+
+// Header stores header of trace segment.
+type Header struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+// IsValid validates the header in the same way as the X-Ray daemon does.
+func (t Header) IsValid() bool {
+	return t.Format == ProtocolFormat && t.Version == ProtocolVersion
+}
+
+// SplitHeaderBody splits a datagram emitted by the X-Ray SDK into its
+// header and the raw segment document that follows it.
+func SplitHeaderBody(buf []byte) (*Header, []byte, error) {
+	idx := bytes.IndexByte(buf, ProtocolSeparator)
+	if idx == -1 {
+		return nil, nil, ErrMissingSeparator
+	}
+
+	var header Header
+	if err := json.Unmarshal(buf[:idx], &header); err != nil {
+		return nil, nil, fmt.Errorf("invalid header: %w", err)
+	}
+	if !header.IsValid() {
+		return nil, nil, fmt.Errorf("unsupported header: %+v", header)
+	}
+
+	return &header, buf[idx+1:], nil
+}
+
+// Segment schema is documented in xray-segmentdocument-schema-v1.0.0 listed
+// on https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
+type Segment struct {
+	// Required fields for both segment and subsegments
+	Name      *string  `json:"name"`
+	ID        *string  `json:"id"`
+	StartTime *float64 `json:"start_time"`
+
+	// Segment-only optional fields
+	Service     *ServiceData `json:"service,omitempty"`
+	Origin      *string      `json:"origin,omitempty"`
+	User        *string      `json:"user,omitempty"`
+	ResourceARN *string      `json:"resource_arn,omitempty"`
+
+	// Optional fields for both Segment and subsegments
+	TraceID     *string                           `json:"trace_id,omitempty"`
+	EndTime     *float64                          `json:"end_time,omitempty"`
+	InProgress  *bool                             `json:"in_progress,omitempty"`
+	HTTP        *HTTPData                         `json:"http,omitempty"`
+	Fault       *bool                             `json:"fault,omitempty"`
+	Error       *bool                             `json:"error,omitempty"`
+	Throttle    *bool                             `json:"throttle,omitempty"`
+	Cause       *CauseData                        `json:"cause,omitempty"`
+	AWS         *AWSData                          `json:"aws,omitempty"`
+	Annotations map[string]interface{}            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]interface{} `json:"metadata,omitempty"`
+	Subsegments []Segment                         `json:"subsegments,omitempty"`
+
+	// (for both embedded and independent) subsegment-only (optional) fields.
+	// Please refer to https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html#api-segmentdocuments-subsegments
+	// for more information on subsegment.
+	Namespace    *string  `json:"namespace,omitempty"`
+	ParentID     *string  `json:"parent_id,omitempty"`
+	Type         *string  `json:"type,omitempty"`
+	PrecursorIDs []string `json:"precursor_ids,omitempty"`
+	Traced       *bool    `json:"traced,omitempty"`
+	SQL          *SQLData `json:"sql,omitempty"`
+}
+
+// Validate checks whether the segment is valid or not.
+func (s *Segment) Validate() error {
+	if s.Name == nil {
+		return errors.New(`segment "name" can not be nil`)
+	}
+
+	if s.ID == nil {
+		return errors.New(`segment "id" can not be nil`)
+	}
+
+	if s.StartTime == nil {
+		return errors.New(`segment "start_time" can not be nil`)
+	}
+
+	// it's ok for embedded subsegments to not have trace_id
+	// but the root segment and independent subsegments must all
+	// have trace_id.
+	if s.TraceID == nil {
+		return errors.New(`segment "trace_id" can not be nil`)
+	}
+
+	return nil
+}
+
+// AWSData represents the aws resource that this segment
+// originates from.
+type AWSData struct {
+	// Segment-only
+	Beanstalk *BeanstalkMetadata `json:"elastic_beanstalk,omitempty"`
+	ECS       *ECSMetadata       `json:"ecs,omitempty"`
+	EC2       *EC2Metadata       `json:"ec2,omitempty"`
+	XRay      *XRayMetaData      `json:"xray,omitempty"`
+
+	// For both segment and subsegments
+	AccountID    *string `json:"account_id,omitempty"`
+	Operation    *string `json:"operation,omitempty"`
+	RemoteRegion *string `json:"region,omitempty"`
+	RequestID    *string `json:"request_id,omitempty"`
+	QueueURL     *string `json:"queue_url,omitempty"`
+	TableName    *string `json:"table_name,omitempty"`
+	Retries      *int64  `json:"retries,omitempty"`
+}
+
+// EC2Metadata represents the EC2 metadata field
+type EC2Metadata struct {
+	InstanceID       *string `json:"instance_id"`
+	AvailabilityZone *string `json:"availability_zone"`
+	InstanceSize     *string `json:"instance_size"`
+	AmiID            *string `json:"ami_id"`
+}
+
+// ECSMetadata represents the ECS metadata field
+type ECSMetadata struct {
+	ContainerName *string `json:"container,omitempty"`
+}
+
+// BeanstalkMetadata represents the Elastic Beanstalk environment metadata field
+type BeanstalkMetadata struct {
+	Environment  *string `json:"environment_name"`
+	VersionLabel *string `json:"version_label"`
+	DeploymentID *int64  `json:"deployment_id"`
+}
+
+// XRayMetaData provides the shape for unmarshalling the X-Ray SDK metadata
+type XRayMetaData struct {
+	SDK                 *string `json:"sdk,omitempty"`
+	SDKVersion          *string `json:"sdk_version,omitempty"`
+	AutoInstrumentation *bool   `json:"auto_instrumentation"`
+}
+
+// CauseType indicates the type of the cause field of a segment.
+type CauseType int
+
+const (
+	// CauseTypeExceptionID indicates that the cause field is a 16 character
+	// exception ID referencing an exception recorded in another subsegment.
+	CauseTypeExceptionID CauseType = iota + 1
+	// CauseTypeObject indicates that the cause field is an object that
+	// contains the recorded exceptions.
+	CauseTypeObject
+)
+
+// CauseData is the container that contains the `cause` field
+type CauseData struct {
+	Type CauseType `json:"-"`
+	// it will contain one of ExceptionID or (WorkingDirectory, Paths, Exceptions)
+	ExceptionID *string `json:"-"`
+
+	CauseObject
+}
+
+// CauseObject is the object form of the `cause` field.
+type CauseObject struct {
+	WorkingDirectory *string     `json:"working_directory,omitempty"`
+	Paths            []string    `json:"paths,omitempty"`
+	Exceptions       []Exception `json:"exceptions,omitempty"`
+}
+
+// UnmarshalJSON is the custom unmarshaller for the cause field, which can
+// either be a 16 character exception ID or an object.
+func (c *CauseData) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var exceptionID string
+		if err := json.Unmarshal(data, &exceptionID); err != nil {
+			return err
+		}
+		c.Type = CauseTypeExceptionID
+		c.ExceptionID = &exceptionID
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &c.CauseObject); err != nil {
+		return err
+	}
+	c.Type = CauseTypeObject
+	return nil
+}
+
+// Exception represents an exception occurred
+type Exception struct {
+	ID        *string      `json:"id,omitempty"`
+	Message   *string      `json:"message,omitempty"`
+	Type      *string      `json:"type,omitempty"`
+	Remote    *bool        `json:"remote,omitempty"`
+	Truncated *int64       `json:"truncated,omitempty"`
+	Skipped   *int64       `json:"skipped,omitempty"`
+	Cause     *string      `json:"cause,omitempty"`
+	Stack     []StackFrame `json:"stack,omitempty"`
+}
+
+// StackFrame represents a frame in the stack when an exception occurred
+type StackFrame struct {
+	Path  *string `json:"path,omitempty"`
+	Line  *int    `json:"line,omitempty"`
+	Label *string `json:"label,omitempty"`
+}
+
+// HTTPData provides the shape for unmarshalling request and response fields.
+type HTTPData struct {
+	Request  *RequestData  `json:"request,omitempty"`
+	Response *ResponseData `json:"response,omitempty"`
+}
+
+// RequestData provides the shape for unmarshalling the request field.
+type RequestData struct {
+	// Available in segment
+	XForwardedFor *bool `json:"x_forwarded_for,omitempty"`
+
+	// Available in both segment and subsegments
+	Method    *string `json:"method,omitempty"`
+	URL       *string `json:"url,omitempty"`
+	UserAgent *string `json:"user_agent,omitempty"`
+	ClientIP  *string `json:"client_ip,omitempty"`
+
+	// Available only in subsegment
+	Traced *bool `json:"traced,omitempty"`
+}
+
+// ResponseData provides the shape for unmarshalling the response field.
+type ResponseData struct {
+	Status        *int64 `json:"status,omitempty"`
+	ContentLength *int64 `json:"content_length,omitempty"`
+}
+
+// ServiceData provides the shape for unmarshalling the service field.
+type ServiceData struct {
+	Version         *string `json:"version,omitempty"`
+	CompilerVersion *string `json:"compiler_version,omitempty"`
+	Compiler        *string `json:"compiler,omitempty"`
+}
+
+// SQLData provides the shape for unmarshalling the sql field.
+type SQLData struct {
+	ConnectionString *string `json:"connection_string,omitempty"`
+	URL              *string `json:"url,omitempty"` // protocol://host[:port]/database
+	SanitizedQuery   *string `json:"sanitized_query,omitempty"`
+	DatabaseType     *string `json:"database_type,omitempty"`
+	DatabaseVersion  *string `json:"database_version,omitempty"`
+	DriverVersion    *string `json:"driver_version,omitempty"`
+	User             *string `json:"user,omitempty"`
+	Preparation      *string `json:"preparation,omitempty"` // "statement" / "call"
+}
@@ -0,0 +1,109 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracesegment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitHeaderBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "valid",
+			input:    `{"format": "json", "version": 1}` + "\n" + `{"name":"a"}`,
+			wantBody: `{"name":"a"}`,
+		},
+		{
+			name:    "missing separator",
+			input:   `{"format": "json", "version": 1}`,
+			wantErr: ErrMissingSeparator.Error(),
+		},
+		{
+			name:    "invalid header json",
+			input:   "not json\n{}",
+			wantErr: "invalid header",
+		},
+		{
+			name:    "unsupported version",
+			input:   `{"format": "json", "version": 2}` + "\n{}",
+			wantErr: "unsupported header",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, body, err := SplitHeaderBody([]byte(tt.input))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &Header{Format: ProtocolFormat, Version: ProtocolVersion}, header)
+			assert.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var seg Segment
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"a","id":"b","start_time":1}`), &seg))
+	assert.EqualError(t, seg.Validate(), `segment "trace_id" can not be nil`)
+
+	seg = Segment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"b","start_time":1,"trace_id":"c"}`), &seg))
+	assert.EqualError(t, seg.Validate(), `segment "name" can not be nil`)
+
+	seg = Segment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"a","start_time":1,"trace_id":"c"}`), &seg))
+	assert.EqualError(t, seg.Validate(), `segment "id" can not be nil`)
+
+	seg = Segment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"a","id":"b","trace_id":"c"}`), &seg))
+	assert.EqualError(t, seg.Validate(), `segment "start_time" can not be nil`)
+
+	seg = Segment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"a","id":"b","start_time":1,"trace_id":"c"}`), &seg))
+	assert.NoError(t, seg.Validate())
+}
+
+func TestCauseUnmarshal(t *testing.T) {
+	var seg Segment
+	require.NoError(t, json.Unmarshal([]byte(`{"cause":"4fbd9d4c0c5e6c3f"}`), &seg))
+	require.NotNil(t, seg.Cause)
+	assert.Equal(t, CauseTypeExceptionID, seg.Cause.Type)
+	assert.Equal(t, "4fbd9d4c0c5e6c3f", *seg.Cause.ExceptionID)
+
+	seg = Segment{}
+	require.NoError(t, json.Unmarshal([]byte(`{"cause":{"working_directory":"/tmp","exceptions":[{"id":"e1","message":"boom"}]}}`), &seg))
+	require.NotNil(t, seg.Cause)
+	assert.Equal(t, CauseTypeObject, seg.Cause.Type)
+	assert.Nil(t, seg.Cause.ExceptionID)
+	assert.Equal(t, "/tmp", *seg.Cause.WorkingDirectory)
+	require.Len(t, seg.Cause.Exceptions, 1)
+	assert.Equal(t, "boom", *seg.Cause.Exceptions[0].Message)
+
+	seg = Segment{}
+	assert.Error(t, json.Unmarshal([]byte(`{"cause":12}`), &seg))
+}
@@ -0,0 +1,56 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// AWSXRayMetadataAttributePrefix is the prefix of the attributes that
+// hold the metadata of a segment, the full key being
+// aws.xray.metadata.<namespace>.<key>.
+const AWSXRayMetadataAttributePrefix = "aws.xray.metadata."
+
+// addAnnotations adds the annotations as span attributes. X-Ray only
+// allows string, number and boolean annotation values.
+func addAnnotations(annos map[string]interface{}, attrs pdata.AttributeMap) {
+	for k, v := range annos {
+		switch t := v.(type) {
+		case string:
+			attrs.UpsertString(k, t)
+		case bool:
+			attrs.UpsertBool(k, t)
+		case float64:
+			attrs.UpsertDouble(k, t)
+		}
+	}
+}
+
+// addMetadata adds the metadata as span attributes. Metadata values can
+// be arbitrary JSON objects so they are kept JSON encoded.
+func addMetadata(meta map[string]map[string]interface{}, attrs pdata.AttributeMap) error {
+	for ns, metaVals := range meta {
+		for k, v := range metaVals {
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			attrs.UpsertString(AWSXRayMetadataAttributePrefix+ns+"."+k, string(val))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+// AWS-specific OpenTelemetry attribute names, identical to the ones
+// consumed by the X-Ray exporter.
+const (
+	AWSOperationAttribute = "aws.operation"
+	AWSAccountAttribute   = "aws.account_id"
+	AWSRegionAttribute    = "aws.region"
+	AWSRequestIDAttribute = "aws.request_id"
+	AWSQueueURLAttribute  = "aws.queue_url"
+	AWSServiceAttribute   = "aws.service"
+	AWSTableNameAttribute = "aws.table_name"
+	AWSRetriesAttribute   = "aws.retries"
+
+	AWSXRayAutoInstrumentationAttribute = "aws.xray.auto_instrumentation"
+
+	cloudProviderAWS = "aws"
+)
+
+// addAWSToResource maps the segment-only aws metadata (EC2, ECS, Elastic
+// Beanstalk and the X-Ray SDK) to resource attributes.
+func addAWSToResource(aws *tracesegment.AWSData, attrs pdata.AttributeMap) {
+	if aws == nil {
+		return
+	}
+
+	attrs.UpsertString(conventions.AttributeCloudProvider, cloudProviderAWS)
+	addString(aws.AccountID, conventions.AttributeCloudAccount, attrs)
+
+	if ec2 := aws.EC2; ec2 != nil {
+		addString(ec2.InstanceID, conventions.AttributeHostID, attrs)
+		addString(ec2.AvailabilityZone, conventions.AttributeCloudZone, attrs)
+		addString(ec2.InstanceSize, conventions.AttributeHostType, attrs)
+		addString(ec2.AmiID, conventions.AttributeHostImageID, attrs)
+	}
+
+	if ecs := aws.ECS; ecs != nil {
+		addString(ecs.ContainerName, conventions.AttributeContainerName, attrs)
+	}
+
+	if bs := aws.Beanstalk; bs != nil {
+		addString(bs.Environment, conventions.AttributeServiceNamespace, attrs)
+		if bs.DeploymentID != nil {
+			attrs.UpsertString(conventions.AttributeServiceInstance, strconv.FormatInt(*bs.DeploymentID, 10))
+		}
+		addString(bs.VersionLabel, conventions.AttributeServiceVersion, attrs)
+	}
+
+	if xray := aws.XRay; xray != nil {
+		addString(xray.SDK, conventions.AttributeTelemetrySDKName, attrs)
+		addString(xray.SDKVersion, conventions.AttributeTelemetrySDKVersion, attrs)
+		addBool(xray.AutoInstrumentation, AWSXRayAutoInstrumentationAttribute, attrs)
+	}
+}
+
+// addAWSToSpan maps the aws fields recorded for calls made to AWS services
+// to span attributes.
+func addAWSToSpan(aws *tracesegment.AWSData, attrs pdata.AttributeMap) {
+	if aws == nil {
+		return
+	}
+
+	addString(aws.AccountID, AWSAccountAttribute, attrs)
+	addString(aws.Operation, AWSOperationAttribute, attrs)
+	addString(aws.RemoteRegion, AWSRegionAttribute, attrs)
+	addString(aws.RequestID, AWSRequestIDAttribute, attrs)
+	addString(aws.QueueURL, AWSQueueURLAttribute, attrs)
+	addString(aws.TableName, AWSTableNameAttribute, attrs)
+	addInt64(aws.Retries, AWSRetriesAttribute, attrs)
+}
@@ -0,0 +1,120 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+// AWSXRayExceptionIDAttribute holds the ID of an exception recorded in
+// another subsegment when the cause field only references it.
+const AWSXRayExceptionIDAttribute = "aws.xray.exception_id"
+
+func addCause(seg *tracesegment.Segment, span pdata.Span) {
+	isFault := seg.Fault != nil && *seg.Fault
+	isError := seg.Error != nil && *seg.Error
+	isThrottle := seg.Throttle != nil && *seg.Throttle
+	if !isFault && !isError && !isThrottle && seg.Cause == nil {
+		return
+	}
+
+	status := span.Status()
+	status.InitEmpty()
+	status.SetCode(pdata.StatusCode(statusCode(seg, isFault, isError, isThrottle)))
+
+	if seg.Cause == nil {
+		return
+	}
+
+	switch seg.Cause.Type {
+	case tracesegment.CauseTypeExceptionID:
+		span.Attributes().UpsertString(AWSXRayExceptionIDAttribute, *seg.Cause.ExceptionID)
+	case tracesegment.CauseTypeObject:
+		events := span.Events()
+		for _, excp := range seg.Cause.Exceptions {
+			event := pdata.NewSpanEvent()
+			event.InitEmpty()
+			event.SetName(conventions.AttributeExceptionEventName)
+			event.SetTimestamp(span.EndTime())
+
+			attrs := event.Attributes()
+			addString(excp.Type, conventions.AttributeExceptionType, attrs)
+			addString(excp.Message, conventions.AttributeExceptionMessage, attrs)
+			if len(excp.Stack) > 0 {
+				attrs.UpsertString(conventions.AttributeExceptionStacktrace, convertStackFramesToStackTraceStr(excp))
+			}
+			events.Append(&event)
+
+			if status.Message() == "" && excp.Message != nil {
+				status.SetMessage(*excp.Message)
+			}
+		}
+	}
+}
+
+// statusCode is the reverse of the fault/error/throttle flags set by the
+// X-Ray exporter. The HTTP status is preferred when it was recorded.
+func statusCode(seg *tracesegment.Segment, isFault, isError, isThrottle bool) int32 {
+	if isThrottle {
+		return tracetranslator.OCResourceExhausted
+	}
+	if seg.HTTP != nil && seg.HTTP.Response != nil && seg.HTTP.Response.Status != nil &&
+		*seg.HTTP.Response.Status >= 400 {
+		return tracetranslator.OCStatusCodeFromHTTP(int32(*seg.HTTP.Response.Status))
+	}
+	switch {
+	case isFault:
+		return tracetranslator.OCInternal
+	case isError:
+		return tracetranslator.OCInvalidArgument
+	}
+	return tracetranslator.OCUnknown
+}
+
+// convertStackFramesToStackTraceStr renders the recorded stack frames in
+// a format similar to a Java stack trace.
+func convertStackFramesToStackTraceStr(excp tracesegment.Exception) string {
+	var b strings.Builder
+	if excp.Type != nil {
+		b.WriteString(*excp.Type)
+	}
+	if excp.Message != nil {
+		b.WriteString(": ")
+		b.WriteString(*excp.Message)
+	}
+	for _, frame := range excp.Stack {
+		b.WriteString("\n\tat ")
+		if frame.Label != nil {
+			b.WriteString(*frame.Label)
+		}
+		b.WriteString("(")
+		if frame.Path != nil {
+			b.WriteString(*frame.Path)
+		}
+		if frame.Line != nil {
+			b.WriteString(":")
+			b.WriteString(strconv.Itoa(*frame.Line))
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+func addHTTP(seg *tracesegment.Segment, span pdata.Span) {
+	if seg.HTTP == nil {
+		return
+	}
+
+	attrs := span.Attributes()
+	if req := seg.HTTP.Request; req != nil {
+		addString(req.Method, conventions.AttributeHTTPMethod, attrs)
+		addString(req.URL, conventions.AttributeHTTPURL, attrs)
+		addString(req.UserAgent, conventions.AttributeHTTPUserAgent, attrs)
+
+		if req.ClientIP != nil {
+			// the X-Ray exporter sets x_forwarded_for only when the client
+			// IP was read from the X-Forwarded-For header.
+			if req.XForwardedFor != nil && *req.XForwardedFor {
+				attrs.UpsertString(conventions.AttributeHTTPClientIP, *req.ClientIP)
+			} else {
+				attrs.UpsertString(conventions.AttributeNetPeerIP, *req.ClientIP)
+			}
+		}
+	}
+
+	if resp := seg.HTTP.Response; resp != nil {
+		addInt64(resp.Status, conventions.AttributeHTTPStatusCode, attrs)
+		addInt64(resp.ContentLength, conventions.AttributeHTTPResponseContentLength, attrs)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+// SQL specific attributes that have no equivalent in the
+// OpenTelemetry semantic conventions.
+const (
+	AWSXRaySQLDatabaseVersionAttribute = "aws.xray.sql.database_version"
+	AWSXRaySQLDriverVersionAttribute   = "aws.xray.sql.driver_version"
+	AWSXRaySQLPreparationAttribute     = "aws.xray.sql.preparation"
+)
+
+// reSQLURL splits the sql url in the form of protocol://host[:port]/database
+// into the connection string (everything before the last slash) and the
+// database name, the reverse of what the X-Ray exporter emits.
+var reSQLURL = regexp.MustCompile(`^(.+)/([^/]*)$`)
+
+func addSQLToSpan(sql *tracesegment.SQLData, attrs pdata.AttributeMap) error {
+	if sql == nil {
+		return nil
+	}
+
+	if sql.URL != nil {
+		matches := reSQLURL.FindStringSubmatch(*sql.URL)
+		if matches == nil {
+			return fmt.Errorf("failed to parse out the database name in the \"sql.url\" field, rawUrl: %s", *sql.URL)
+		}
+		attrs.UpsertString(conventions.AttributeDBConnectionString, matches[1])
+		attrs.UpsertString(conventions.AttributeDBName, matches[2])
+	}
+
+	addString(sql.DatabaseType, conventions.AttributeDBSystem, attrs)
+	addString(sql.SanitizedQuery, conventions.AttributeDBStatement, attrs)
+	addString(sql.User, conventions.AttributeDBUser, attrs)
+	addString(sql.DatabaseVersion, AWSXRaySQLDatabaseVersionAttribute, attrs)
+	addString(sql.DriverVersion, AWSXRaySQLDriverVersionAttribute, attrs)
+	addString(sql.Preparation, AWSXRaySQLPreparationAttribute, attrs)
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+const (
+	// traceIDLength is the fixed length of an X-Ray trace ID,
+	// e.g. 1-58406520-a006649127e371903a2de979.
+	traceIDLength = 35
+	// spanIDLength is the fixed length of an X-Ray segment ID.
+	spanIDLength = 16
+
+	// the namespaces a subsegment can be recorded under.
+	namespaceAWS    = "aws"
+	namespaceRemote = "remote"
+)
+
+// X-Ray specific attributes that have no equivalent in the
+// OpenTelemetry semantic conventions.
+const (
+	AWSXRayInProgressAttribute  = "aws.xray.inprogress"
+	AWSXRayResourceARNAttribute = "aws.xray.resource_arn"
+	AWSXRayTracedAttribute      = "aws.xray.traced"
+)
+
+// ToTraces converts a raw X-Ray segment document into pdata.Traces. The
+// number of spans (i.e. the segment itself and all of its subsegments) is
+// also returned so the caller can report it.
+func ToTraces(rawSeg []byte) (*pdata.Traces, int, error) {
+	var seg tracesegment.Segment
+	if err := json.Unmarshal(rawSeg, &seg); err != nil {
+		return nil, 0, err
+	}
+
+	if err := seg.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	traceData := pdata.NewTraces()
+	rspanSlice := traceData.ResourceSpans()
+	rspanSlice.Resize(1)
+	rspan := rspanSlice.At(0)
+	resource := rspan.Resource()
+	resource.InitEmpty()
+	populateResource(&seg, resource)
+
+	ilsSlice := rspan.InstrumentationLibrarySpans()
+	ilsSlice.Resize(1)
+	spans := ilsSlice.At(0).Spans()
+
+	if err := segToSpans(seg, seg.TraceID, nil, spans); err != nil {
+		return nil, 0, err
+	}
+
+	return &traceData, spans.Len(), nil
+}
+
+// segToSpans converts the given segment and, recursively, all of its
+// embedded subsegments into spans appended to the given span slice.
+func segToSpans(seg tracesegment.Segment, traceID, parentID *string, spans pdata.SpanSlice) error {
+	// embedded subsegments inherit the trace ID of their enclosing segment
+	if seg.TraceID == nil {
+		seg.TraceID = traceID
+	}
+	if err := seg.Validate(); err != nil {
+		return err
+	}
+	// embedded subsegments are children of their enclosing segment
+	if seg.ParentID == nil {
+		seg.ParentID = parentID
+	}
+
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	if err := populateSpan(&seg, parentID != nil, span); err != nil {
+		return err
+	}
+	spans.Append(&span)
+
+	for _, s := range seg.Subsegments {
+		if err := segToSpans(s, seg.TraceID, seg.ID, spans); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func populateSpan(seg *tracesegment.Segment, embedded bool, span pdata.Span) error {
+	traceID, err := convertToOtelTraceID(*seg.TraceID)
+	if err != nil {
+		return err
+	}
+	spanID, err := convertToOtelSpanID(*seg.ID)
+	if err != nil {
+		return err
+	}
+
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetName(*seg.Name)
+	span.SetStartTime(floatSecondsToTimestamp(*seg.StartTime))
+
+	if seg.ParentID != nil {
+		parentID, err := convertToOtelSpanID(*seg.ParentID)
+		if err != nil {
+			return err
+		}
+		span.SetParentSpanID(parentID)
+	}
+
+	attrs := span.Attributes()
+	if seg.InProgress != nil && *seg.InProgress {
+		// in-progress segments are not closed yet, so there is no end time.
+		attrs.UpsertBool(AWSXRayInProgressAttribute, true)
+	} else if seg.EndTime != nil {
+		span.SetEndTime(floatSecondsToTimestamp(*seg.EndTime))
+	}
+
+	addKind(seg, embedded, span)
+	addString(seg.User, conventions.AttributeEnduserID, attrs)
+	addString(seg.ResourceARN, AWSXRayResourceARNAttribute, attrs)
+	addBool(seg.Traced, AWSXRayTracedAttribute, attrs)
+
+	addHTTP(seg, span)
+	addCause(seg, span)
+	addAWSToSpan(seg.AWS, attrs)
+	if err := addSQLToSpan(seg.SQL, attrs); err != nil {
+		return err
+	}
+	addAnnotations(seg.Annotations, attrs)
+	return addMetadata(seg.Metadata, attrs)
+}
+
+// addKind derives the span kind from the segment type and namespace, the
+// reverse of what the X-Ray exporter does when naming segments.
+func addKind(seg *tracesegment.Segment, embedded bool, span pdata.Span) {
+	attrs := span.Attributes()
+	switch {
+	case seg.Namespace != nil && *seg.Namespace == namespaceAWS:
+		span.SetKind(pdata.SpanKindCLIENT)
+		attrs.UpsertString(AWSServiceAttribute, *seg.Name)
+	case seg.Namespace != nil && *seg.Namespace == namespaceRemote:
+		span.SetKind(pdata.SpanKindCLIENT)
+		attrs.UpsertString(conventions.AttributePeerService, *seg.Name)
+	case embedded || (seg.Type != nil && *seg.Type == tracesegment.SegmentTypeSubsegment):
+		span.SetKind(pdata.SpanKindINTERNAL)
+	default:
+		span.SetKind(pdata.SpanKindSERVER)
+	}
+}
+
+func populateResource(seg *tracesegment.Segment, rs pdata.Resource) {
+	attrs := rs.Attributes()
+	if seg.Type == nil || *seg.Type != tracesegment.SegmentTypeSubsegment {
+		// segment names are service names as per the X-Ray documentation
+		attrs.UpsertString(conventions.AttributeServiceName, *seg.Name)
+	}
+	if seg.Service != nil {
+		addString(seg.Service.Version, conventions.AttributeServiceVersion, attrs)
+	}
+	addAWSToResource(seg.AWS, attrs)
+}
+
+// convertToOtelTraceID converts an X-Ray trace ID into the 16 byte
+// representation used by OpenTelemetry: the 8 hexadecimal digit epoch
+// followed by the 24 hexadecimal digit identifier.
+func convertToOtelTraceID(traceID string) (pdata.TraceID, error) {
+	if len(traceID) != traceIDLength {
+		return nil, fmt.Errorf("invalid X-Ray traceID: %s", traceID)
+	}
+	parts := strings.Split(traceID, "-")
+	if len(parts) != 3 || parts[0] != "1" {
+		return nil, fmt.Errorf("invalid X-Ray traceID: %s", traceID)
+	}
+
+	tid, err := hex.DecodeString(parts[1] + parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Ray traceID: %s: %w", traceID, err)
+	}
+	return pdata.NewTraceID(tid), nil
+}
+
+// convertToOtelSpanID converts the 16 hexadecimal digit X-Ray segment ID
+// into its 8 byte representation.
+func convertToOtelSpanID(spanID string) (pdata.SpanID, error) {
+	if len(spanID) != spanIDLength {
+		return nil, fmt.Errorf("invalid X-Ray segment ID: %s", spanID)
+	}
+	sid, err := hex.DecodeString(spanID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Ray segment ID: %s: %w", spanID, err)
+	}
+	return pdata.NewSpanID(sid), nil
+}
+
+func floatSecondsToTimestamp(secs float64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(secs * float64(time.Second))
+}
+
+func addString(val *string, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertString(key, *val)
+	}
+}
+
+func addInt64(val *int64, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertInt(key, *val)
+	}
+}
+
+func addBool(val *bool, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertBool(key, *val)
+	}
+}
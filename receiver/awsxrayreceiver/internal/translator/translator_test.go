@@ -0,0 +1,190 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+func TestTranslation(t *testing.T) {
+	td, count := loadTraces(t, "serverSample.json")
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 4, td.SpanCount())
+
+	rs := td.ResourceSpans().At(0)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeServiceName:         pdata.NewAttributeValueString("SampleServer"),
+		conventions.AttributeServiceVersion:      pdata.NewAttributeValueString("1.2.3"),
+		conventions.AttributeCloudProvider:       pdata.NewAttributeValueString("aws"),
+		conventions.AttributeHostID:              pdata.NewAttributeValueString("i-00f7c0bcb26da2a99"),
+		conventions.AttributeCloudZone:           pdata.NewAttributeValueString("us-west-2c"),
+		conventions.AttributeHostType:            pdata.NewAttributeValueString("t3.micro"),
+		conventions.AttributeHostImageID:         pdata.NewAttributeValueString("ami-0123456789"),
+		conventions.AttributeTelemetrySDKName:    pdata.NewAttributeValueString("X-Ray for Go"),
+		conventions.AttributeTelemetrySDKVersion: pdata.NewAttributeValueString("1.1.0"),
+	}, rs.Resource().Attributes())
+
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	traceID, _ := hex.DecodeString("5f1872536a106696d56b1f4ef9eba2ed")
+
+	root := spans.At(0)
+	assert.Equal(t, pdata.TraceID(traceID), root.TraceID())
+	assert.Equal(t, "5cc4a447f5d4d696", root.SpanID().String())
+	assert.Len(t, root.ParentSpanID(), 0)
+	assert.Equal(t, "SampleServer", root.Name())
+	assert.Equal(t, pdata.SpanKindSERVER, root.Kind())
+	assert.InDelta(t, 1595437651680097000, float64(root.StartTime()), 1000)
+	assert.InDelta(t, 1595437652197392000, float64(root.EndTime()), 1000)
+	assert.True(t, root.Status().IsNil())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeEnduserID:                 pdata.NewAttributeValueString("xraysegmentdump"),
+		conventions.AttributeHTTPMethod:                pdata.NewAttributeValueString("GET"),
+		conventions.AttributeHTTPURL:                   pdata.NewAttributeValueString("http://localhost:8000/"),
+		conventions.AttributeHTTPClientIP:              pdata.NewAttributeValueString("127.0.0.1"),
+		conventions.AttributeHTTPUserAgent:             pdata.NewAttributeValueString("curl/7.64.1"),
+		conventions.AttributeHTTPStatusCode:            pdata.NewAttributeValueInt(200),
+		conventions.AttributeHTTPResponseContentLength: pdata.NewAttributeValueInt(34),
+		"int":    pdata.NewAttributeValueDouble(100),
+		"string": pdata.NewAttributeValueString("value"),
+		"bool":   pdata.NewAttributeValueBool(true),
+		AWSXRayMetadataAttributePrefix + "default.complex": pdata.NewAttributeValueString(`{"a":[1,2]}`),
+	}, root.Attributes())
+
+	dynamo := spans.At(1)
+	assert.Equal(t, pdata.TraceID(traceID), dynamo.TraceID())
+	assert.Equal(t, "5cc4a447f5d4d696", dynamo.ParentSpanID().String())
+	assert.Equal(t, "DynamoDB", dynamo.Name())
+	assert.Equal(t, pdata.SpanKindCLIENT, dynamo.Kind())
+	assert.EqualValues(t, tracetranslator.OCInvalidArgument, dynamo.Status().Code())
+	assert.Equal(t, "ResourceNotFoundException: Requested resource not found", dynamo.Status().Message())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		AWSServiceAttribute:                            pdata.NewAttributeValueString("DynamoDB"),
+		AWSOperationAttribute:                          pdata.NewAttributeValueString("GetItem"),
+		AWSRegionAttribute:                             pdata.NewAttributeValueString("us-west-2"),
+		AWSRequestIDAttribute:                          pdata.NewAttributeValueString("1234abc"),
+		AWSTableNameAttribute:                          pdata.NewAttributeValueString("xray_sample_table"),
+		AWSRetriesAttribute:                            pdata.NewAttributeValueInt(1),
+		conventions.AttributeHTTPStatusCode:            pdata.NewAttributeValueInt(400),
+		conventions.AttributeHTTPResponseContentLength: pdata.NewAttributeValueInt(112),
+	}, dynamo.Attributes())
+	require.Equal(t, 1, dynamo.Events().Len())
+	event := dynamo.Events().At(0)
+	assert.Equal(t, conventions.AttributeExceptionEventName, event.Name())
+	assert.Equal(t, dynamo.EndTime(), event.Timestamp())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionType:    pdata.NewAttributeValueString("dynamodb.ResourceNotFoundException"),
+		conventions.AttributeExceptionMessage: pdata.NewAttributeValueString("ResourceNotFoundException: Requested resource not found"),
+		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString(
+			"dynamodb.ResourceNotFoundException: ResourceNotFoundException: Requested resource not found\n\tat main.handler(main.go:42)"),
+	}, event.Attributes())
+
+	marshal := spans.At(2)
+	assert.Equal(t, "6df0c3a4f3cc7d6e", marshal.ParentSpanID().String())
+	assert.Equal(t, "marshal", marshal.Name())
+	assert.Equal(t, pdata.SpanKindINTERNAL, marshal.Kind())
+	assert.Equal(t, 0, marshal.Attributes().Len())
+
+	sql := spans.At(3)
+	assert.Equal(t, "5cc4a447f5d4d696", sql.ParentSpanID().String())
+	assert.Equal(t, pdata.SpanKindCLIENT, sql.Kind())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributePeerService:        pdata.NewAttributeValueString("ebdb@aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com"),
+		conventions.AttributeDBConnectionString: pdata.NewAttributeValueString("jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432"),
+		conventions.AttributeDBName:             pdata.NewAttributeValueString("ebdb"),
+		conventions.AttributeDBSystem:           pdata.NewAttributeValueString("PostgreSQL"),
+		conventions.AttributeDBStatement:        pdata.NewAttributeValueString("SELECT * FROM customers WHERE customer_id=?;"),
+		conventions.AttributeDBUser:             pdata.NewAttributeValueString("dbuser"),
+		AWSXRaySQLDatabaseVersionAttribute:      pdata.NewAttributeValueString("10.4"),
+		AWSXRaySQLDriverVersionAttribute:        pdata.NewAttributeValueString("PostgreSQL 42.2.4"),
+		AWSXRaySQLPreparationAttribute:          pdata.NewAttributeValueString("statement"),
+	}, sql.Attributes())
+}
+
+func TestTranslationInProgressIndependentSubsegment(t *testing.T) {
+	td, count := loadTraces(t, "inProgressSubsegment.json")
+	assert.Equal(t, 1, count)
+
+	rs := td.ResourceSpans().At(0)
+	_, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+	assert.False(t, ok, "independent subsegments do not name a service")
+
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "5cc4a447f5d4d696", span.ParentSpanID().String())
+	assert.Equal(t, pdata.SpanKindINTERNAL, span.Kind())
+	assert.Equal(t, pdata.TimestampUnixNano(0), span.EndTime())
+	assert.EqualValues(t, tracetranslator.OCResourceExhausted, span.Status().Code())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		AWSXRayInProgressAttribute:  pdata.NewAttributeValueBool(true),
+		AWSXRayExceptionIDAttribute: pdata.NewAttributeValueString("4fbd9d4c0c5e6c3f"),
+	}, span.Attributes())
+}
+
+func TestTranslationErrors(t *testing.T) {
+	tests := []struct {
+		file    string
+		wantErr string
+	}{
+		{
+			file:    "invalidTraceID.json",
+			wantErr: "invalid X-Ray traceID",
+		},
+		{
+			file:    "missingSubsegmentName.json",
+			wantErr: `segment "name" can not be nil`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, err := ioutil.ReadFile(path.Join("testdata", tt.file))
+			require.NoError(t, err)
+			_, _, err = ToTraces(content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, _, err := ToTraces([]byte("{"))
+	assert.Error(t, err)
+}
+
+func loadTraces(t *testing.T, file string) (pdata.Traces, int) {
+	content, err := ioutil.ReadFile(path.Join("testdata", file))
+	require.NoError(t, err)
+
+	td, count, err := ToTraces(content)
+	require.NoError(t, err)
+	require.NotNil(t, td)
+	return *td, count
+}
+
+func assertAttributes(t *testing.T, expected map[string]pdata.AttributeValue, actual pdata.AttributeMap) {
+	assert.Equal(t, len(expected), actual.Len())
+	for k, v := range expected {
+		got, ok := actual.Get(k)
+		if assert.True(t, ok, "missing attribute %q", k) {
+			assert.True(t, v.Equal(got), "attribute %q: expected %v, got %v", k, v, got)
+		}
+	}
+}
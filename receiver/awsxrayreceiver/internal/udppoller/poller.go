@@ -0,0 +1,140 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package udppoller
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+const (
+	// maxPacketSize is the max size of a UDP datagram body (assuming ipv6),
+	// which is large enough for any segment document emitted by the X-Ray SDK.
+	maxPacketSize = 65527
+
+	// segChanSize is the size of the channel the segments are
+	// buffered in before being translated.
+	segChanSize = 500
+)
+
+// RawSegment represents a raw X-Ray segment document and its header.
+type RawSegment struct {
+	Header  *tracesegment.Header
+	Payload []byte
+}
+
+// Config represents the configurations needed to
+// start the UDP poller.
+type Config struct {
+	Endpoint           string
+	NumOfPollerToStart int
+}
+
+// Poller represents one or more goroutines that are
+// polling segments from a UDP socket.
+type Poller interface {
+	// Start starts the polling goroutines.
+	Start()
+	// Close closes the socket, waits for the polling goroutines to exit
+	// and closes the channel returned by SegmentsChan.
+	Close() error
+	// SegmentsChan returns the channel the polled segments are sent to.
+	SegmentsChan() <-chan RawSegment
+}
+
+type poller struct {
+	logger             *zap.Logger
+	udpSock            net.PacketConn
+	numOfPollerToStart int
+	wg                 sync.WaitGroup
+	segChan            chan RawSegment
+}
+
+var _ Poller = (*poller)(nil)
+
+// New creates a new UDP poller listening on the configured endpoint.
+func New(cfg *Config, logger *zap.Logger) (Poller, error) {
+	if cfg.NumOfPollerToStart <= 0 {
+		return nil, errors.New("number of pollers must be greater than 0")
+	}
+
+	udpSock, err := net.ListenPacket("udp", cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poller{
+		logger:             logger,
+		udpSock:            udpSock,
+		numOfPollerToStart: cfg.NumOfPollerToStart,
+		segChan:            make(chan RawSegment, segChanSize),
+	}, nil
+}
+
+func (p *poller) Start() {
+	for i := 0; i < p.numOfPollerToStart; i++ {
+		p.wg.Add(1)
+		go p.poll()
+	}
+}
+
+func (p *poller) Close() error {
+	err := p.udpSock.Close()
+	p.wg.Wait()
+	close(p.segChan)
+	return err
+}
+
+func (p *poller) SegmentsChan() <-chan RawSegment {
+	return p.segChan
+}
+
+func (p *poller) poll() {
+	defer p.wg.Done()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := p.udpSock.ReadFrom(buf)
+		if n > 0 {
+			header, body, splitErr := tracesegment.SplitHeaderBody(buf[:n])
+			if splitErr != nil {
+				p.logger.Warn("Failed to split segment header and body",
+					zap.Error(splitErr))
+			} else {
+				payload := make([]byte, len(body))
+				copy(payload, body)
+				p.segChan <- RawSegment{
+					Header:  header,
+					Payload: payload,
+				}
+			}
+		}
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				p.logger.Debug("Temporary error while reading from the UDP socket",
+					zap.Error(err))
+				continue
+			}
+			// the socket was closed (or is unusable), stop polling
+			p.logger.Debug("Stopped polling the UDP socket", zap.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package udppoller
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/tracesegment"
+)
+
+func TestNonPositivePollerCount(t *testing.T) {
+	_, err := New(&Config{
+		Endpoint:           "127.0.0.1:0",
+		NumOfPollerToStart: 0,
+	}, zap.NewNop())
+	assert.EqualError(t, err, "number of pollers must be greater than 0")
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	_, err := New(&Config{
+		Endpoint:           "invalid-endpoint",
+		NumOfPollerToStart: 1,
+	}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestPollSegments(t *testing.T) {
+	p, err := New(&Config{
+		Endpoint:           "127.0.0.1:0",
+		NumOfPollerToStart: 2,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	p.Start()
+
+	conn, err := net.Dial("udp", p.(*poller).udpSock.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// a datagram without header is dropped
+	_, err = conn.Write([]byte(`{"name":"dropped"}`))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte(`{"format": "json", "version": 1}` + "\n" + `{"name":"kept"}`))
+	require.NoError(t, err)
+
+	select {
+	case seg := <-p.SegmentsChan():
+		assert.Equal(t, &tracesegment.Header{Format: "json", Version: 1}, seg.Header)
+		assert.Equal(t, `{"name":"kept"}`, string(seg.Payload))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the segment")
+	}
+
+	require.NoError(t, p.Close())
+	_, ok := <-p.SegmentsChan()
+	assert.False(t, ok, "the segments channel must be closed")
+}
@@ -0,0 +1,203 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+const (
+	// number of goroutines polling the UDP socket.
+	// https://github.com/aws/aws-xray-daemon/blob/master/pkg/cfg/cfg.go#L184
+	maxPollerCount = 2
+
+	udpTransport = "udp"
+)
+
+// xrayReceiver implements the component.TraceReceiver interface for
+// converting AWS X-Ray segment documents into the OpenTelemetry format.
+type xrayReceiver struct {
+	sync.Mutex
+	instanceName string
+	logger       *zap.Logger
+	endpoint     string
+	consumer     consumer.TraceConsumer
+	// proxyConfig is nil when no proxy server is configured.
+	proxyConfig *proxy.Config
+	// poller is created on Start, nil until then.
+	poller udppoller.Poller
+	// server relays the sampling calls of the X-Ray SDK, it is created
+	// on Start and nil until then or when no proxy server is configured.
+	server proxy.Server
+	// done is closed once all the polled segments were consumed.
+	done chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.TraceReceiver = (*xrayReceiver)(nil)
+
+func newReceiver(config *Config,
+	consumer consumer.TraceConsumer,
+	logger *zap.Logger) (component.TraceReceiver, error) {
+
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.Transport != udpTransport {
+		return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.Transport, config.Name())
+	}
+
+	// the UDP socket is only bound on Start, the endpoint is validated beforehand.
+	if _, err := net.ResolveUDPAddr(udpTransport, config.Endpoint); err != nil {
+		return nil, err
+	}
+
+	// the proxy server resolves the AWS region and credentials on Start,
+	// only its endpoint is validated beforehand.
+	if config.ProxyServer != nil && config.ProxyServer.Endpoint != "" {
+		if _, err := net.ResolveTCPAddr("tcp", config.ProxyServer.Endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return &xrayReceiver{
+		instanceName: config.Name(),
+		logger:       logger,
+		endpoint:     config.Endpoint,
+		consumer:     consumer,
+		proxyConfig:  config.ProxyServer,
+	}, nil
+}
+
+// Start binds the UDP socket and tells the receiver to start polling segments
+// from it and, if configured, to start relaying the sampling calls to X-Ray.
+// The proxy server is not started when the AWS region or credentials cannot
+// be resolved.
+func (x *xrayReceiver) Start(ctx context.Context, host component.Host) error {
+	x.Lock()
+	defer x.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	x.startOnce.Do(func() {
+		// the proxy server is created first so that nothing is left
+		// to close when it cannot be.
+		if x.proxyConfig != nil {
+			x.server, err = proxy.NewServer(x.proxyConfig, x.logger)
+			switch {
+			case errors.Is(err, proxy.ErrNoRegion), errors.Is(err, proxy.ErrNoCredentials):
+				// the segments can still be received, e.g. off AWS.
+				x.logger.Warn("Unable to start the X-Ray proxy server, the sampling calls of the X-Ray SDK are not relayed",
+					zap.Error(err))
+				x.server = nil
+			case err != nil:
+				return
+			}
+		}
+
+		x.logger.Info("Going to listen on endpoint for X-Ray segments",
+			zap.String(udpTransport, x.endpoint))
+		x.poller, err = udppoller.New(&udppoller.Config{
+			Endpoint:           x.endpoint,
+			NumOfPollerToStart: maxPollerCount,
+		}, x.logger)
+		if err != nil {
+			x.server = nil
+			return
+		}
+		x.logger.Info("Listening on endpoint for X-Ray segments",
+			zap.String(udpTransport, x.endpoint))
+
+		x.done = make(chan struct{})
+		x.poller.Start()
+		go x.start()
+		if x.server != nil {
+			go func() {
+				if err := x.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					host.ReportFatalError(err)
+				}
+			}()
+		}
+	})
+	return err
+}
+
+// Shutdown closes the UDP socket and the proxy server, and waits for the
+// segments already received to be passed to the next consumer.
+func (x *xrayReceiver) Shutdown(_ context.Context) error {
+	x.Lock()
+	defer x.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	x.stopOnce.Do(func() {
+		err = nil
+		if x.poller != nil {
+			err = x.poller.Close()
+		}
+		if x.server != nil {
+			if serverErr := x.server.Close(); err == nil {
+				err = serverErr
+			}
+		}
+		if x.done != nil {
+			// wait for the segments already polled to be consumed.
+			<-x.done
+		}
+	})
+	return err
+}
+
+func (x *xrayReceiver) start() {
+	defer close(x.done)
+
+	ctx := obsreport.ReceiverContext(context.Background(), x.instanceName, udpTransport, "")
+	for seg := range x.poller.SegmentsChan() {
+		x.handleSegment(ctx, seg)
+	}
+}
+
+func (x *xrayReceiver) handleSegment(ctx context.Context, seg udppoller.RawSegment) {
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, x.instanceName, udpTransport)
+
+	traces, totalSpanCount, err := translator.ToTraces(seg.Payload)
+	if err != nil {
+		x.logger.Warn("X-Ray segment to OT traces conversion failed", zap.Error(err))
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, totalSpanCount, err)
+		return
+	}
+
+	err = x.consumer.ConsumeTraces(ctx, *traces)
+	if err != nil {
+		x.logger.Warn("Trace consumer errored out", zap.Error(err))
+	}
+	obsreport.EndTraceDataReceiveOp(ctx, typeStr, totalSpanCount, err)
+}
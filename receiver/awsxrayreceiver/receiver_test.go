@@ -0,0 +1,187 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayreceiver
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+const segmentHeader = `{"format": "json", "version": 1}` + "\n"
+
+func TestNilConsumer(t *testing.T) {
+	_, err := newReceiver(createDefaultConfig().(*Config), nil, zap.NewNop())
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+}
+
+func TestNonUDPTransport(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transport = "tcp"
+	_, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	assert.EqualError(t, err, `unsupported transport "tcp" for receiver "aws_xray"`)
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "invalid-endpoint"
+	_, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestSocketBoundOnStart(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.ProxyServer = nil
+	rcvr, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	require.NoError(t, err)
+
+	// the port is still free until the receiver is started
+	conn, err := net.ListenPacket(udpTransport, addr)
+	require.NoError(t, err)
+	assert.Error(t, rcvr.Start(context.Background(), componenttest.NewNopHost()),
+		"the receiver cannot start on a port in use")
+	require.NoError(t, conn.Close())
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+}
+
+func TestProxyCreationFailed(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "127.0.0.1:0"
+	cfg.ProxyServer.Endpoint = "invalid-endpoint"
+	cfg.ProxyServer.Region = "us-west-2"
+	_, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestProxyNotStartedWithoutAWSConfig(t *testing.T) {
+	defer setEnv(t, "AWS_REGION", "")()
+	defer setEnv(t, "AWS_DEFAULT_REGION", "")()
+	defer setEnv(t, "AWS_EC2_METADATA_DISABLED", "true")()
+	addr := testutil.GetAvailableLocalAddress(t)
+	proxyAddr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.ProxyServer.Endpoint = proxyAddr
+	rcvr, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	require.NoError(t, err)
+
+	// the segments are received even though no region can be resolved.
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+	_, err = net.ListenPacket(udpTransport, addr)
+	assert.Error(t, err, "the UDP socket must be bound")
+	_, err = net.Dial("tcp", proxyAddr)
+	assert.Error(t, err, "the proxy server must not be started")
+}
+
+func TestProxyServerStarted(t *testing.T) {
+	defer setEnv(t, "AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")()
+	defer setEnv(t, "AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")()
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "127.0.0.1:0"
+	cfg.ProxyServer.Endpoint = addr
+	cfg.ProxyServer.Region = "us-west-2"
+	rcvr, err := newReceiver(cfg, &mockTraceConsumer{}, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	_, err = net.Dial("tcp", addr)
+	assert.Error(t, err, "the proxy server must be closed on shutdown")
+}
+
+func TestSegmentsConsumed(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := &Config{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  addr,
+			Transport: udpTransport,
+		},
+	}
+	cfg.SetName(typeStr)
+	rcvr, err := newReceiver(cfg, sink, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, componenterror.ErrAlreadyStarted, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial(udpTransport, addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// a malformed segment is dropped and does not stop the receiver
+	_, err = conn.Write([]byte(segmentHeader + `{"name": "invalid"}`))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte(segmentHeader + `{
+		"trace_id": "1-5f187253-6a106696d56b1f4ef9eba2ed",
+		"id": "5cc4a447f5d4d696",
+		"name": "SampleServer",
+		"start_time": 1595437651.680097,
+		"end_time": 1595437652.197392,
+		"subsegments": [{
+			"id": "6df0c3a4f3cc7d6e",
+			"name": "DynamoDB",
+			"namespace": "aws",
+			"start_time": 1595437651.680114,
+			"end_time": 1595437652.196987
+		}]
+	}`))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return sink.SpansCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, rcvr.Shutdown(context.Background()))
+}
+
+// setEnv sets the given environment variable and returns
+// a function restoring its previous value.
+func setEnv(t *testing.T, key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
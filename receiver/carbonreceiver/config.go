@@ -19,6 +19,7 @@ import (
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 )
@@ -40,6 +41,12 @@ type Config struct {
 	// if transport being used is UDP.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
+	// TLSSetting enables TLS on the TCP connections and, if a client CA file
+	// is set, the verification of the client certificates. The certificates
+	// are reloaded when their files change. It is not supported if the
+	// transport being used is UDP.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls_settings"`
+
 	// Parser specifies a parser and the respective configuration to be used
 	// by the receiver.
 	Parser *protocol.Config `mapstructure:"parser"`
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 )
@@ -42,7 +43,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 5)
 
 	r0 := cfg.Receivers["carbon"]
 	assert.Equal(t, factory.CreateDefaultConfig(), r0)
@@ -66,6 +67,17 @@ func TestLoadConfig(t *testing.T) {
 		},
 		r1)
 
+	r := cfg.Receivers["carbon/tls"].(*Config)
+	assert.Equal(t,
+		&configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{
+				CertFile: "/var/lib/mycert.pem",
+				KeyFile:  "/var/lib/mykey.key",
+			},
+			ClientCAFile: "/var/lib/myca.pem",
+		},
+		r.TLSSetting)
+
 	r2 := cfg.Receivers["carbon/regex"].(*Config)
 	assert.Equal(t,
 		&Config{
@@ -97,7 +109,33 @@ func TestLoadConfig(t *testing.T) {
 					},
 					MetricNameSeparator: "_",
 				},
+				TrackStartTime:    true,
+				SeriesIdleTimeout: 10 * time.Minute,
 			},
 		},
 		r2)
+	r3 := cfg.Receivers["carbon/template"].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: configmodels.Type(typeStr),
+				NameVal: "carbon/template",
+			},
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "localhost:2003",
+				Transport: "tcp",
+			},
+			TCPIdleTimeout: 30 * time.Second,
+			Parser: &protocol.Config{
+				Type: "template",
+				Config: &protocol.TemplateParserConfig{
+					Templates: []string{
+						"servers.* .host.measurement.field*",
+						"env.host.measurement* region=us-west",
+					},
+					MetricNameSeparator: "_",
+				},
+			},
+		},
+		r3)
 }
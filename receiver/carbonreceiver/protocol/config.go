@@ -17,6 +17,7 @@ package protocol
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -27,6 +28,7 @@ var (
 	parserMap = map[string]func() ParserConfig{
 		"plaintext": plaintextDefaultConfig,
 		"regex":     regexDefaultConfig,
+		"template":  templateDefaultConfig,
 	}
 
 	// validParsers keeps a list of all valid parsers to be used in error
@@ -60,6 +62,35 @@ type Config struct {
 
 	// Config placeholder for the configuration object of the selected parser.
 	Config ParserConfig `mapstructure:"config"`
+
+	// TrackStartTime enables the tracking of each cumulative time series, ie.:
+	// the combination of metric name and labels, so their StartTimestamp can be
+	// set. The start time is the timestamp of the first point received for the
+	// series and it is moved to the timestamp of the previous point whenever
+	// the value decreases, ie.: the counter was reset. It is disabled by
+	// default since it requires keeping state for every cumulative time series.
+	TrackStartTime bool `mapstructure:"track_start_time"`
+
+	// SeriesIdleTimeout is the duration after which a tracked time series that
+	// did not receive any new point is forgotten, the next point received for
+	// it starts a new series. It is only used when TrackStartTime is enabled,
+	// the default value is 5 minutes.
+	SeriesIdleTimeout time.Duration `mapstructure:"series_idle_timeout"`
+}
+
+// BuildParser builds the parser selected by the configuration, adding the
+// tracking of cumulative time series if TrackStartTime is enabled.
+func (cfg *Config) BuildParser() (Parser, error) {
+	parser, err := cfg.Config.BuildParser()
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.TrackStartTime {
+		return parser, nil
+	}
+
+	return newStartTimeParser(parser, cfg.SeriesIdleTimeout)
 }
 
 // ParserConfig is the configuration of a given parser.
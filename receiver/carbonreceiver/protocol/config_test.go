@@ -66,6 +66,17 @@ config:
 				Config: &RegexParserConfig{},
 			},
 		},
+		{
+			name: "default_template",
+			yaml: `type: template`,
+			cfg:  Config{Type: "template"},
+			want: Config{
+				Type: "template",
+				Config: &TemplateParserConfig{
+					MetricNameSeparator: ".",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -54,9 +54,8 @@ func buildMetricForSinglePoint(
 		},
 		Timeseries: []*metricspb.TimeSeries{
 			{
-				// TODO: StartTimestamp can be set if each cumulative time series are
-				//  	tracked but right now it is not clear if it brings benefits.
-				//		Perhaps as an option so cost is "pay for play".
+				// StartTimestamp is only set if the tracking of cumulative
+				// time series is enabled, see startTimeParser.
 				LabelValues: labelValues,
 				Points:      []*metricspb.Point{point},
 			},
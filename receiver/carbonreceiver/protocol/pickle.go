@@ -0,0 +1,673 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Pickle opcodes needed to decode the lists of metrics sent by Carbon clients,
+// see https://github.com/python/cpython/blob/master/Lib/pickletools.py.
+// Opcodes that instantiate arbitrary objects (e.g.: GLOBAL, REDUCE, BUILD) are
+// purposely not supported.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opPopMark        = '1'
+	opDup            = '2'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opAppends        = 'e'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opEmptyList      = ']'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+
+	opProto           = 0x80
+	opTuple1          = 0x85
+	opTuple2          = 0x86
+	opTuple3          = 0x87
+	opNewTrue         = 0x88
+	opNewFalse        = 0x89
+	opLong1           = 0x8a
+	opLong4           = 0x8b
+	opShortBinUnicode = 0x8c
+	opBinUnicode8     = 0x8d
+	opBinBytes8       = 0x8e
+	opMemoize         = 0x94
+	opFrame           = 0x95
+)
+
+// PickleToLines decodes the pickled payload sent by Carbon clients using the
+// pickle protocol, a list of (path, (timestamp, value)) tuples, into lines of
+// the Carbon plaintext format so that they can be handled by any Parser.
+func PickleToLines(data []byte) ([]string, error) {
+	v, err := decodePickle(data)
+	if err != nil {
+		return nil, err
+	}
+	metrics, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid pickle data: expected a list, got %T", v)
+	}
+
+	lines := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		line, err := pickleMetricToLine(m)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func pickleMetricToLine(m interface{}) (string, error) {
+	metric, ok := m.([]interface{})
+	if !ok || len(metric) != 2 {
+		return "", fmt.Errorf("invalid pickled metric %s: expected (path, (timestamp, value))", describePickleValue(m))
+	}
+	path, ok := metric[0].(string)
+	if !ok || path == "" || strings.ContainsAny(path, " \t\r\n") {
+		return "", fmt.Errorf("invalid pickled metric path %s", describePickleValue(metric[0]))
+	}
+	point, ok := metric[1].([]interface{})
+	if !ok || len(point) != 2 {
+		return "", fmt.Errorf("invalid pickled datapoint %s for %q: expected (timestamp, value)", describePickleValue(metric[1]), path)
+	}
+
+	var ts int64
+	switch t := point[0].(type) {
+	case int64:
+		ts = t
+	case float64:
+		ts = int64(t)
+	default:
+		return "", fmt.Errorf("invalid pickled timestamp %s for %q", describePickleValue(point[0]), path)
+	}
+
+	var value string
+	switch t := point[1].(type) {
+	case int64:
+		value = strconv.FormatInt(t, 10)
+	case float64:
+		value = strconv.FormatFloat(t, 'g', -1, 64)
+		if !strings.ContainsAny(value, ".eEnN") {
+			// Keep the value a double when parsed back.
+			value += ".0"
+		}
+	case bool:
+		value = "0"
+		if t {
+			value = "1"
+		}
+	default:
+		return "", fmt.Errorf("invalid pickled value %s for %q", describePickleValue(point[1]), path)
+	}
+
+	return path + " " + value + " " + strconv.FormatInt(ts, 10), nil
+}
+
+// describePickleValue formats a decoded value for an error message. Lists and
+// tuples are only described by their length since they can share their items
+// and printing them could take time exponential in their depth.
+func describePickleValue(v interface{}) string {
+	switch t := v.(type) {
+	case []interface{}:
+		return fmt.Sprintf("list of %d items", len(t))
+	case string:
+		return strconv.Quote(t)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// pickleMark is pushed on the stack by the MARK opcode.
+type pickleMark struct{}
+
+// pickleList is a pointer so that the lists referenced from the memo are
+// updated by APPEND(S).
+type pickleList struct {
+	items []interface{}
+}
+
+// pickleTuple is a pointer so that the tuples referenced several times from
+// the memo are only unwrapped once.
+type pickleTuple struct {
+	items []interface{}
+}
+
+// unpickler decodes the subset of the pickle format that represents plain
+// data: lists, tuples, strings, numbers, booleans and None.
+type unpickler struct {
+	r     *bytes.Reader
+	stack []interface{}
+	memo  map[int]interface{}
+}
+
+// decodePickle decodes the pickled data returning the resulting value. Lists
+// are returned as []interface{}, tuples as []interface{}, integers as int64,
+// floats as float64 and both strings and bytes as string.
+func decodePickle(data []byte) (interface{}, error) {
+	u := &unpickler{
+		r:    bytes.NewReader(data),
+		memo: make(map[int]interface{}),
+	}
+	v, err := u.decode()
+	if err == nil {
+		v, err = unwrapPickleLists(v, make(map[interface{}][]interface{}))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid pickle data: %v", err)
+	}
+	return v, nil
+}
+
+func (u *unpickler) decode() (interface{}, error) {
+	for {
+		op, err := u.r.ReadByte()
+		if err != nil {
+			return nil, errors.New("unexpected end of data")
+		}
+
+		switch op {
+		case opStop:
+			return u.pop()
+
+		case opProto:
+			if _, err = u.r.ReadByte(); err != nil {
+				return nil, err
+			}
+		case opFrame:
+			// Frames are only a hint for buffering, the size can be skipped.
+			if _, err = u.readN(8); err != nil {
+				return nil, err
+			}
+
+		case opMark:
+			u.push(pickleMark{})
+		case opPop:
+			if _, err = u.pop(); err != nil {
+				return nil, err
+			}
+		case opPopMark:
+			if _, err = u.popMark(); err != nil {
+				return nil, err
+			}
+		case opDup:
+			v, err := u.top()
+			if err != nil {
+				return nil, err
+			}
+			u.push(v)
+
+		case opNone:
+			u.push(nil)
+		case opNewTrue:
+			u.push(true)
+		case opNewFalse:
+			u.push(false)
+
+		case opInt:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			switch line {
+			case "00":
+				u.push(false)
+			case "01":
+				u.push(true)
+			default:
+				i, err := strconv.ParseInt(line, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				u.push(i)
+			}
+		case opLong:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			i, err := strconv.ParseInt(strings.TrimSuffix(line, "L"), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			u.push(i)
+		case opBinInt:
+			b, err := u.readN(4)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(int32(binary.LittleEndian.Uint32(b))))
+		case opBinInt1:
+			b, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(b))
+		case opBinInt2:
+			b, err := u.readN(2)
+			if err != nil {
+				return nil, err
+			}
+			u.push(int64(binary.LittleEndian.Uint16(b)))
+		case opLong1:
+			n, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushLong(int(n)); err != nil {
+				return nil, err
+			}
+		case opLong4:
+			n, err := u.readSize(4)
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushLong(n); err != nil {
+				return nil, err
+			}
+
+		case opFloat:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			f, err := strconv.ParseFloat(line, 64)
+			if err != nil {
+				return nil, err
+			}
+			u.push(f)
+		case opBinFloat:
+			b, err := u.readN(8)
+			if err != nil {
+				return nil, err
+			}
+			u.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+
+		case opString:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			s, err := unquotePickleString(line)
+			if err != nil {
+				return nil, err
+			}
+			u.push(s)
+		case opUnicode:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			u.push(line)
+		case opShortBinString, opShortBinBytes, opShortBinUnicode:
+			n, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushString(int(n)); err != nil {
+				return nil, err
+			}
+		case opBinString, opBinBytes, opBinUnicode:
+			n, err := u.readSize(4)
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushString(n); err != nil {
+				return nil, err
+			}
+		case opBinUnicode8, opBinBytes8:
+			n, err := u.readSize(8)
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushString(n); err != nil {
+				return nil, err
+			}
+
+		case opEmptyList:
+			u.push(&pickleList{})
+		case opList:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(&pickleList{items: items})
+		case opAppend:
+			v, err := u.pop()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.appendToList(v); err != nil {
+				return nil, err
+			}
+		case opAppends:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.appendToList(items...); err != nil {
+				return nil, err
+			}
+
+		case opEmptyTuple:
+			u.push(&pickleTuple{})
+		case opTuple:
+			items, err := u.popMark()
+			if err != nil {
+				return nil, err
+			}
+			u.push(&pickleTuple{items: items})
+		case opTuple1, opTuple2, opTuple3:
+			n := int(op-opTuple1) + 1
+			if len(u.stack) < n {
+				return nil, errors.New("stack underflow")
+			}
+			items := make([]interface{}, n)
+			copy(items, u.stack[len(u.stack)-n:])
+			u.stack = u.stack[:len(u.stack)-n]
+			u.push(&pickleTuple{items: items})
+
+		case opPut:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, err
+			}
+			if err = u.memoize(idx); err != nil {
+				return nil, err
+			}
+		case opBinPut:
+			idx, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.memoize(int(idx)); err != nil {
+				return nil, err
+			}
+		case opLongBinPut:
+			idx, err := u.readIndex()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.memoize(idx); err != nil {
+				return nil, err
+			}
+		case opMemoize:
+			if err = u.memoize(len(u.memo)); err != nil {
+				return nil, err
+			}
+		case opGet:
+			line, err := u.readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushMemo(idx); err != nil {
+				return nil, err
+			}
+		case opBinGet:
+			idx, err := u.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushMemo(int(idx)); err != nil {
+				return nil, err
+			}
+		case opLongBinGet:
+			idx, err := u.readIndex()
+			if err != nil {
+				return nil, err
+			}
+			if err = u.pushMemo(idx); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("unsupported opcode 0x%02x", op)
+		}
+	}
+}
+
+func (u *unpickler) push(v interface{}) {
+	u.stack = append(u.stack, v)
+}
+
+func (u *unpickler) top() (interface{}, error) {
+	if len(u.stack) == 0 {
+		return nil, errors.New("stack underflow")
+	}
+	return u.stack[len(u.stack)-1], nil
+}
+
+func (u *unpickler) pop() (interface{}, error) {
+	v, err := u.top()
+	if err != nil {
+		return nil, err
+	}
+	u.stack = u.stack[:len(u.stack)-1]
+	if _, ok := v.(pickleMark); ok {
+		return nil, errors.New("unexpected mark")
+	}
+	return v, nil
+}
+
+// popMark pops all the items pushed since the last mark, and the mark itself.
+func (u *unpickler) popMark() ([]interface{}, error) {
+	for i := len(u.stack) - 1; i >= 0; i-- {
+		if _, ok := u.stack[i].(pickleMark); ok {
+			items := make([]interface{}, len(u.stack)-i-1)
+			copy(items, u.stack[i+1:])
+			u.stack = u.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, errors.New("mark not found")
+}
+
+func (u *unpickler) appendToList(items ...interface{}) error {
+	v, err := u.top()
+	if err != nil {
+		return err
+	}
+	l, ok := v.(*pickleList)
+	if !ok {
+		return fmt.Errorf("cannot append to %T", v)
+	}
+	l.items = append(l.items, items...)
+	return nil
+}
+
+func (u *unpickler) memoize(idx int) error {
+	v, err := u.top()
+	if err != nil {
+		return err
+	}
+	u.memo[idx] = v
+	return nil
+}
+
+func (u *unpickler) pushMemo(idx int) error {
+	v, ok := u.memo[idx]
+	if !ok {
+		return fmt.Errorf("memo key %d not found", idx)
+	}
+	u.push(v)
+	return nil
+}
+
+func (u *unpickler) pushString(n int) error {
+	b, err := u.readN(n)
+	if err != nil {
+		return err
+	}
+	u.push(string(b))
+	return nil
+}
+
+// pushLong pushes a little-endian two's complement integer of n bytes.
+func (u *unpickler) pushLong(n int) error {
+	b, err := u.readN(n)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		u.push(int64(0))
+		return nil
+	}
+	be := make([]byte, n)
+	for i := range b {
+		be[n-1-i] = b[i]
+	}
+	i := new(big.Int).SetBytes(be)
+	if be[0]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+	}
+	if !i.IsInt64() {
+		return fmt.Errorf("integer %v overflows int64", i)
+	}
+	u.push(i.Int64())
+	return nil
+}
+
+func (u *unpickler) readN(n int) ([]byte, error) {
+	if n < 0 || n > u.r.Len() {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := make([]byte, n)
+	_, err := u.r.Read(b)
+	return b, err
+}
+
+// readSize reads a little-endian unsigned size of n bytes.
+func (u *unpickler) readSize(n int) (int, error) {
+	b, err := u.readN(n)
+	if err != nil {
+		return 0, err
+	}
+	var size uint64
+	for i := n - 1; i >= 0; i-- {
+		size = size<<8 | uint64(b[i])
+	}
+	if size > uint64(u.r.Len()) {
+		return 0, errors.New("unexpected end of data")
+	}
+	return int(size), nil
+}
+
+// readIndex reads the little-endian 4 bytes memo index of the LONG_BINPUT and
+// LONG_BINGET opcodes. Unlike a size it is not bound by the remaining data.
+func (u *unpickler) readIndex() (int, error) {
+	b, err := u.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.LittleEndian.Uint32(b)), nil
+}
+
+func (u *unpickler) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := u.r.ReadByte()
+		if err != nil {
+			return "", errors.New("unexpected end of data")
+		}
+		if b == '\n' {
+			return sb.String(), nil
+		}
+		sb.WriteByte(b)
+	}
+}
+
+// unquotePickleString unquotes the repr of a Python 2 string used by the
+// STRING opcode.
+func unquotePickleString(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		// Convert to a Go quoted string so strconv.Unquote can be used.
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// unwrapPickleLists replaces the *pickleList and *pickleTuple values used
+// during decoding by plain []interface{}. A list can reference itself through
+// the memo, so the values being unwrapped are tracked and such a cycle is
+// reported as an error. The values already unwrapped are reused so that the
+// ones referenced several times are only unwrapped once, otherwise nesting
+// shared tuples would make the work grow exponentially with the depth.
+func unwrapPickleLists(v interface{}, unwrapped map[interface{}][]interface{}) (interface{}, error) {
+	var items []interface{}
+	switch t := v.(type) {
+	case *pickleList:
+		items = t.items
+	case *pickleTuple:
+		items = t.items
+	default:
+		return v, nil
+	}
+	if u, ok := unwrapped[v]; ok {
+		if u == nil {
+			return nil, errors.New("self-referencing list")
+		}
+		return u, nil
+	}
+	// Mark the value as being unwrapped.
+	unwrapped[v] = nil
+	u := make([]interface{}, len(items))
+	for i, item := range items {
+		var err error
+		if u[i], err = unwrapPickleLists(item, unwrapped); err != nil {
+			return nil, err
+		}
+	}
+	unwrapped[v] = u
+	return u, nil
+}
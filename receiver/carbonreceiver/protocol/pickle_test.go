@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickleToLines(t *testing.T) {
+	// Pickled in Python with protocols 0 to 5:
+	// [("test.metric", (1582230020, 1)), ("test.double", (1582230020.5, 2.5)),
+	//  ("test.neg", (1582230020, -7)), ("test.big", (1582230020, 1 << 40))]
+	wantLines := []string{
+		"test.metric 1 1582230020",
+		"test.double 2.5 1582230020",
+		"test.neg -7 1582230020",
+		"test.big 1099511627776 1582230020",
+	}
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "protocol_0", data: "(lp0\n(Vtest.metric\np1\n(I1582230020\nI1\ntp2\ntp3\na(Vtest.double\np4\n(F1582230020.5\nF2.5\ntp5\ntp6\na(Vtest.neg\np7\n(I1582230020\nI-7\ntp8\ntp9\na(Vtest.big\np10\n(I1582230020\nL1099511627776L\ntp11\ntp12\na."},
+		{name: "protocol_1", data: "]q\x00((X\x0b\x00\x00\x00test.metricq\x01(J\x04\xeaN^K\x01tq\x02tq\x03(X\x0b\x00\x00\x00test.doubleq\x04(GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00tq\x05tq\x06(X\x08\x00\x00\x00test.negq\x07(J\x04\xeaN^J\xf9\xff\xff\xfftq\x08tq\x09(X\x08\x00\x00\x00test.bigq\n(J\x04\xeaN^L1099511627776L\ntq\x0btq\x0ce."},
+		{name: "protocol_2", data: "\x80\x02]q\x00(X\x0b\x00\x00\x00test.metricq\x01J\x04\xeaN^K\x01\x86q\x02\x86q\x03X\x0b\x00\x00\x00test.doubleq\x04GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86q\x05\x86q\x06X\x08\x00\x00\x00test.negq\x07J\x04\xeaN^J\xf9\xff\xff\xff\x86q\x08\x86q\x09X\x08\x00\x00\x00test.bigq\nJ\x04\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86q\x0b\x86q\x0ce."},
+		{name: "protocol_3", data: "\x80\x03]q\x00(X\x0b\x00\x00\x00test.metricq\x01J\x04\xeaN^K\x01\x86q\x02\x86q\x03X\x0b\x00\x00\x00test.doubleq\x04GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86q\x05\x86q\x06X\x08\x00\x00\x00test.negq\x07J\x04\xeaN^J\xf9\xff\xff\xff\x86q\x08\x86q\x09X\x08\x00\x00\x00test.bigq\nJ\x04\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86q\x0b\x86q\x0ce."},
+		{name: "protocol_4", data: "\x80\x04\x95w\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0btest.metric\x94J\x04\xeaN^K\x01\x86\x94\x86\x94\x8c\x0btest.double\x94GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\x08test.neg\x94J\x04\xeaN^J\xf9\xff\xff\xff\x86\x94\x86\x94\x8c\x08test.big\x94J\x04\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86\x94\x86\x94e."},
+		{name: "protocol_5", data: "\x80\x05\x95w\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0btest.metric\x94J\x04\xeaN^K\x01\x86\x94\x86\x94\x8c\x0btest.double\x94GA\xd7\x93\xba\x81 \x00\x00G@\x04\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\x08test.neg\x94J\x04\xeaN^J\xf9\xff\xff\xff\x86\x94\x86\x94\x8c\x08test.big\x94J\x04\xeaN^\x8a\x06\x00\x00\x00\x00\x00\x01\x86\x94\x86\x94e."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PickleToLines([]byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, wantLines, got)
+		})
+	}
+}
+
+func TestPickleToLines_LargeMemo(t *testing.T) {
+	// Pickled in Python with protocol 2, the memo has more than 256 entries
+	// so LONG_BINPUT and LONG_BINGET are used:
+	// points = [(1582230020 + i, i * 0.5) for i in range(300)]
+	// [("test.metric%d" % i, points[i % 300]) for i in range(600)]
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "pickle_protocol2.bin"))
+	require.NoError(t, err)
+
+	got, err := PickleToLines(data)
+	require.NoError(t, err)
+	require.Len(t, got, 600)
+	for i, line := range got {
+		j := i % 300
+		want := fmt.Sprintf("test.metric%d %s %d", i, strconv.FormatFloat(float64(j)*0.5, 'f', -1, 64), 1582230020+j)
+		if j%2 == 0 {
+			// Whole doubles keep a decimal point.
+			want = fmt.Sprintf("test.metric%d %d.0 %d", i, j/2, 1582230020+j)
+		}
+		assert.Equal(t, want, line)
+	}
+}
+
+func TestPickleToLines_Values(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "python2_string_and_long",
+			data: "(lp0\n(S'a.b'\np1\n(L1582230020L\nF1.5\ntp2\ntp3\na.",
+			want: []string{"a.b 1.5 1582230020"},
+		},
+		{
+			name: "whole_double",
+			data: "\x80\x02]q\x00X\x01\x00\x00\x00aq\x01K\x01G@\x08\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03a.",
+			want: []string{"a 3.0 1"},
+		},
+		{
+			name: "empty_list",
+			data: "\x80\x02]q\x00.",
+			want: []string{},
+		},
+		{
+			name:    "path_with_space",
+			data:    "\x80\x02]q\x00X\x03\x00\x00\x00a bq\x01K\x01K\x02\x86q\x02\x86q\x03a.",
+			wantErr: true,
+		},
+		{
+			name:    "none_value",
+			data:    "\x80\x02]q\x00X\x01\x00\x00\x00aq\x01K\x01N\x86q\x02\x86q\x03a.",
+			wantErr: true,
+		},
+		{
+			name:    "not_a_list",
+			data:    "\x80\x02}q\x00X\x01\x00\x00\x00aq\x01K\x01s.",
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			data:    "\x80\x02]q\x00X\x01\x00\x00\x00aq\x01K\x01K\x02\x86q\x02\x86q",
+			wantErr: true,
+		},
+		{
+			name:    "global_not_supported",
+			data:    "cos\nsystem\n(S'ls'\ntR.",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			data:    "",
+			wantErr: true,
+		},
+		{
+			name: "shared_list",
+			data: "\x80\x02]q\x00(X\x01\x00\x00\x00aq\x01]q\x02(K\x01K\x02e\x86q\x03X\x01\x00\x00\x00bq\x04h\x02\x86q\x05e.",
+			want: []string{"a 2 1", "b 2 1"},
+		},
+		{
+			// The list is appended to itself through the memo.
+			name:    "self_referencing_list",
+			data:    "]q\x00h\x00a.",
+			wantErr: true,
+		},
+		{
+			// Each tuple holds the previous one twice, so unwrapping the
+			// references instead of reusing them doubles the work per level.
+			name:    "deeply_shared_tuple",
+			data:    "\x80\x02]q\x00X\x01\x00\x00\x00aq\x01K\x01q\x020" + strings.Repeat("h\x02h\x02\x86q\x02", 200) + "\x86q\x03a.",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PickleToLines([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// SeriesStates holds the state kept by a parser for each time series, the
+// states of the time series that did not receive points for longer than an
+// idle timeout are discarded. It is not safe for concurrent use.
+type SeriesStates struct {
+	idleTimeout time.Duration
+	states      map[string]*seriesEntry
+	lastSweep   time.Time
+}
+
+type seriesEntry struct {
+	state    interface{}
+	lastSeen time.Time
+}
+
+// NewSeriesStates creates the SeriesStates discarding the states of the time
+// series idle for longer than idleTimeout.
+func NewSeriesStates(idleTimeout time.Duration) *SeriesStates {
+	return &SeriesStates{
+		idleTimeout: idleTimeout,
+		states:      make(map[string]*seriesEntry),
+	}
+}
+
+// Get returns the state of the time series identified by key, nil if it has
+// none or if it was idle for longer than the idle timeout at now.
+func (s *SeriesStates) Get(key string, now time.Time) interface{} {
+	entry, ok := s.states[key]
+	if !ok || now.Sub(entry.lastSeen) >= s.idleTimeout {
+		return nil
+	}
+	return entry.state
+}
+
+// Set sets the state of the time series identified by key, which received a
+// point at now. To keep its cost low the idle time series are discarded at
+// most once per idle timeout, by Set, Get ignores those not discarded yet.
+func (s *SeriesStates) Set(key string, state interface{}, now time.Time) {
+	if now.Sub(s.lastSweep) >= s.idleTimeout {
+		for k, entry := range s.states {
+			if now.Sub(entry.lastSeen) >= s.idleTimeout {
+				delete(s.states, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	s.states[key] = &seriesEntry{state: state, lastSeen: now}
+}
+
+// Len returns the number of time series with a state.
+func (s *SeriesStates) Len() int {
+	return len(s.states)
+}
+
+// SeriesKey builds the key identifying a time series from the metric name and
+// its labels. The labels are sorted by key so that the key does not depend on
+// the order in which they were received.
+func SeriesKey(
+	name string,
+	keys []*metricspb.LabelKey,
+	values []*metricspb.LabelValue,
+) string {
+	labels := make([][2]string, len(keys))
+	for i, k := range keys {
+		labels[i][0] = k.GetKey()
+		if i < len(values) && values[i].GetHasValue() {
+			labels[i][1] = values[i].GetValue()
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i][0] != labels[j][0] {
+			return labels[i][0] < labels[j][0]
+		}
+		return labels[i][1] < labels[j][1]
+	})
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, label := range labels {
+		sb.WriteByte(0)
+		sb.WriteString(label[0])
+		sb.WriteByte('=')
+		sb.WriteString(label[1])
+	}
+	return sb.String()
+}
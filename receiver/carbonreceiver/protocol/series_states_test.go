@@ -0,0 +1,63 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeriesStates(t *testing.T) {
+	s := NewSeriesStates(time.Minute)
+	now := time.Unix(1582230000, 0)
+	assert.Nil(t, s.Get("a", now))
+
+	s.Set("a", 1, now)
+	s.Set("b", 2, now)
+	assert.Equal(t, 1, s.Get("a", now))
+	assert.Equal(t, 2, s.Get("b", now))
+
+	now = now.Add(30 * time.Second)
+	s.Set("a", 3, now)
+	assert.Equal(t, 3, s.Get("a", now))
+
+	// "b" is idle, it is ignored and discarded by the next sweep.
+	now = now.Add(30 * time.Second)
+	assert.Nil(t, s.Get("b", now))
+	assert.Equal(t, 3, s.Get("a", now))
+	s.Set("c", 4, now)
+	assert.Equal(t, 2, s.Len())
+}
+
+func TestSeriesKey(t *testing.T) {
+	keys := []*metricspb.LabelKey{{Key: "a"}, {Key: "b"}}
+	k0 := SeriesKey("m", keys, []*metricspb.LabelValue{
+		{Value: "x", HasValue: true}, {Value: "y", HasValue: true}})
+	k1 := SeriesKey("m", keys, []*metricspb.LabelValue{
+		{Value: "x", HasValue: true}, {Value: "z", HasValue: true}})
+	k2 := SeriesKey("m", keys, []*metricspb.LabelValue{
+		{Value: "x", HasValue: true}, {Value: "y", HasValue: true}})
+	assert.NotEqual(t, k0, k1)
+	assert.Equal(t, k0, k2)
+
+	// The same labels received in another order.
+	k3 := SeriesKey("m", []*metricspb.LabelKey{{Key: "b"}, {Key: "a"}}, []*metricspb.LabelValue{
+		{Value: "y", HasValue: true}, {Value: "x", HasValue: true}})
+	assert.Equal(t, k0, k3)
+	assert.NotEqual(t, k0, SeriesKey("m", nil, nil))
+}
@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+const (
+	// SeriesIdleTimeoutDefault is the default duration after which the state of
+	// idle cumulative time series is discarded.
+	SeriesIdleTimeoutDefault = 5 * time.Minute
+)
+
+// startTimeParser wraps a Parser to set the StartTimestamp of the cumulative
+// time series that it generates. Parse is safe for concurrent use since a
+// single parser is shared by all connections of a receiver.
+type startTimeParser struct {
+	parser Parser
+	// now is used to measure the time series idle time, it can be replaced on
+	// tests.
+	now func() time.Time
+
+	mu     sync.Mutex
+	series *SeriesStates
+}
+
+// seriesState is the state kept for each cumulative time series.
+type seriesState struct {
+	startTime     *timestamp.Timestamp
+	lastTimestamp *timestamp.Timestamp
+	lastValue     float64
+}
+
+var _ (Parser) = (*startTimeParser)(nil)
+
+func newStartTimeParser(parser Parser, idleTimeout time.Duration) (Parser, error) {
+	if idleTimeout < 0 {
+		return nil, fmt.Errorf("invalid series idle timeout: %v", idleTimeout)
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = SeriesIdleTimeoutDefault
+	}
+
+	return &startTimeParser{
+		parser: parser,
+		now:    time.Now,
+		series: NewSeriesStates(idleTimeout),
+	}, nil
+}
+
+// Parse parses the line with the wrapped parser and, if the resulting metric
+// is cumulative, sets the StartTimestamp of its time series.
+func (stp *startTimeParser) Parse(line string) (*metricspb.Metric, error) {
+	metric, err := stp.parser.Parse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor := metric.GetMetricDescriptor()
+	switch descriptor.GetType() {
+	case metricspb.MetricDescriptor_CUMULATIVE_INT64, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+	default:
+		return metric, nil
+	}
+
+	stp.mu.Lock()
+	defer stp.mu.Unlock()
+
+	now := stp.now()
+
+	for _, ts := range metric.Timeseries {
+		if len(ts.Points) == 0 {
+			continue
+		}
+		point := ts.Points[len(ts.Points)-1]
+		value := pointValue(point)
+
+		key := SeriesKey(descriptor.Name, descriptor.LabelKeys, ts.LabelValues)
+		state, ok := stp.series.Get(key, now).(*seriesState)
+		switch {
+		case !ok:
+			state = &seriesState{startTime: point.Timestamp}
+		case value < state.lastValue:
+			// The counter was reset sometime after the previous point.
+			state.startTime = state.lastTimestamp
+		}
+		state.lastTimestamp = point.Timestamp
+		state.lastValue = value
+		stp.series.Set(key, state, now)
+
+		ts.StartTimestamp = state.startTime
+	}
+
+	return metric, nil
+}
+
+func pointValue(point *metricspb.Point) float64 {
+	switch v := point.Value.(type) {
+	case *metricspb.Point_Int64Value:
+		return float64(v.Int64Value)
+	case *metricspb.Point_DoubleValue:
+		return v.DoubleValue
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_BuildParser_TrackStartTime(t *testing.T) {
+	cfg := &Config{
+		Type:   "plaintext",
+		Config: &PlaintextConfig{},
+	}
+	p, err := cfg.BuildParser()
+	require.NoError(t, err)
+	assert.IsType(t, &PathParserHelper{}, p)
+
+	cfg.TrackStartTime = true
+	p, err = cfg.BuildParser()
+	require.NoError(t, err)
+	require.IsType(t, &startTimeParser{}, p)
+	assert.Equal(t, SeriesIdleTimeoutDefault, p.(*startTimeParser).series.idleTimeout)
+
+	cfg.SeriesIdleTimeout = -1 * time.Second
+	_, err = cfg.BuildParser()
+	assert.EqualError(t, err, "invalid series idle timeout: -1s")
+}
+
+func Test_startTimeParser_Parse(t *testing.T) {
+	regexParser, err := (&RegexParserConfig{
+		Rules: []*RegexRule{
+			{
+				Regexp:     `^(?P<key_host>[^.]+)\.(?P<name_0>.*)\.count$`,
+				MetricType: string(CumulativeMetricType),
+			},
+		},
+	}).BuildParser()
+	require.NoError(t, err)
+
+	p, err := newStartTimeParser(regexParser, time.Minute)
+	require.NoError(t, err)
+	stp := p.(*startTimeParser)
+	now := time.Unix(1582230000, 0)
+	stp.now = func() time.Time { return now }
+
+	tests := []struct {
+		name          string
+		line          string
+		elapsed       time.Duration
+		wantStartTime *timestamp.Timestamp
+	}{
+		{
+			name:          "first_point",
+			line:          "host0.rpc.count 10 1582230020",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230020},
+		},
+		{
+			name:          "other_series",
+			line:          "host1.rpc.count 5.5 1582230025",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230025},
+		},
+		{
+			name:          "increase",
+			line:          "host0.rpc.count 15 1582230030",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230020},
+		},
+		{
+			name:          "no_change",
+			line:          "host0.rpc.count 15 1582230040",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230020},
+		},
+		{
+			name:          "reset",
+			line:          "host0.rpc.count 3 1582230050",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230040},
+		},
+		{
+			name:          "after_reset",
+			line:          "host0.rpc.count 7 1582230060",
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230040},
+		},
+		{
+			name:          "gauge_not_tracked",
+			line:          "host0.rpc.gauge 1 1582230060",
+			wantStartTime: nil,
+		},
+		{
+			name:          "idle_series_restarted",
+			line:          "host0.rpc.count 9 1582230200",
+			elapsed:       2 * time.Minute,
+			wantStartTime: &timestamp.Timestamp{Seconds: 1582230200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+			got, err := stp.Parse(tt.line)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(got.Timeseries))
+			assert.Equal(t, tt.wantStartTime, got.Timeseries[0].StartTimestamp)
+		})
+	}
+
+	// The sweep removed the idle series of "host1".
+	assert.Equal(t, 1, stp.series.Len())
+}
+
+func Test_startTimeParser_ParseError(t *testing.T) {
+	p, err := (&Config{
+		Type:           "plaintext",
+		Config:         &PlaintextConfig{},
+		TrackStartTime: true,
+	}).BuildParser()
+	require.NoError(t, err)
+
+	got, err := p.Parse("invalid")
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,353 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+const (
+	// Special parts of a template, any other non-empty part is a label key.
+	templateMeasurement       = "measurement"
+	templateMeasurementGreedy = "measurement*"
+	templateField             = "field"
+	templateFieldGreedy       = "field*"
+
+	// templateWildcard matches any part of a path on template filters.
+	templateWildcard = "*"
+
+	templateNameSeparatorDefault = "."
+)
+
+// TemplateParserConfig has the configuration for a parser that breaks down
+// a Carbon "metric path" into a metric name and labels according to templates
+// as the Graphite input of InfluxDB, see
+// https://github.com/influxdata/influxdb/tree/v1.8.0/services/graphite#templates.
+//
+// Each template is a string with up to 3 parts separated by spaces:
+//
+// 	[filter] <template> [default_labels]
+//
+// The filter selects the paths to which the template is applied, each part of
+// the filter is either a literal or "*" that matches any part of the path. A
+// path matching multiple filters uses the most specific filter: the parts of
+// the path are compared from left to right and, at each part, a literal is
+// preferred over a wildcard even if the filter going through the wildcard is
+// longer, e.g. "a.b" is used over "a.*.c" for "a.b.c". Among the filters
+// sharing the same literals and wildcards, the longer one is preferred. Paths
+// longer than a filter are also matched by it. A single template can be specified
+// without filter, it is used for the paths not matching any filter. If no
+// template applies the metric is then processed by the "plaintext" parser.
+//
+// The template describes each part of the path:
+//   - "measurement": the part is used in the metric name;
+//   - "measurement*": the part and all the remaining ones are used in the
+//     metric name;
+//   - "field" and "field*": as "measurement" and "measurement*" but appended to
+//     the metric name after all measurement parts;
+//   - "": the part is ignored;
+//   - anything else: the name of the label that gets the part as its value.
+//
+// The default labels are a comma separated list of "key=value" added to the
+// metrics using the template, unless the label is set from the path.
+//
+// Examples:
+//
+// 1. Template: "servers.* .host.measurement.field*"
+//    Metric path: "servers.host01.cpu.user.seconds"
+//    Resulting metric:
+//        name: cpu.user.seconds
+//        label keys: {"host"}
+//        label values: {"host01"}
+//
+// 2. Template: "env.host.measurement* region=us-west"
+//    Metric path: "prod.host02.disk.free"
+//    Resulting metric:
+//        name: disk.free
+//        label keys: {"env", "host", "region"}
+//        label values: {"prod", "host02", "us-west"}
+//
+type TemplateParserConfig struct {
+	// Templates used by the parser, see above for their format.
+	Templates []string `mapstructure:"templates"`
+
+	// MetricNameSeparator is used when joining the parts of the path used in
+	// the metric name, the default is ".".
+	MetricNameSeparator string `mapstructure:"name_separator"`
+}
+
+var _ (ParserConfig) = (*TemplateParserConfig)(nil)
+
+// BuildParser builds the respective parser of the configuration instance.
+func (tpc *TemplateParserConfig) BuildParser() (Parser, error) {
+	if tpc == nil {
+		return nil, errors.New("nil receiver on TemplateParserConfig.BuildParser")
+	}
+
+	if len(tpc.Templates) == 0 {
+		return nil, errors.New(`no template was specified`)
+	}
+
+	tpp := &templatePathParser{
+		root:                &templateNode{},
+		metricNameSeparator: tpc.MetricNameSeparator,
+	}
+	for i, s := range tpc.Templates {
+		filter, tmpl, err := parseTemplate(s)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %d-th template: %v", i, err)
+		}
+		if err := tpp.root.insert(filter, tmpl); err != nil {
+			return nil, fmt.Errorf("error parsing %d-th template: %v", i, err)
+		}
+	}
+
+	return NewParser(tpp)
+}
+
+// template is a parsed template.
+type template struct {
+	parts       []string
+	labelKeys   []string
+	labelValues []string
+}
+
+// parseTemplate parses a template string returning its filter, nil if the
+// template has no filter.
+func parseTemplate(s string) ([]string, *template, error) {
+	fields := strings.Fields(s)
+	var filter, tags string
+	var tmpl string
+	switch len(fields) {
+	case 1:
+		tmpl = fields[0]
+	case 2:
+		if strings.Contains(fields[1], "=") {
+			tmpl, tags = fields[0], fields[1]
+		} else {
+			filter, tmpl = fields[0], fields[1]
+		}
+	case 3:
+		filter, tmpl, tags = fields[0], fields[1], fields[2]
+	default:
+		return nil, nil, fmt.Errorf("invalid template %q", s)
+	}
+
+	t := &template{
+		parts: strings.Split(tmpl, "."),
+	}
+	hasName := false
+	for i, p := range t.parts {
+		switch p {
+		case templateMeasurementGreedy, templateFieldGreedy:
+			if i != len(t.parts)-1 {
+				return nil, nil, fmt.Errorf("invalid template %q: %q must be the last part", s, p)
+			}
+			hasName = true
+		case templateMeasurement, templateField:
+			hasName = true
+		case "":
+		default:
+			if strings.Contains(p, templateWildcard) {
+				return nil, nil, fmt.Errorf("invalid template %q: unexpected %q in label %q", s, templateWildcard, p)
+			}
+		}
+	}
+	if !hasName {
+		return nil, nil, fmt.Errorf("invalid template %q: no measurement or field part", s)
+	}
+
+	if tags != "" {
+		labels := make(map[string]string)
+		for _, tag := range strings.Split(tags, ",") {
+			idx := strings.IndexByte(tag, '=')
+			if idx < 1 {
+				return nil, nil, fmt.Errorf("invalid template %q: incorrect default label %q", s, tag)
+			}
+			labels[tag[:idx]] = tag[idx+1:]
+		}
+		for k := range labels {
+			t.labelKeys = append(t.labelKeys, k)
+		}
+		// Sort the labels so they are always added in the same order.
+		sort.Strings(t.labelKeys)
+		for _, k := range t.labelKeys {
+			t.labelValues = append(t.labelValues, labels[k])
+		}
+	}
+
+	if filter == "" {
+		return nil, t, nil
+	}
+	return strings.Split(filter, "."), t, nil
+}
+
+// templateNode is a node of the prefix tree of template filters, each node
+// corresponds to a part of the filters.
+type templateNode struct {
+	children map[string]*templateNode
+	wildcard *templateNode
+	// template is set if a filter ends on this node.
+	template *template
+}
+
+func (n *templateNode) insert(filter []string, t *template) error {
+	for _, part := range filter {
+		if part == "" {
+			return errors.New("empty part on template filter")
+		}
+		if part == templateWildcard {
+			if n.wildcard == nil {
+				n.wildcard = &templateNode{}
+			}
+			n = n.wildcard
+			continue
+		}
+		if n.children == nil {
+			n.children = make(map[string]*templateNode)
+		}
+		child, ok := n.children[part]
+		if !ok {
+			child = &templateNode{}
+			n.children[part] = child
+		}
+		n = child
+	}
+
+	if n.template != nil {
+		if len(filter) == 0 {
+			return errors.New("multiple templates without filter")
+		}
+		return fmt.Errorf("duplicated filter %q", strings.Join(filter, "."))
+	}
+	n.template = t
+	return nil
+}
+
+// match returns the template of the most specific filter matching the path
+// parts and how many parts the filter had, nil if no filter matches. The
+// literal child is followed first, and the wildcard one only if no filter
+// matches through the literal, so that literals take precedence over wildcards
+// regardless of the length of the filters.
+func (n *templateNode) match(parts []string) (*template, int) {
+	if len(parts) > 0 {
+		for _, child := range []*templateNode{n.children[parts[0]], n.wildcard} {
+			if child == nil {
+				continue
+			}
+			if t, depth := child.match(parts[1:]); t != nil {
+				return t, depth + 1
+			}
+		}
+	}
+	if n.template != nil {
+		return n.template, 0
+	}
+	return nil, -1
+}
+
+type templatePathParser struct {
+	root *templateNode
+
+	metricNameSeparator string
+
+	// plaintextParser is used if no template applies to a given metric.
+	plaintextPathParser PlaintextPathParser
+}
+
+var _ (PathParser) = (*templatePathParser)(nil)
+
+// ParsePath converts the <metric_path> of a Carbon line (see PathParserHelper
+// a full description of the line format) according to the TemplateParserConfig
+// settings.
+func (tpp *templatePathParser) ParsePath(path string, parsedPath *ParsedPath) error {
+	// Tags in the Carbon format, ie.: "<path>;k0=v0;k1=v1", are also supported.
+	name := path
+	var tagsParsedPath ParsedPath
+	if idx := strings.IndexByte(path, ';'); idx >= 0 {
+		if err := tpp.plaintextPathParser.ParsePath(path, &tagsParsedPath); err != nil {
+			return err
+		}
+		name = path[:idx]
+	}
+
+	parts := strings.Split(name, ".")
+	tmpl, _ := tpp.root.match(parts)
+	if tmpl == nil {
+		return tpp.plaintextPathParser.ParsePath(path, parsedPath)
+	}
+
+	var measurement, field []string
+	keys := make([]*metricspb.LabelKey, 0, len(tmpl.parts)+len(tmpl.labelKeys))
+	values := make([]*metricspb.LabelValue, 0, len(tmpl.parts)+len(tmpl.labelKeys))
+	labelIndex := make(map[string]int)
+	for i := 0; i < len(tmpl.parts) && i < len(parts); i++ {
+		switch p := tmpl.parts[i]; p {
+		case templateMeasurement:
+			measurement = append(measurement, parts[i])
+		case templateMeasurementGreedy:
+			measurement = append(measurement, parts[i:]...)
+		case templateField:
+			field = append(field, parts[i])
+		case templateFieldGreedy:
+			field = append(field, parts[i:]...)
+		case "":
+		default:
+			if idx, ok := labelIndex[p]; ok {
+				// The label is used multiple times, join the values.
+				values[idx].Value += tpp.metricNameSeparator + parts[i]
+				continue
+			}
+			labelIndex[p] = len(keys)
+			keys = append(keys, &metricspb.LabelKey{Key: p})
+			values = append(values, &metricspb.LabelValue{
+				Value:    parts[i],
+				HasValue: true,
+			})
+		}
+	}
+
+	for i, k := range tmpl.labelKeys {
+		if _, ok := labelIndex[k]; ok {
+			continue
+		}
+		keys = append(keys, &metricspb.LabelKey{Key: k})
+		values = append(values, &metricspb.LabelValue{
+			Value:    tmpl.labelValues[i],
+			HasValue: true,
+		})
+	}
+
+	metricName := strings.Join(append(measurement, field...), tpp.metricNameSeparator)
+	if metricName == "" {
+		metricName = name
+	}
+
+	parsedPath.MetricName = metricName
+	parsedPath.LabelKeys = append(keys, tagsParsedPath.LabelKeys...)
+	parsedPath.LabelValues = append(values, tagsParsedPath.LabelValues...)
+	return nil
+}
+
+func templateDefaultConfig() ParserConfig {
+	return &TemplateParserConfig{
+		MetricNameSeparator: templateNameSeparatorDefault,
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_templateParser_BuildParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *TemplateParserConfig
+		wantErr bool
+	}{
+		{
+			name:    "nil_config",
+			wantErr: true,
+		},
+		{
+			name:    "no_templates",
+			config:  &TemplateParserConfig{},
+			wantErr: true,
+		},
+		{
+			name: "too_many_fields",
+			config: &TemplateParserConfig{
+				Templates: []string{"a.* measurement.field k=v extra"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no_measurement",
+			config: &TemplateParserConfig{
+				Templates: []string{"a.* host.env"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "greedy_not_last",
+			config: &TemplateParserConfig{
+				Templates: []string{"measurement*.host"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_default_label",
+			config: &TemplateParserConfig{
+				Templates: []string{"measurement* k=v,=x"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated_filter",
+			config: &TemplateParserConfig{
+				Templates: []string{"a.* measurement*", "a.* host.measurement*"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "multiple_without_filter",
+			config: &TemplateParserConfig{
+				Templates: []string{"measurement*", "host.measurement*"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty_filter_part",
+			config: &TemplateParserConfig{
+				Templates: []string{"a..b measurement*"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid_templates",
+			config: &TemplateParserConfig{
+				Templates: []string{
+					"servers.* .host.measurement.field*",
+					"env.host.measurement* region=us-west,zone=1a",
+					"stats.* .measurement*",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.BuildParser()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.NotNil(t, got)
+		})
+	}
+}
+
+func Test_templateParser_parsePath(t *testing.T) {
+	config := &TemplateParserConfig{
+		Templates: []string{
+			"servers.* .host.measurement.field*",
+			"servers.*.cpu .host.measurement.cpu.field",
+			"*.*.rpc svc.host.measurement.field k=v",
+			"stats.*.*.*.requests .svc.dc.dc.measurement",
+			"env.host.measurement* region=us-west,env=default",
+		},
+		MetricNameSeparator: "_",
+	}
+	p, err := config.BuildParser()
+	require.NoError(t, err)
+	tp := p.(*PathParserHelper).pathParser
+
+	tests := []struct {
+		name       string
+		path       string
+		wantName   string
+		wantKeys   []*metricspb.LabelKey
+		wantValues []*metricspb.LabelValue
+		wantErr    bool
+	}{
+		{
+			name:     "prefix_filter",
+			path:     "servers.host01.disk.free.bytes",
+			wantName: "disk_free_bytes",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "host"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host01", HasValue: true},
+			},
+		},
+		{
+			name:     "most_specific_filter",
+			path:     "servers.host01.cpu.1.idle",
+			wantName: "cpu_idle",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "host"},
+				{Key: "cpu"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host01", HasValue: true},
+				{Value: "1", HasValue: true},
+			},
+		},
+		{
+			name:     "wildcard_filter_default_label",
+			path:     "svc_01.host02.rpc.count",
+			wantName: "rpc_count",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "svc"},
+				{Key: "host"},
+				{Key: "k"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "svc_01", HasValue: true},
+				{Value: "host02", HasValue: true},
+				{Value: "v", HasValue: true},
+			},
+		},
+		{
+			name:     "repeated_label",
+			path:     "stats.frontend.us.west.requests",
+			wantName: "requests",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "svc"},
+				{Key: "dc"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "frontend", HasValue: true},
+				{Value: "us_west", HasValue: true},
+			},
+		},
+		{
+			name:     "default_template",
+			path:     "prod.host03.mem.used",
+			wantName: "mem_used",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "env"},
+				{Key: "host"},
+				{Key: "region"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "prod", HasValue: true},
+				{Value: "host03", HasValue: true},
+				{Value: "us-west", HasValue: true},
+			},
+		},
+		{
+			name:     "short_path",
+			path:     "prod",
+			wantName: "prod",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "env"},
+				{Key: "region"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "prod", HasValue: true},
+				{Value: "us-west", HasValue: true},
+			},
+		},
+		{
+			name:     "carbon_tags",
+			path:     "servers.host01.disk.free;dev=sda1",
+			wantName: "disk_free",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "host"},
+				{Key: "dev"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "host01", HasValue: true},
+				{Value: "sda1", HasValue: true},
+			},
+		},
+		{
+			name:    "invalid_carbon_tags",
+			path:    "servers.host01.disk.free;dev",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsedPath{}
+			err := tp.ParsePath(tt.path, &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, got.MetricName)
+			assert.Equal(t, tt.wantKeys, got.LabelKeys)
+			assert.Equal(t, tt.wantValues, got.LabelValues)
+		})
+	}
+}
+
+func Test_templateParser_literalOverWildcard(t *testing.T) {
+	p, err := (&TemplateParserConfig{
+		Templates: []string{
+			"app.api .service.measurement*",
+			"app.*.http .service.protocol.measurement*",
+		},
+		MetricNameSeparator: "_",
+	}).BuildParser()
+	require.NoError(t, err)
+	tp := p.(*PathParserHelper).pathParser
+
+	// the literal "api" is preferred over the wildcard of the longer filter
+	got := ParsedPath{}
+	require.NoError(t, tp.ParsePath("app.api.http.requests", &got))
+	assert.Equal(t, "http_requests", got.MetricName)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "service"}}, got.LabelKeys)
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "api", HasValue: true}}, got.LabelValues)
+
+	got = ParsedPath{}
+	require.NoError(t, tp.ParsePath("app.web.http.requests", &got))
+	assert.Equal(t, "requests", got.MetricName)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "service"}, {Key: "protocol"}}, got.LabelKeys)
+	assert.Equal(t, []*metricspb.LabelValue{
+		{Value: "web", HasValue: true},
+		{Value: "http", HasValue: true},
+	}, got.LabelValues)
+}
+
+func Test_templateParser_noDefaultTemplate(t *testing.T) {
+	p, err := (&TemplateParserConfig{
+		Templates: []string{"servers.* .host.measurement*"},
+	}).BuildParser()
+	require.NoError(t, err)
+
+	// Paths not matching any template are handled by the plaintext parser.
+	got, err := p.Parse("other.host01.cpu;k=v 1 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, buildMetric(
+		metricspb.MetricDescriptor_GAUGE_INT64,
+		"other.host01.cpu",
+		[]string{"k"},
+		[]string{"v"},
+		&metricspb.Point{
+			Timestamp: convertUnixSec(1582230020),
+			Value:     &metricspb.Point_Int64Value{Int64Value: 1},
+		},
+	), got)
+}
+
+func Benchmark_templatePathParser_ParsePath(b *testing.B) {
+	config := &TemplateParserConfig{
+		Templates: []string{
+			"*.*.cpu.seconds svc.host.measurement.field k=v",
+			"*.*.rpc.count svc.host.measurement.field",
+			"svc.host.measurement.field",
+		},
+	}
+	p, err := config.BuildParser()
+	require.NoError(b, err)
+	tp := p.(*PathParserHelper).pathParser
+
+	tests := []string{
+		"service_name.host01.rpc.duration.seconds",
+		"service_name.host00.cpu.seconds",
+		"service_name.host01.rpc.count",
+		"svc_02.host02.avg.duration",
+	}
+
+	got := ParsedPath{}
+	err = tp.ParsePath(tests[0], &got)
+	res.name = got.MetricName
+	res.keys = got.LabelKeys
+	res.values = got.LabelValues
+	res.metricType = got.MetricType
+	res.err = err
+
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < len(tests); i++ {
+			err = tp.ParsePath(tests[i], &got)
+		}
+	}
+
+	res.name = got.MetricName
+	res.keys = got.LabelKeys
+	res.values = got.LabelValues
+	res.metricType = got.MetricType
+	res.err = err
+}
@@ -76,7 +76,7 @@ func New(
 		}
 	}
 
-	parser, err := config.Parser.Config.BuildParser()
+	parser, err := config.Parser.BuildParser()
 	if err != nil {
 		return nil, err
 	}
@@ -103,9 +103,14 @@ func New(
 func buildTransportServer(config Config, logger *zap.Logger) (transport.Server, error) {
 	switch strings.ToLower(config.Transport) {
 	case "", "tcp":
-		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
+		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout, config.TLSSetting)
 	case "udp":
+		if config.TLSSetting != nil {
+			return nil, fmt.Errorf("TLS is not supported by transport %q for receiver %q", config.Transport, config.Name())
+		}
 		return transport.NewUDPServer(config.Endpoint)
+	case "pickle":
+		return transport.NewPickleServer(config.Endpoint, config.TCPIdleTimeout, config.TLSSetting)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.Transport, config.Name())
@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testutil"
@@ -133,6 +134,27 @@ func Test_carbonreceiver_New(t *testing.T) {
 				nextConsumer: new(exportertest.SinkMetricsExporterOld),
 			},
 		},
+		{
+			name: "udp_with_tls",
+			args: args{
+				config: Config{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						NameVal: "udp_with_tls",
+					},
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "localhost:2003",
+						Transport: "udp",
+					},
+					TLSSetting: &configtls.TLSServerSetting{},
+					Parser: &protocol.Config{
+						Type:   "plaintext",
+						Config: &protocol.PlaintextConfig{},
+					},
+				},
+				nextConsumer: new(exportertest.SinkMetricsExporterOld),
+			},
+			wantErr: errors.New("TLS is not supported by transport \"udp\" for receiver \"udp_with_tls\""),
+		},
 		{
 			name: "negative_tcp_idle_timeout",
 			args: args{
@@ -205,6 +227,19 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "default_config_pickle",
+			configFn: func() *Config {
+				cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+				cfg.Transport = "pickle"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.Graphite {
+				c, err := client.NewGraphite(client.Pickle, host, port)
+				require.NoError(t, err)
+				return c
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// pickleMetrics encodes the metrics as a message of the Carbon pickle
+// protocol: the length of the payload as a 4-byte big-endian integer followed
+// by the list of (path, (timestamp, value)) tuples pickled using the protocol
+// version 2.
+func pickleMetrics(metrics []Metric) []byte {
+	payload := []byte{
+		0x80, 0x02, // PROTO 2
+		']', // EMPTY_LIST
+		'(', // MARK
+	}
+	buf := make([]byte, 8)
+	for _, m := range metrics {
+		// BINUNICODE
+		payload = append(payload, 'X')
+		binary.LittleEndian.PutUint32(buf, uint32(len(m.Name)))
+		payload = append(payload, buf[:4]...)
+		payload = append(payload, m.Name...)
+		// BINFLOAT for both the timestamp and the value
+		payload = append(payload, 'G')
+		binary.BigEndian.PutUint64(buf, math.Float64bits(float64(m.Timestamp.Unix())))
+		payload = append(payload, buf...)
+		payload = append(payload, 'G')
+		binary.BigEndian.PutUint64(buf, math.Float64bits(m.Value))
+		payload = append(payload, buf...)
+		// TUPLE2 twice
+		payload = append(payload, 0x86, 0x86)
+	}
+	payload = append(payload,
+		'e', // APPENDS
+		'.', // STOP
+	)
+
+	msg := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(msg, uint32(len(payload)))
+	return append(msg, payload...)
+}
@@ -34,6 +34,8 @@ type Graphite struct {
 	Port    int
 	Timeout time.Duration
 	Conn    io.Writer
+	// pickle is set when the metrics are sent using the pickle protocol.
+	pickle bool
 }
 
 // Transport is used as an enum to select the type of transport to be used.
@@ -42,9 +44,11 @@ type Transport int
 const (
 	defaultTimeout = 5
 
-	// Available transport options: TCP and UDP.
+	// Available transport options: TCP, UDP and Pickle, the latter uses the
+	// pickle protocol over TCP.
 	TCP Transport = iota
 	UDP
+	Pickle
 )
 
 // NewGraphite is a method that's used to create a new Graphite instance.
@@ -68,14 +72,15 @@ func (g *Graphite) connect(transport Transport) error {
 		cl.Close()
 	}
 
-	address := fmt.Sprintf("%s:%d", g.Host, g.Port)
+	address := net.JoinHostPort(g.Host, strconv.Itoa(g.Port))
 	if g.Timeout == 0 {
 		g.Timeout = defaultTimeout * time.Second
 	}
 
 	var err error
 	switch transport {
-	case TCP:
+	case TCP, Pickle:
+		g.pickle = transport == Pickle
 		g.Conn, err = net.DialTimeout("tcp", address, g.Timeout)
 	case UDP:
 		var udpAddr *net.UDPAddr
@@ -106,6 +111,9 @@ func (g *Graphite) Disconnect() (err error) {
 // SendMetric method can be used to just pass a metric name and value and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetric(metric Metric) error {
+	if g.pickle {
+		return g.SendMetrics([]Metric{metric})
+	}
 	_, err := fmt.Fprint(g.Conn, metric.String())
 	if err != nil {
 		return err
@@ -116,6 +124,10 @@ func (g *Graphite) SendMetric(metric Metric) error {
 // SendMetrics method can be used to pass a set of metrics and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetrics(metrics []Metric) error {
+	if g.pickle {
+		_, err := g.Conn.Write(pickleMetrics(metrics))
+		return err
+	}
 	sb := strings.Builder{}
 	for i, metric := range metrics {
 		if _, err := sb.WriteString(metric.String()); err != nil {
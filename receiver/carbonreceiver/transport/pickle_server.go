@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// pickleMaxMessageSize is the maximum size of a pickled message, larger
+	// messages are considered invalid and their connections closed. The
+	// value matches the default of Carbon.
+	pickleMaxMessageSize = 1 << 20
+)
+
+// NewPickleServer creates a transport.Server receiving metrics using the
+// Carbon pickle protocol over TCP: each message is a pickled list of
+// (path, (timestamp, value)) tuples, prefixed by its length as a 4-byte
+// big-endian integer. If tlsSetting is not nil the connections are secured
+// with TLS.
+func NewPickleServer(
+	addr string,
+	idleTimeout time.Duration,
+	tlsSetting *configtls.TLSServerSetting,
+) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout, tlsSetting)
+	if err != nil {
+		return nil, err
+	}
+	t.handleConn = t.handlePickleConnection
+	return t, nil
+}
+
+func (t *tcpServer) handlePickleConnection(
+	p protocol.Parser,
+	nextConsumer consumer.MetricsConsumerOld,
+	conn net.Conn,
+) {
+	defer conn.Close()
+	header := make([]byte, 4)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - conn.SetDeadLine error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		// Connections are closed on any read error, including the idle
+		// timeout, since there is no way to resynchronize with the stream.
+		if _, err := io.ReadFull(conn, header); err != nil {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size > pickleMaxMessageSize {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - pickled message of %d bytes exceeds the limit of %d bytes",
+				t.ln.Addr(),
+				size,
+				pickleMaxMessageSize)
+			return
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		if err := t.processPickle(p, nextConsumer, payload); err != nil {
+			// As for the plaintext protocol, close the connection to report
+			// the error back to the client.
+			return
+		}
+	}
+}
+
+// processPickle passes the metrics of a pickled message to the next consumer,
+// returning the error of the consumer if any.
+func (t *tcpServer) processPickle(
+	p protocol.Parser,
+	nextConsumer consumer.MetricsConsumerOld,
+	payload []byte,
+) error {
+	ctx := t.reporter.OnDataReceived(context.Background())
+	lines, err := protocol.PickleToLines(payload)
+	if err != nil {
+		t.reporter.OnTranslationError(ctx, err)
+		t.reporter.OnMetricsProcessed(ctx, 0, 0, nil)
+		return nil
+	}
+
+	var numInvalidTimeSeries int
+	metrics := make([]*metricspb.Metric, 0, len(lines))
+	for _, line := range lines {
+		metric, err := p.Parse(line)
+		if err != nil {
+			numInvalidTimeSeries++
+			t.reporter.OnTranslationError(ctx, fmt.Errorf("invalid pickled metric: %v", err))
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(metrics) > 0 {
+		md := consumerdata.MetricsData{
+			Metrics: metrics,
+		}
+		err = nextConsumer.ConsumeMetricsData(ctx, md)
+	}
+	t.reporter.OnMetricsProcessed(ctx, len(lines), numInvalidTimeSeries, err)
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+// pickledRPCCount is [("service_name.host01.rpc.count", (1582230020, 42))]
+// pickled in Python using the protocol 2.
+const pickledRPCCount = "\x80\x02]q\x00X\x1d\x00\x00\x00service_name.host01.rpc.countq\x01J\x04\xeaN^K*\x86q\x02\x86q\x03a."
+
+func Test_PickleServer_RegexParser(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second, nil)
+	require.NoError(t, err)
+
+	p, err := (&protocol.RegexParserConfig{
+		Rules: []*protocol.RegexRule{
+			{
+				Regexp:     `^(?P<key_svc>[^.]+)\.(?P<key_host>[^.]+)\.(?P<name_0>[^.]+)\.(?P<name_1>[^.]+)$`,
+				NamePrefix: "pickled",
+			},
+		},
+		MetricNameSeparator: "_",
+	}).BuildParser()
+	require.NoError(t, err)
+
+	mc := &mockMetricsConsumer{}
+	// One call for the invalid message and another one for the valid message.
+	mr := NewMockReporter(2)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// An invalid message is dropped without closing the connection.
+	_, err = conn.Write(pickleMessage("\x80\x02}q\x00."))
+	require.NoError(t, err)
+	_, err = conn.Write(pickleMessage(pickledRPCCount))
+	require.NoError(t, err)
+
+	mr.WaitAllOnMetricsProcessedCalls()
+	require.NoError(t, svr.Close())
+	wg.Wait()
+
+	require.Equal(t, 1, len(mc.md))
+	require.Equal(t, 1, len(mc.md[0].Metrics))
+	metric := mc.md[0].Metrics[0]
+	assert.Equal(t, "pickled_rpc_count", metric.GetMetricDescriptor().GetName())
+	assert.Equal(t, 2, len(metric.GetMetricDescriptor().GetLabelKeys()))
+	require.Equal(t, 1, len(metric.GetTimeseries()))
+	assert.Equal(t, int64(42), metric.GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+}
+
+func Test_PickleServer_MessageTooLarge(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second, nil)
+	require.NoError(t, err)
+
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, svr.ListenAndServe(p, &mockMetricsConsumer{}, NewMockReporter(0)))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, pickleMaxMessageSize+1)
+	_, err = conn.Write(header)
+	require.NoError(t, err)
+
+	// The server closes the connection instead of reading the message.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	require.NoError(t, svr.Close())
+	wg.Wait()
+}
+
+func pickleMessage(payload string) []byte {
+	msg := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(msg, uint32(len(payload)))
+	return append(msg, payload...)
+}
@@ -45,6 +45,25 @@ type Server interface {
 	Close() error
 }
 
+// LineHandler handles a line received by a LineServer. The connection on which
+// the line was received is closed if it returns an error.
+type LineHandler func(line string) error
+
+// LineServer is a Server for text protocols that send other data than metrics
+// on the same connections: instead of parsing the lines as metrics it passes
+// them to a LineHandler, which is responsible for processing and reporting
+// them.
+type LineServer interface {
+	// ListenAndServe is a blocking call that starts to listen for client
+	// messages on the specific transport and passes each line received to
+	// handleLine. The Reporter is only used for the transport events.
+	ListenAndServe(handleLine LineHandler, r Reporter) error
+
+	// Close stops any running ListenAndServe, however, it waits for any
+	// line already received to be handled.
+	Close() error
+}
+
 // Reporter is used to report (via zPages, logs, metrics, etc) the events
 // happening when the Server is receiving and processing data.
 type Reporter interface {
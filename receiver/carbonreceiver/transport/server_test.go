@@ -16,6 +16,8 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net"
 	"runtime"
 	"strconv"
@@ -41,7 +43,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		{
 			name: "tcp",
 			buildServerFn: func(addr string) (Server, error) {
-				return NewTCPServer(addr, 1*time.Second)
+				return NewTCPServer(addr, 1*time.Second, nil)
 			},
 			buildClientFn: func(host string, port int) (*client.Graphite, error) {
 				return client.NewGraphite(client.TCP, host, port)
@@ -56,6 +58,15 @@ func Test_Server_ListenAndServe(t *testing.T) {
 				return client.NewGraphite(client.UDP, host, port)
 			},
 		},
+		{
+			name: "pickle",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewPickleServer(addr, 1*time.Second, nil)
+			},
+			buildClientFn: func(host string, port int) (*client.Graphite, error) {
+				return client.NewGraphite(client.Pickle, host, port)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -109,6 +120,48 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 }
 
+func Test_LineServer_ListenAndServe(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewTCPLineServer(addr, 1*time.Second, nil)
+	require.NoError(t, err)
+	require.NotNil(t, svr)
+
+	var mu sync.Mutex
+	var lines []string
+	handleLine := func(line string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+		if line == "close" {
+			return errors.New("close the connection")
+		}
+		return nil
+	}
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(handleLine, NewMockReporter(0)))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("first line\n\n  second line  \nclose\nignored\n"))
+	require.NoError(t, err)
+
+	// The connection is closed by the server once the handler fails.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	assert.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+
+	assert.Equal(t, []string{"first line", "second line", "close"}, lines)
+}
+
 type mockMetricsConsumer struct {
 	sync.Mutex
 	md []consumerdata.MetricsData
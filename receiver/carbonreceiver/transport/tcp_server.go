@@ -17,15 +17,15 @@ package transport
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"fmt"
-	"io"
 	"net"
 	"strings"
 	"sync"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	"go.opencensus.io/trace"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 
@@ -42,15 +42,54 @@ type tcpServer struct {
 	wg          sync.WaitGroup
 	idleTimeout time.Duration
 	reporter    Reporter
+	// handleConn reads the metrics sent on an accepted connection according
+	// to the protocol served.
+	handleConn func(p protocol.Parser, nextConsumer consumer.MetricsConsumerOld, conn net.Conn)
 }
 
 var _ (Server) = (*tcpServer)(nil)
 
-// NewTCPServer creates a transport.Server using TCP as its transport.
+// NewTCPServer creates a transport.Server using TCP as its transport. If
+// tlsSetting is not nil the connections are secured with TLS.
 func NewTCPServer(
 	addr string,
 	idleTimeout time.Duration,
+	tlsSetting *configtls.TLSServerSetting,
 ) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout, tlsSetting)
+	if err != nil {
+		return nil, err
+	}
+	t.handleConn = t.handleConnection
+	return t, nil
+}
+
+// tcpLineServer passes the lines received by a tcpServer to a LineHandler.
+type tcpLineServer struct {
+	*tcpServer
+}
+
+var _ (LineServer) = (*tcpLineServer)(nil)
+
+// NewTCPLineServer creates a transport.LineServer using TCP as its transport.
+// If tlsSetting is not nil the connections are secured with TLS.
+func NewTCPLineServer(
+	addr string,
+	idleTimeout time.Duration,
+	tlsSetting *configtls.TLSServerSetting,
+) (LineServer, error) {
+	t, err := newTCPServer(addr, idleTimeout, tlsSetting)
+	if err != nil {
+		return nil, err
+	}
+	return &tcpLineServer{t}, nil
+}
+
+func newTCPServer(
+	addr string,
+	idleTimeout time.Duration,
+	tlsSetting *configtls.TLSServerSetting,
+) (*tcpServer, error) {
 	if idleTimeout < 0 {
 		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
 	}
@@ -59,15 +98,33 @@ func NewTCPServer(
 		idleTimeout = TCPIdleTimeoutDefault
 	}
 
+	t := tcpServer{
+		idleTimeout: idleTimeout,
+	}
+
+	var tlsReloader *tlsConfigReloader
+	if tlsSetting != nil {
+		var err error
+		tlsReloader, err = newTLSConfigReloader(tlsSetting, func(err error) {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - failed to reload TLS config: %v",
+				t.ln.Addr(),
+				err)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, err
 	}
-
-	t := tcpServer{
-		ln:          ln,
-		idleTimeout: idleTimeout,
+	if tlsReloader != nil {
+		ln = tls.NewListener(ln, tlsReloader.tlsConfig())
 	}
+
+	t.ln = ln
 	return &t, nil
 }
 
@@ -80,6 +137,14 @@ func (t *tcpServer) ListenAndServe(
 		return errNilListenAndServeParameters
 	}
 
+	return t.serve(reporter, func(conn net.Conn) {
+		t.handleConn(parser, nextConsumer, conn)
+	})
+}
+
+// serve accepts the connections, each one handled by handleConn on its own
+// goroutine, until the listener is closed.
+func (t *tcpServer) serve(reporter Reporter, handleConn func(conn net.Conn)) error {
 	acceptedConnMap := make(map[net.Conn]struct{})
 	connMapMtx := &sync.Mutex{}
 
@@ -93,7 +158,7 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				handleConn(c)
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
@@ -132,6 +197,16 @@ func (t *tcpServer) ListenAndServe(
 	return err
 }
 
+func (t *tcpLineServer) ListenAndServe(handleLine LineHandler, reporter Reporter) error {
+	if handleLine == nil || reporter == nil {
+		return errNilListenAndServeParameters
+	}
+
+	return t.serve(reporter, func(conn net.Conn) {
+		t.readLines(conn, handleLine)
+	})
+}
+
 func (t *tcpServer) Close() error {
 	err := t.ln.Close()
 	t.wg.Wait()
@@ -143,15 +218,30 @@ func (t *tcpServer) handleConnection(
 	nextConsumer consumer.MetricsConsumerOld,
 	conn net.Conn,
 ) {
+	t.readLines(conn, func(line string) error {
+		ctx := t.reporter.OnDataReceived(context.Background())
+		metric, err := p.Parse(line)
+		if err != nil {
+			t.reporter.OnTranslationError(ctx, err)
+			return nil
+		}
+
+		md := consumerdata.MetricsData{
+			Metrics: []*metricspb.Metric{metric},
+		}
+		err = nextConsumer.ConsumeMetricsData(ctx, md)
+		t.reporter.OnMetricsProcessed(ctx, 1, 0, err)
+		return err
+	})
+}
+
+// readLines passes each line received on conn to handleLine, until the
+// connection is closed by the client, becomes idle or handleLine returns an
+// error.
+func (t *tcpServer) readLines(conn net.Conn, handleLine LineHandler) {
 	defer conn.Close()
-	var span *trace.Span
 	reader := bufio.NewReader(conn)
 	for {
-		if span != nil {
-			span.End()
-			span = nil
-		}
-
 		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
 			t.reporter.OnDebugf(
 				"TCP Transport (%s) - conn.SetDeadLine error: %v",
@@ -172,30 +262,13 @@ func (t *tcpServer) handleConnection(
 		bytes, err := reader.ReadBytes((byte)('\n'))
 
 		// It is possible to have new data in bytes and err to be io.EOF
-		ctx := t.reporter.OnDataReceived(context.Background())
-		var numReceivedTimeSeries, numInvalidTimeSeries int
 		line := strings.TrimSpace(string(bytes))
 		if line != "" {
-			numReceivedTimeSeries++
-			var metric *metricspb.Metric
-			metric, err = p.Parse(line)
-			if err != nil {
-				numInvalidTimeSeries++
-				t.reporter.OnTranslationError(ctx, err)
-				continue
-			}
-
-			md := consumerdata.MetricsData{
-				Metrics: []*metricspb.Metric{metric},
-			}
-			err = nextConsumer.ConsumeMetricsData(ctx, md)
-			t.reporter.OnMetricsProcessed(ctx, numReceivedTimeSeries, numInvalidTimeSeries, err)
-			if err != nil {
+			if handleErr := handleLine(line); handleErr != nil {
 				// The protocol doesn't account for returning errors.
 				// Since this is a TCP connection it seems reasonable to close the
 				// connection as a way to report "error" back to client and minimize
 				// the effect of a client constantly submitting bad data.
-				span.End()
 				return
 			}
 		}
@@ -205,19 +278,20 @@ func (t *tcpServer) handleConnection(
 				"TCP Transport (%s) - net.OpError: %v",
 				t.ln.Addr(),
 				netErr)
-			if !netErr.Temporary() || netErr.Timeout() {
-				// We want to end on timeout so idle connections are purged.
-				span.End()
-				return
+			if netErr.Temporary() && !netErr.Timeout() {
+				continue
 			}
+			// We want to end on timeout so idle connections are purged.
+			return
 		}
 
-		if err == io.EOF {
+		// Any other error, e.g.: io.EOF or a failed TLS handshake, also ends
+		// the connection.
+		if err != nil {
 			t.reporter.OnDebugf(
 				"TCP Transport (%s) - error: %v",
 				t.ln.Addr(),
 				err)
-			span.End()
 			return
 		}
 	}
@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// tlsConfigReloader provides the TLS configuration of a server, reloading it
+// whenever the certificate, key or client CA files are modified so renewed
+// certificates are used without restarting the server.
+type tlsConfigReloader struct {
+	setting *configtls.TLSServerSetting
+	// onError is called when reloading the configuration fails, the previous
+	// configuration is kept in that case.
+	onError func(err error)
+
+	mu       sync.Mutex
+	config   *tls.Config
+	modTimes []time.Time
+}
+
+func newTLSConfigReloader(setting *configtls.TLSServerSetting, onError func(err error)) (*tlsConfigReloader, error) {
+	if setting.CertFile == "" || setting.KeyFile == "" {
+		return nil, errors.New("TLS server requires a cert_file and a key_file")
+	}
+
+	r := &tlsConfigReloader{
+		setting: setting,
+		onError: onError,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// tlsConfig returns a tls.Config that gets the current configuration of the
+// reloader on each handshake.
+func (r *tlsConfigReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+// current returns the configuration to use for a new connection, reloading it
+// first if any of its files changed.
+func (r *tlsConfigReloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !timesEqual(r.modTimes, r.fileModTimes()) {
+		if err := r.load(); err != nil {
+			r.onError(err)
+		}
+	}
+	return r.config
+}
+
+// load loads the configuration, it must be called with the lock held.
+func (r *tlsConfigReloader) load() error {
+	// Get the modification times before loading the files so a change while
+	// loading them triggers another reload.
+	modTimes := r.fileModTimes()
+	config, err := r.setting.LoadTLSConfig()
+	if err != nil {
+		return err
+	}
+	r.config = config
+	r.modTimes = modTimes
+	return nil
+}
+
+func (r *tlsConfigReloader) fileModTimes() []time.Time {
+	files := []string{r.setting.CertFile, r.setting.KeyFile, r.setting.CAFile, r.setting.ClientCAFile}
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil {
+			modTimes[i] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+func timesEqual(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
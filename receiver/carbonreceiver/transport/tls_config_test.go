@@ -0,0 +1,160 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/testutil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+func Test_TCPServer_TLS(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewTCPServer(addr, 1*time.Second, &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "testdata/server.crt",
+			KeyFile:  "testdata/server.key",
+		},
+		ClientCAFile: "testdata/ca.crt",
+	})
+	require.NoError(t, err)
+
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	mc := &mockMetricsConsumer{}
+	mr := NewMockReporter(1)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	caPool := x509.NewCertPool()
+	caPEM, err := ioutil.ReadFile("testdata/ca.crt")
+	require.NoError(t, err)
+	require.True(t, caPool.AppendCertsFromPEM(caPEM))
+
+	// Clients without certificate are rejected.
+	conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: caPool})
+	if err == nil {
+		_, err = conn.Write([]byte("test.rejected 1 1582230020\n"))
+		if err == nil {
+			_, err = conn.Read(make([]byte, 1))
+		}
+		conn.Close()
+	}
+	assert.Error(t, err)
+
+	clientCert, err := tls.LoadX509KeyPair("testdata/client.crt", "testdata/client.key")
+	require.NoError(t, err)
+	conn, err = tls.Dial("tcp", addr, &tls.Config{
+		RootCAs:      caPool,
+		Certificates: []tls.Certificate{clientCert},
+	})
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("test.metric 1 1582230020\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	mr.WaitAllOnMetricsProcessedCalls()
+	require.NoError(t, svr.Close())
+	wg.Wait()
+
+	require.Equal(t, 1, len(mc.md))
+	assert.Equal(t, "test.metric", mc.md[0].Metrics[0].GetMetricDescriptor().GetName())
+}
+
+func Test_TCPServer_InvalidTLS(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	_, err := NewTCPServer(addr, 1*time.Second, &configtls.TLSServerSetting{})
+	assert.EqualError(t, err, "TLS server requires a cert_file and a key_file")
+
+	_, err = NewPickleServer(addr, 1*time.Second, &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "testdata/server.crt",
+			KeyFile:  "testdata/missing.key",
+		},
+	})
+	assert.Error(t, err)
+}
+
+func Test_tlsConfigReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "carbon-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	copyFile(t, "testdata/server.crt", certFile)
+	copyFile(t, "testdata/server.key", keyFile)
+
+	var reloadErr error
+	r, err := newTLSConfigReloader(&configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}, func(err error) {
+		reloadErr = err
+	})
+	require.NoError(t, err)
+
+	cert1 := r.current().Certificates[0].Certificate[0]
+	assert.Equal(t, cert1, r.current().Certificates[0].Certificate[0])
+
+	// Replace the certificate, the modification time is moved forward since
+	// the file system may not have enough precision to tell the change.
+	copyFile(t, "testdata/server2.crt", certFile)
+	copyFile(t, "testdata/server2.key", keyFile)
+	touch(t, time.Now().Add(time.Minute), certFile, keyFile)
+
+	cert2 := r.current().Certificates[0].Certificate[0]
+	assert.False(t, bytes.Equal(cert1, cert2))
+	assert.NoError(t, reloadErr)
+
+	// An invalid certificate is reported and the last valid one is kept.
+	require.NoError(t, ioutil.WriteFile(certFile, []byte("invalid"), 0600))
+	touch(t, time.Now().Add(2*time.Minute), certFile)
+
+	assert.Equal(t, cert2, r.current().Certificates[0].Certificate[0])
+	assert.Error(t, reloadErr)
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	b, err := ioutil.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(dst, b, 0600))
+}
+
+func touch(t *testing.T, modTime time.Time, files ...string) {
+	for _, f := range files {
+		require.NoError(t, os.Chtimes(f, modTime, modTime))
+	}
+}
@@ -30,6 +30,10 @@ const (
 	collectDMetricGauge    = "gauge"
 	collectDMetricCounter  = "counter"
 	collectDMetricAbsolute = "absolute"
+
+	// notificationMetricName is the name of the metric the notifications are
+	// converted to when enabled.
+	notificationMetricName = "collectd.notification"
 )
 
 type collectDRecord struct {
@@ -100,6 +104,44 @@ func (r *collectDRecord) appendToMetrics(metrics []*metricspb.Metric, defaultLab
 	return metrics, nil
 }
 
+// appendNotificationToMetrics converts the notification to a gauge with the
+// value 1 and the severity and message of the notification as labels, in
+// addition to the labels of the plugin, host and type.
+func (r *collectDRecord) appendNotificationToMetrics(metrics []*metricspb.Metric, defaultLabels map[string]string) []*metricspb.Metric {
+	recordEventsReceived()
+	labels := make(map[string]string, len(defaultLabels))
+	for k, v := range defaultLabels {
+		labels[k] = v
+	}
+
+	addIfNotNullOrEmpty(labels, "plugin", r.Plugin)
+	parseAndAddLabels(labels, r.PluginInstance, r.Host)
+	addIfNotNullOrEmpty(labels, "type", r.TypeS)
+	parseNameForLabels(labels, "type_instance", r.TypeInstance)
+	addIfNotNullOrEmpty(labels, "severity", r.Severity)
+	addIfNotNullOrEmpty(labels, "message", r.Message)
+
+	lKeys, lValues := labelKeysAndValues(labels)
+	return append(metrics, &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      notificationMetricName,
+			Type:      metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys: lKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: lValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: r.protoTime(),
+						Value:     &metricspb.Point_Int64Value{Int64Value: 1},
+					},
+				},
+			},
+		},
+	})
+}
+
 func (r *collectDRecord) newMetric(name string, dsType *string, val *json.Number, labels map[string]string) (*metricspb.Metric, error) {
 	metric := &metricspb.Metric{}
 	point, isDouble, err := r.newPoint(val)
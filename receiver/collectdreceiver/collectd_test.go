@@ -43,6 +43,46 @@ func TestDecodeEvent(t *testing.T) {
 	}
 }
 
+func TestDecodeNotification(t *testing.T) {
+	jsonData, err := loadFromJSON("./testdata/event.json")
+	require.NoError(t, err)
+
+	records := []collectDRecord{}
+	err = json.Unmarshal(jsonData, &records)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.True(t, records[0].isEvent())
+
+	metrics := records[0].appendNotificationToMetrics(nil, map[string]string{"dap": "v"})
+	require.Len(t, metrics, 1)
+
+	md := metrics[0].MetricDescriptor
+	assert.Equal(t, "collectd.notification", md.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, md.Type)
+
+	ts := metrics[0].Timeseries[0]
+	labels := make(map[string]string)
+	for i, k := range md.LabelKeys {
+		labels[k.Key] = ts.LabelValues[i].Value
+	}
+	assert.Equal(t, map[string]string{
+		"dap":             "v",
+		"plugin":          "my_plugin",
+		"plugin_instance": "my_plugin_instance",
+		"f":               "x",
+		"host":            "mwp-signalbox",
+		"a":               "b",
+		"type":            "imanotify",
+		"type_instance":   "notify_instance",
+		"k":               "v",
+		"severity":        "OKAY",
+		"message":         "my message",
+	}, labels)
+	require.Len(t, ts.Points, 1)
+	assert.Equal(t, &timestamp.Timestamp{Seconds: 1435104306}, ts.Points[0].Timestamp)
+	assert.Equal(t, int64(1), ts.Points[0].GetInt64Value())
+}
+
 func loadFromJSON(path string) ([]byte, error) {
 	var body []byte
 	jsonFile, err := os.Open(path)
@@ -29,4 +29,23 @@ type Config struct {
 	Timeout          time.Duration `mapstructure:"timeout"`
 	AttributesPrefix string        `mapstructure:"attributes_prefix"`
 	Encoding         string        `mapstructure:"encoding"`
+
+	// NotificationsAsMetrics converts the collectd notifications to the
+	// "collectd.notification" gauge with the severity and message as labels,
+	// instead of dropping them.
+	NotificationsAsMetrics bool `mapstructure:"notifications_as_metrics"`
+
+	// The following settings only apply to the "binary" encoding, i.e. the
+	// collectd network protocol received over UDP.
+
+	// SecurityLevel is the minimum security level of the accepted data: "none",
+	// "sign" or "encrypt".
+	SecurityLevel string `mapstructure:"security_level"`
+	// AuthFile is the path to the file with the passwords of the users, one
+	// "<user>: <password>" per line, used to verify signed data and to decrypt
+	// encrypted data.
+	AuthFile string `mapstructure:"auth_file"`
+	// TypesDB are the paths to the types.db files used to name the values of
+	// each type.
+	TypesDB []string `mapstructure:"types_db"`
 }
@@ -40,7 +40,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 3)
 
 	r0 := cfg.Receivers["collectd"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -59,4 +59,22 @@ func TestLoadConfig(t *testing.T) {
 			AttributesPrefix: "dap_",
 			Encoding:         "command",
 		})
+
+	r2 := cfg.Receivers["collectd/binary"].(*Config)
+	assert.Equal(t, r2,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: configmodels.Type(typeStr),
+				NameVal: "collectd/binary",
+			},
+			TCPAddr: confignet.TCPAddr{
+				Endpoint: "localhost:25826",
+			},
+			Timeout:                defaultTimeout,
+			Encoding:               "binary",
+			NotificationsAsMetrics: true,
+			SecurityLevel:          "encrypt",
+			AuthFile:               "./testdata/passwd",
+			TypesDB:                []string{"./testdata/types.db"},
+		})
 }
@@ -23,7 +23,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 )
@@ -35,6 +34,11 @@ const (
 	defaultBindEndpoint   = "localhost:8081"
 	defaultTimeout        = time.Duration(time.Second * 30)
 	defaultEncodingFormat = "json"
+	binaryEncodingFormat  = "binary"
+
+	// defaultNetworkBindEndpoint is the default endpoint of the binary
+	// encoding, the port collectd's network plugin sends to by default.
+	defaultNetworkBindEndpoint = "localhost:25826"
 )
 
 // Factory is the factory for collectd receiver.
@@ -54,15 +58,14 @@ func (f *Factory) CustomUnmarshaler() component.CustomUnmarshaler {
 }
 
 // CreateDefaultConfig creates the default configuration for CollectD receiver.
+// The endpoint is left empty, its default depends on the encoding and is
+// resolved when the receiver is created.
 func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
 	return &Config{
 		ReceiverSettings: configmodels.ReceiverSettings{
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		TCPAddr: confignet.TCPAddr{
-			Endpoint: defaultBindEndpoint,
-		},
 		Timeout:  defaultTimeout,
 		Encoding: defaultEncodingFormat,
 	}
@@ -85,15 +88,23 @@ func (f *Factory) CreateMetricsReceiver(
 	cfg configmodels.Receiver,
 	nextConsumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
-	c := cfg.(*Config)
+	// The defaults are applied to a copy, the config is owned by its loader.
+	c := *cfg.(*Config)
 	c.Encoding = strings.ToLower(c.Encoding)
-	// CollectD receiver only supports JSON encoding. We expose a config option
-	// to make it explicit and obvious to the users.
-	if c.Encoding != defaultEncodingFormat {
-		return nil, fmt.Errorf(
-			"CollectD only support JSON encoding format. %s is not supported",
-			c.Encoding,
-		)
+	switch c.Encoding {
+	case defaultEncodingFormat:
+		if c.Endpoint == "" {
+			c.Endpoint = defaultBindEndpoint
+		}
+		return newCollectdReceiver(logger, &c, nextConsumer)
+	case binaryEncodingFormat:
+		if c.Endpoint == "" {
+			c.Endpoint = defaultNetworkBindEndpoint
+		}
+		return newNetworkReceiver(logger, &c, nextConsumer)
 	}
-	return New(logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	return nil, fmt.Errorf(
+		"CollectD only support JSON and binary encoding formats. %s is not supported",
+		c.Encoding,
+	)
 }
@@ -16,9 +16,12 @@ package collectdreceiver
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -32,6 +35,34 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 }
 
+func TestCreateReceiverDefaultEndpoint(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	r, err := factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, &mockMetricsConsumer{})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8081", r.(*collectdReceiver).addr)
+
+	binaryCfg := factory.CreateDefaultConfig().(*Config)
+	binaryCfg.Encoding = "Binary"
+	r, err = factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), binaryCfg, &mockMetricsConsumer{})
+	require.NoError(t, err)
+	// The config is left untouched.
+	assert.Equal(t, "", binaryCfg.Endpoint)
+	assert.Equal(t, "Binary", binaryCfg.Encoding)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+	assert.Equal(t, 25826, r.(*networkReceiver).conn.LocalAddr().(*net.UDPAddr).Port)
+
+	// An endpoint set explicitly is used whatever the encoding.
+	binaryCfg = factory.CreateDefaultConfig().(*Config)
+	binaryCfg.Encoding = binaryEncodingFormat
+	binaryCfg.Endpoint = "localhost:8081"
+	r, err = factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), binaryCfg, &mockMetricsConsumer{})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8081", r.(*networkReceiver).addr)
+}
+
 type mockMetricsConsumer struct {
 }
 
@@ -51,6 +82,18 @@ func TestCreateReceiver(t *testing.T) {
 	assert.Nil(t, err, "receiver creation failed")
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
+	binaryCfg := factory.CreateDefaultConfig().(*Config)
+	binaryCfg.Encoding = "Binary"
+	tReceiver, err = factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), binaryCfg, &mockMetricsConsumer{})
+	assert.Nil(t, err, "receiver creation failed")
+	assert.IsType(t, &networkReceiver{}, tReceiver)
+
+	invalidCfg := factory.CreateDefaultConfig().(*Config)
+	invalidCfg.Encoding = "command"
+	tReceiver, err = factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), invalidCfg, &mockMetricsConsumer{})
+	assert.Error(t, err)
+	assert.Nil(t, tReceiver)
+
 	mReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, nil)
 	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
 	assert.Nil(t, mReceiver)
@@ -0,0 +1,457 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec collectd uses SHA-1 to verify the integrity of encrypted packets
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Types of the parts of the collectd binary network protocol, see
+// https://collectd.org/wiki/index.php/Binary_protocol.
+const (
+	partHost           = 0x0000
+	partTime           = 0x0001
+	partPlugin         = 0x0002
+	partPluginInstance = 0x0003
+	partType           = 0x0004
+	partTypeInstance   = 0x0005
+	partValues         = 0x0006
+	partInterval       = 0x0007
+	partTimeHR         = 0x0008
+	partIntervalHR     = 0x0009
+	partMessage        = 0x0100
+	partSeverity       = 0x0101
+	partSignSHA256     = 0x0200
+	partEncrAES256     = 0x0210
+
+	partHeaderLen = 4
+
+	// Types of the data sources in a values part.
+	dsTypeCounter  = 0
+	dsTypeGauge    = 1
+	dsTypeDerive   = 2
+	dsTypeAbsolute = 3
+
+	// Notification severities.
+	severityFailure = 1
+	severityWarning = 2
+	severityOkay    = 4
+
+	sha256Len = 32
+	sha1Len   = 20
+)
+
+// Security levels of the collectd network protocol, a packet with a lower
+// security level than the configured one is ignored.
+const (
+	securityLevelNone    = "none"
+	securityLevelSign    = "sign"
+	securityLevelEncrypt = "encrypt"
+)
+
+// securityLevel is the security of a region of a packet: the values are
+// ordered so they can be compared with the minimum level required.
+type securityLevel int
+
+const (
+	securityNone securityLevel = iota
+	securitySigned
+	securityEncrypted
+)
+
+var (
+	errShortPacket    = errors.New("packet too short")
+	errInvalidPartLen = errors.New("invalid part length")
+)
+
+// networkParser decodes the packets sent by the collectd network plugin into
+// collectDRecord.
+type networkParser struct {
+	minSecurity securityLevel
+	// passwords are the passwords by user name read from the auth file, nil
+	// when no auth file is configured.
+	passwords map[string]string
+	// dsNames are the names of the data sources by type read from the types
+	// database files.
+	dsNames map[string][]string
+}
+
+func newNetworkParser(securityLevel, authFile string, typesDBFiles []string) (*networkParser, error) {
+	p := &networkParser{}
+	switch strings.ToLower(securityLevel) {
+	case "", securityLevelNone:
+		p.minSecurity = securityNone
+	case securityLevelSign:
+		p.minSecurity = securitySigned
+	case securityLevelEncrypt:
+		p.minSecurity = securityEncrypted
+	default:
+		return nil, fmt.Errorf(
+			"invalid security level %q, valid levels: %v",
+			securityLevel,
+			[]string{securityLevelNone, securityLevelSign, securityLevelEncrypt})
+	}
+
+	if authFile != "" {
+		passwords, err := loadAuthFile(authFile)
+		if err != nil {
+			return nil, err
+		}
+		p.passwords = passwords
+	} else if p.minSecurity > securityNone {
+		return nil, fmt.Errorf("an auth file is required with security level %q", securityLevel)
+	}
+
+	p.dsNames = make(map[string][]string)
+	for _, file := range typesDBFiles {
+		if err := loadTypesDB(file, p.dsNames); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// loadAuthFile reads the passwords from a file in the format of the collectd
+// auth file: one "<user>: <password>" per line.
+func loadAuthFile(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth file: %v", err)
+	}
+	defer f.Close()
+
+	passwords := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.IndexByte(line, ':')
+		if idx < 1 {
+			return nil, fmt.Errorf("invalid line in auth file %q", file)
+		}
+		passwords[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read auth file: %v", err)
+	}
+	return passwords, nil
+}
+
+// loadTypesDB reads the names of the data sources of each type from a file
+// in the format of the collectd types.db, see
+// https://collectd.org/documentation/manpages/types.db.5.shtml.
+func loadTypesDB(file string, dsNames map[string][]string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to read types database: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var names []string
+		for _, ds := range strings.Split(strings.Join(fields[1:], ""), ",") {
+			if idx := strings.IndexByte(ds, ':'); idx > 0 {
+				names = append(names, ds[:idx])
+			}
+		}
+		dsNames[fields[0]] = names
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read types database: %v", err)
+	}
+	return nil
+}
+
+// parse decodes the records of a packet. Records in a region of the packet
+// with less security than required are ignored.
+func (p *networkParser) parse(packet []byte) ([]collectDRecord, error) {
+	var records []collectDRecord
+	err := p.parseParts(packet, securityNone, &collectDRecord{}, &records)
+	return records, err
+}
+
+func (p *networkParser) parseParts(
+	b []byte,
+	security securityLevel,
+	state *collectDRecord,
+	records *[]collectDRecord,
+) error {
+	for len(b) > 0 {
+		if len(b) < partHeaderLen {
+			return errShortPacket
+		}
+		typ := binary.BigEndian.Uint16(b)
+		partLen := int(binary.BigEndian.Uint16(b[2:]))
+		if partLen < partHeaderLen || partLen > len(b) {
+			return errInvalidPartLen
+		}
+		part := b[partHeaderLen:partLen]
+
+		switch typ {
+		case partSignSHA256:
+			signed, err := p.verifySignature(part, b[partLen:])
+			if err != nil {
+				return err
+			}
+			if signed {
+				security = securitySigned
+			}
+		case partEncrAES256:
+			payload, err := p.decrypt(part)
+			if err != nil {
+				return err
+			}
+			if payload == nil {
+				// Can't be decrypted, ignore it.
+				break
+			}
+			if err := p.parseParts(payload, securityEncrypted, state, records); err != nil {
+				return err
+			}
+
+		case partHost:
+			state.Host = stringPart(part)
+		case partPlugin:
+			state.Plugin = stringPart(part)
+		case partPluginInstance:
+			state.PluginInstance = stringPart(part)
+		case partType:
+			state.TypeS = stringPart(part)
+		case partTypeInstance:
+			state.TypeInstance = stringPart(part)
+		case partTime, partTimeHR:
+			t, err := timePart(part, typ == partTimeHR)
+			if err != nil {
+				return err
+			}
+			state.Time = &t
+		case partInterval, partIntervalHR:
+			t, err := timePart(part, typ == partIntervalHR)
+			if err != nil {
+				return err
+			}
+			state.Interval = &t
+		case partSeverity:
+			if len(part) != 8 {
+				return errInvalidPartLen
+			}
+			severity := severityString(binary.BigEndian.Uint64(part))
+			state.Severity = &severity
+
+		case partValues:
+			if security < p.minSecurity {
+				break
+			}
+			record, err := p.valuesRecord(part, state)
+			if err != nil {
+				return err
+			}
+			*records = append(*records, record)
+		case partMessage:
+			// Notifications are complete once their message is received.
+			if security < p.minSecurity {
+				break
+			}
+			record := *state
+			record.Message = stringPart(part)
+			*records = append(*records, record)
+			state.Message, state.Severity = nil, nil
+		}
+
+		b = b[partLen:]
+	}
+	return nil
+}
+
+// verifySignature checks the HMAC-SHA-256 signature of the rest of the packet,
+// returning false if it can't be verified because no auth file is configured.
+func (p *networkParser) verifySignature(part, rest []byte) (bool, error) {
+	if len(part) < sha256Len {
+		return false, errInvalidPartLen
+	}
+	user := string(part[sha256Len:])
+	if p.passwords == nil {
+		// Like collectd the signed data is accepted as not signed.
+		return false, nil
+	}
+	password, ok := p.passwords[user]
+	if !ok {
+		return false, fmt.Errorf("unknown user %q", user)
+	}
+
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte(user))
+	mac.Write(rest)
+	if !hmac.Equal(mac.Sum(nil), part[:sha256Len]) {
+		return false, fmt.Errorf("invalid signature for user %q", user)
+	}
+	return true, nil
+}
+
+// decrypt decrypts the AES-256-OFB encrypted part, returning nil if it can't
+// be decrypted because no auth file is configured.
+func (p *networkParser) decrypt(part []byte) ([]byte, error) {
+	if len(part) < 2 {
+		return nil, errInvalidPartLen
+	}
+	userLen := int(binary.BigEndian.Uint16(part))
+	part = part[2:]
+	if len(part) < userLen+aes.BlockSize+sha1Len {
+		return nil, errInvalidPartLen
+	}
+	user := string(part[:userLen])
+	iv := part[userLen : userLen+aes.BlockSize]
+	encrypted := part[userLen+aes.BlockSize:]
+
+	if p.passwords == nil {
+		return nil, nil
+	}
+	password, ok := p.passwords[user]
+	if !ok {
+		return nil, fmt.Errorf("unknown user %q", user)
+	}
+
+	key := sha256.Sum256([]byte(password))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewOFB(block, iv).XORKeyStream(decrypted, encrypted)
+
+	hash, payload := decrypted[:sha1Len], decrypted[sha1Len:]
+	sum := sha1.Sum(payload) // #nosec
+	if !hmac.Equal(hash, sum[:]) {
+		return nil, fmt.Errorf("failed to decrypt packet from user %q", user)
+	}
+	return payload, nil
+}
+
+// valuesRecord builds the record of a values part using the current state of
+// the identifier parts.
+func (p *networkParser) valuesRecord(part []byte, state *collectDRecord) (collectDRecord, error) {
+	if len(part) < 2 {
+		return collectDRecord{}, errInvalidPartLen
+	}
+	n := int(binary.BigEndian.Uint16(part))
+	part = part[2:]
+	if len(part) != n*9 {
+		return collectDRecord{}, errInvalidPartLen
+	}
+
+	record := *state
+	record.Message, record.Severity = nil, nil
+	record.Dsnames = make([]*string, n)
+	record.Dstypes = make([]*string, n)
+	record.Values = make([]*json.Number, n)
+
+	var dsNames []string
+	if state.TypeS != nil {
+		dsNames = p.dsNames[*state.TypeS]
+	}
+	for i := 0; i < n; i++ {
+		var dsName string
+		switch {
+		case len(dsNames) == n:
+			dsName = dsNames[i]
+		case n == 1:
+			dsName = "value"
+		default:
+			dsName = "value" + strconv.Itoa(i)
+		}
+
+		raw := part[n+i*8 : n+(i+1)*8]
+		var dsType, value string
+		switch part[i] {
+		case dsTypeCounter:
+			dsType, value = collectDMetricCounter, strconv.FormatUint(binary.BigEndian.Uint64(raw), 10)
+		case dsTypeGauge:
+			// Gauges are the only values sent in little-endian.
+			dsType, value = collectDMetricGauge, formatGauge(math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+		case dsTypeDerive:
+			dsType, value = collectDMetricDerive, strconv.FormatInt(int64(binary.BigEndian.Uint64(raw)), 10)
+		case dsTypeAbsolute:
+			dsType, value = collectDMetricAbsolute, strconv.FormatUint(binary.BigEndian.Uint64(raw), 10)
+		default:
+			return collectDRecord{}, fmt.Errorf("unknown data source type %d", part[i])
+		}
+
+		number := json.Number(value)
+		record.Dsnames[i] = &dsName
+		record.Dstypes[i] = &dsType
+		record.Values[i] = &number
+	}
+	return record, nil
+}
+
+// formatGauge formats the gauge so it is always decoded as a double.
+func formatGauge(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
+
+func stringPart(part []byte) *string {
+	s := string(bytes.TrimRight(part, "\x00"))
+	return &s
+}
+
+// timePart decodes a time or interval part in seconds. The high resolution
+// parts are in units of 2^-30 seconds.
+func timePart(part []byte, highResolution bool) (float64, error) {
+	if len(part) != 8 {
+		return 0, errInvalidPartLen
+	}
+	v := binary.BigEndian.Uint64(part)
+	if highResolution {
+		return float64(v) / (1 << 30), nil
+	}
+	return float64(v), nil
+}
+
+func severityString(severity uint64) string {
+	switch severity {
+	case severityFailure:
+		return "FAILURE"
+	case severityWarning:
+		return "WARNING"
+	case severityOkay:
+		return "OKAY"
+	}
+	return "UNKNOWN"
+}
@@ -0,0 +1,193 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPacketSize is the maximum size of the UDP packets sent by collectd,
+	// see the MaxPacketSize option of the network plugin.
+	maxPacketSize = 65535
+
+	udpTransport  = "udp"
+	networkFormat = "collectd_binary"
+)
+
+var _ component.MetricsReceiver = (*networkReceiver)(nil)
+
+// networkReceiver implements the component.MetricsReceiver for the collectd
+// binary network protocol sent over UDP.
+type networkReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	name         string
+	addr         string
+	parser       *networkParser
+	nextConsumer consumer.MetricsConsumerOld
+	// defaultAttrs are the default attributes of all the metrics, read
+	// from the query of the endpoint.
+	defaultAttrs map[string]string
+	// notificationsAsMetrics converts the notifications to metrics instead
+	// of dropping them.
+	notificationsAsMetrics bool
+
+	conn net.PacketConn
+	wg   sync.WaitGroup
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// newNetworkReceiver creates the collectd network receiver with the given
+// configuration.
+func newNetworkReceiver(
+	logger *zap.Logger,
+	cfg *Config,
+	nextConsumer consumer.MetricsConsumerOld,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	parser, err := newNetworkParser(cfg.SecurityLevel, cfg.AuthFile, cfg.TypesDB)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no request to read the default attributes from as with the
+	// write_http plugin, they are set in the query of the endpoint instead,
+	// e.g. "localhost:25826?sfxdim_env=prod".
+	addr, query := cfg.Endpoint, ""
+	if i := strings.IndexByte(addr, '?'); i >= 0 {
+		addr, query = addr[:i], addr[i+1:]
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collectd endpoint query %q: %v", query, err)
+	}
+
+	return &networkReceiver{
+		logger:       logger,
+		name:         cfg.Name(),
+		addr:         addr,
+		parser:       parser,
+		nextConsumer: nextConsumer,
+		defaultAttrs: defaultAttributes(cfg.AttributesPrefix, params),
+
+		notificationsAsMetrics: cfg.NotificationsAsMetrics,
+	}, nil
+}
+
+// Start listens on the UDP address and starts processing the packets.
+func (nr *networkReceiver) Start(_ context.Context, _ component.Host) error {
+	nr.Lock()
+	defer nr.Unlock()
+
+	err := errAlreadyStarted
+	nr.startOnce.Do(func() {
+		nr.conn, err = net.ListenPacket(udpTransport, nr.addr)
+		if err != nil {
+			err = fmt.Errorf("error starting collectd receiver: %v", err)
+			return
+		}
+
+		nr.wg.Add(1)
+		go func() {
+			defer nr.wg.Done()
+			nr.readPackets()
+		}()
+	})
+
+	return err
+}
+
+// Shutdown stops the collectd network receiver.
+func (nr *networkReceiver) Shutdown(context.Context) error {
+	nr.Lock()
+	defer nr.Unlock()
+
+	err := errAlreadyStopped
+	nr.stopOnce.Do(func() {
+		if nr.conn == nil {
+			err = nil
+			return
+		}
+		err = nr.conn.Close()
+		nr.wg.Wait()
+	})
+	return err
+}
+
+func (nr *networkReceiver) readPackets() {
+	ctx := obsreport.ReceiverContext(context.Background(), nr.name, udpTransport, "")
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := nr.conn.ReadFrom(buf)
+		if n > 0 {
+			nr.handlePacket(ctx, buf[:n])
+		}
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (nr *networkReceiver) handlePacket(ctx context.Context, packet []byte) {
+	recordRequestReceived()
+	ctx = obsreport.StartMetricsReceiveOp(ctx, nr.name, udpTransport)
+
+	records, err := nr.parser.parse(packet)
+	if err != nil {
+		recordRequestErrors()
+		nr.logger.Debug("unable to decode collectd packet", zap.Error(err))
+		// Process the records decoded before the error.
+	}
+	if len(records) == 0 {
+		obsreport.EndMetricsReceiveOp(ctx, networkFormat, 0, 0, err)
+		return
+	}
+
+	md := consumerdata.MetricsData{}
+	md.Metrics, err = recordsToMetrics(records, nr.defaultAttrs, nr.notificationsAsMetrics)
+	if err != nil {
+		recordRequestErrors()
+		nr.logger.Debug("unable to process metrics", zap.Error(err))
+		obsreport.EndMetricsReceiveOp(ctx, networkFormat, 0, 0, err)
+		return
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	if err = nr.nextConsumer.ConsumeMetricsData(ctx, md); err != nil {
+		recordRequestErrors()
+		nr.logger.Debug("unable to process metrics", zap.Error(err))
+	}
+	obsreport.EndMetricsReceiveOp(ctx, networkFormat, numTimeSeries, numPoints, err)
+}
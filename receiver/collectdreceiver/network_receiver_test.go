@@ -0,0 +1,112 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestNetworkReceiver(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Endpoint = addr + "?dim_env=prod&dim_blank=&other=ignored"
+	cfg.AttributesPrefix = "dim_"
+	cfg.Encoding = binaryEncodingFormat
+	cfg.SecurityLevel = securityLevelSign
+	cfg.AuthFile = "./testdata/passwd"
+	cfg.TypesDB = []string{"./testdata/types.db"}
+	cfg.NotificationsAsMetrics = true
+
+	sink := new(exportertest.SinkMetricsExporterOld)
+	r, err := newNetworkReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+	assert.Equal(t, errAlreadyStarted, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Not signed, ignored.
+	_, err = conn.Write(testPayload())
+	require.NoError(t, err)
+	notification := packetBuilder{}.
+		number(partSeverity, severityFailure).
+		string(partMessage, "host1 is down")
+	_, err = conn.Write(signPacket("alice", "secret", append(testPayload(), notification...)))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 10*time.Second, 5*time.Millisecond)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	var names []string
+	for _, m := range mds[0].Metrics {
+		names = append(names, m.MetricDescriptor.Name)
+	}
+	assert.Equal(t, []string{
+		"load.shortterm",
+		"load.midterm",
+		"load.longterm",
+		"if_octets.rx",
+		"if_octets.tx",
+		"uptime",
+		"collectd.notification",
+	}, names)
+
+	// The default attributes are added as with the write_http plugin.
+	for _, m := range mds[0].Metrics {
+		labels := make(map[string]string)
+		for i, k := range m.MetricDescriptor.LabelKeys {
+			labels[k.Key] = m.Timeseries[0].LabelValues[i].Value
+		}
+		assert.Equal(t, "prod", labels["env"], m.MetricDescriptor.Name)
+		assert.NotContains(t, labels, "blank", m.MetricDescriptor.Name)
+		assert.NotContains(t, labels, "other", m.MetricDescriptor.Name)
+	}
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, errAlreadyStopped, r.Shutdown(context.Background()))
+}
+
+func TestNewNetworkReceiverErrors(t *testing.T) {
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Encoding = binaryEncodingFormat
+
+	_, err := newNetworkReceiver(zap.NewNop(), cfg, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+
+	cfg.SecurityLevel = securityLevelEncrypt
+	_, err = newNetworkReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	assert.Error(t, err)
+
+	cfg.SecurityLevel = securityLevelNone
+	cfg.Endpoint = "localhost:25826?dim_env=%zz"
+	_, err = newNetworkReceiver(zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	assert.Error(t, err)
+}
@@ -0,0 +1,367 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packetBuilder builds packets of the collectd binary network protocol.
+type packetBuilder []byte
+
+func (b packetBuilder) part(partType uint16, data []byte) packetBuilder {
+	header := make([]byte, partHeaderLen)
+	binary.BigEndian.PutUint16(header, partType)
+	binary.BigEndian.PutUint16(header[2:], uint16(partHeaderLen+len(data)))
+	return append(append(b, header...), data...)
+}
+
+func (b packetBuilder) string(partType uint16, s string) packetBuilder {
+	return b.part(partType, append([]byte(s), 0))
+}
+
+func (b packetBuilder) number(partType uint16, v uint64) packetBuilder {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, v)
+	return b.part(partType, data)
+}
+
+type dsValue struct {
+	dsType byte
+	value  uint64
+}
+
+func gaugeValue(v float64) dsValue {
+	return dsValue{dsType: dsTypeGauge, value: math.Float64bits(v)}
+}
+
+func (b packetBuilder) values(values ...dsValue) packetBuilder {
+	data := make([]byte, 2+len(values)*9)
+	binary.BigEndian.PutUint16(data, uint16(len(values)))
+	for i, v := range values {
+		data[2+i] = v.dsType
+		raw := data[2+len(values)+i*8:]
+		if v.dsType == dsTypeGauge {
+			binary.LittleEndian.PutUint64(raw, v.value)
+		} else {
+			binary.BigEndian.PutUint64(raw, v.value)
+		}
+	}
+	return b.part(partValues, data)
+}
+
+func signPacket(user, password string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte(user))
+	mac.Write(payload)
+	signed := packetBuilder{}.part(partSignSHA256, append(mac.Sum(nil), user...))
+	return append(signed, payload...)
+}
+
+func encryptPacket(user, password string, payload []byte) []byte {
+	key := sha256.Sum256([]byte(password))
+	block, _ := aes.NewCipher(key[:])
+	iv := make([]byte, aes.BlockSize)
+	for i := range iv {
+		iv[i] = byte(i)
+	}
+	sum := sha1.Sum(payload) // #nosec
+	plaintext := append(sum[:], payload...)
+	encrypted := make([]byte, len(plaintext))
+	cipher.NewOFB(block, iv).XORKeyStream(encrypted, plaintext)
+
+	data := make([]byte, 2)
+	binary.BigEndian.PutUint16(data, uint16(len(user)))
+	data = append(data, user...)
+	data = append(data, iv...)
+	data = append(data, encrypted...)
+	return packetBuilder{}.part(partEncrAES256, data)
+}
+
+func testPayload() []byte {
+	return packetBuilder{}.
+		string(partHost, "host1").
+		number(partTimeHR, 1415062577<<30).
+		number(partIntervalHR, 10<<30).
+		string(partPlugin, "load").
+		string(partPluginInstance, "").
+		string(partType, "load").
+		string(partTypeInstance, "").
+		values(gaugeValue(0.5), gaugeValue(1), gaugeValue(1.25)).
+		string(partPlugin, "interface").
+		string(partPluginInstance, "eth0").
+		string(partType, "if_octets").
+		values(dsValue{dsTypeDerive, 100}, dsValue{dsTypeDerive, 200}).
+		string(partType, "uptime").
+		values(dsValue{dsTypeCounter, 42})
+}
+
+func recordValues(r collectDRecord) (names, types, values []string) {
+	for i := range r.Values {
+		names = append(names, *r.Dsnames[i])
+		types = append(types, *r.Dstypes[i])
+		values = append(values, r.Values[i].String())
+	}
+	return names, types, values
+}
+
+func TestNetworkParser(t *testing.T) {
+	p, err := newNetworkParser("", "", []string{"./testdata/types.db"})
+	require.NoError(t, err)
+
+	records, err := p.parse(testPayload())
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	r := records[0]
+	assert.Equal(t, "host1", *r.Host)
+	assert.Equal(t, "load", *r.Plugin)
+	assert.Equal(t, "", *r.PluginInstance)
+	assert.Equal(t, "load", *r.TypeS)
+	assert.Equal(t, 1415062577.0, *r.Time)
+	assert.Equal(t, 10.0, *r.Interval)
+	names, types, values := recordValues(r)
+	assert.Equal(t, []string{"shortterm", "midterm", "longterm"}, names)
+	assert.Equal(t, []string{"gauge", "gauge", "gauge"}, types)
+	assert.Equal(t, []string{"0.5", "1.0", "1.25"}, values)
+
+	r = records[1]
+	assert.Equal(t, "host1", *r.Host)
+	assert.Equal(t, "interface", *r.Plugin)
+	assert.Equal(t, "eth0", *r.PluginInstance)
+	names, types, values = recordValues(r)
+	assert.Equal(t, []string{"rx", "tx"}, names)
+	assert.Equal(t, []string{"derive", "derive"}, types)
+	assert.Equal(t, []string{"100", "200"}, values)
+
+	// Unknown type.
+	r = records[2]
+	assert.Equal(t, "uptime", *r.TypeS)
+	names, types, values = recordValues(r)
+	assert.Equal(t, []string{"value"}, names)
+	assert.Equal(t, []string{"counter"}, types)
+	assert.Equal(t, []string{"42"}, values)
+}
+
+func TestNetworkParserNotification(t *testing.T) {
+	p, err := newNetworkParser("", "", nil)
+	require.NoError(t, err)
+
+	packet := packetBuilder{}.
+		string(partHost, "host1").
+		number(partTime, 1415062577).
+		string(partPlugin, "cpu").
+		number(partSeverity, severityWarning).
+		string(partMessage, "CPU usage is high").
+		values(dsValue{dsTypeAbsolute, 1})
+	records, err := p.parse(packet)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.True(t, records[0].isEvent())
+	assert.Equal(t, "WARNING", *records[0].Severity)
+	assert.Equal(t, "CPU usage is high", *records[0].Message)
+	assert.Equal(t, "cpu", *records[0].Plugin)
+
+	assert.False(t, records[1].isEvent())
+	names, types, values := recordValues(records[1])
+	assert.Equal(t, []string{"value"}, names)
+	assert.Equal(t, []string{"absolute"}, types)
+	assert.Equal(t, []string{"1"}, values)
+}
+
+func TestNetworkParserSecurity(t *testing.T) {
+	payload := testPayload()
+	tests := []struct {
+		name          string
+		securityLevel string
+		authFile      string
+		packet        []byte
+		wantRecords   int
+		wantErr       bool
+	}{
+		{
+			name:        "none",
+			packet:      payload,
+			wantRecords: 3,
+		},
+		{
+			name:        "none_signed_without_auth_file",
+			packet:      signPacket("alice", "wrong", payload),
+			wantRecords: 3,
+		},
+		{
+			name:        "none_signed",
+			authFile:    "./testdata/passwd",
+			packet:      signPacket("alice", "secret", payload),
+			wantRecords: 3,
+		},
+		{
+			name:     "none_invalid_signature",
+			authFile: "./testdata/passwd",
+			packet:   signPacket("alice", "wrong", payload),
+			wantErr:  true,
+		},
+		{
+			name:     "none_unknown_user",
+			authFile: "./testdata/passwd",
+			packet:   signPacket("eve", "secret", payload),
+			wantErr:  true,
+		},
+		{
+			name:   "none_encrypted_without_auth_file",
+			packet: encryptPacket("alice", "secret", payload),
+		},
+		{
+			name:        "none_encrypted",
+			authFile:    "./testdata/passwd",
+			packet:      encryptPacket("alice", "secret", payload),
+			wantRecords: 3,
+		},
+		{
+			name:          "sign_not_signed",
+			securityLevel: "sign",
+			authFile:      "./testdata/passwd",
+			packet:        payload,
+		},
+		{
+			name:          "sign_signed",
+			securityLevel: "sign",
+			authFile:      "./testdata/passwd",
+			packet:        signPacket("bob", "hunter2", payload),
+			wantRecords:   3,
+		},
+		{
+			name:          "sign_encrypted",
+			securityLevel: "sign",
+			authFile:      "./testdata/passwd",
+			packet:        encryptPacket("bob", "hunter2", payload),
+			wantRecords:   3,
+		},
+		{
+			name:          "encrypt_signed",
+			securityLevel: "encrypt",
+			authFile:      "./testdata/passwd",
+			packet:        signPacket("bob", "hunter2", payload),
+		},
+		{
+			name:          "encrypt_encrypted",
+			securityLevel: "encrypt",
+			authFile:      "./testdata/passwd",
+			packet:        encryptPacket("bob", "hunter2", payload),
+			wantRecords:   3,
+		},
+		{
+			name:          "encrypt_wrong_password",
+			securityLevel: "encrypt",
+			authFile:      "./testdata/passwd",
+			packet:        encryptPacket("bob", "wrong", payload),
+			wantErr:       true,
+		},
+		{
+			name:          "encrypt_mixed",
+			securityLevel: "encrypt",
+			authFile:      "./testdata/passwd",
+			packet:        append(encryptPacket("bob", "hunter2", payload), payload...),
+			wantRecords:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newNetworkParser(tt.securityLevel, tt.authFile, nil)
+			require.NoError(t, err)
+
+			records, err := p.parse(tt.packet)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, records, tt.wantRecords)
+		})
+	}
+}
+
+func TestNetworkParserInvalidPacket(t *testing.T) {
+	p, err := newNetworkParser("", "", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{
+			name:   "short_header",
+			packet: []byte{0, 0, 0},
+		},
+		{
+			name:   "part_too_long",
+			packet: []byte{0, 0, 0, 10, 'a', 0},
+		},
+		{
+			name:   "part_too_short",
+			packet: []byte{0, 0, 0, 2},
+		},
+		{
+			name:   "invalid_time",
+			packet: packetBuilder{}.part(partTime, []byte{0, 1}),
+		},
+		{
+			name:   "invalid_values_count",
+			packet: packetBuilder{}.part(partValues, []byte{0, 2, dsTypeGauge, 0, 0, 0, 0, 0, 0, 0, 0}),
+		},
+		{
+			name:   "unknown_ds_type",
+			packet: packetBuilder{}.values(dsValue{dsType: 7}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.parse(tt.packet)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewNetworkParserErrors(t *testing.T) {
+	_, err := newNetworkParser("paranoid", "", nil)
+	assert.Error(t, err)
+
+	_, err = newNetworkParser("sign", "", nil)
+	assert.Error(t, err)
+
+	_, err = newNetworkParser("", "./testdata/missing", nil)
+	assert.Error(t, err)
+
+	_, err = newNetworkParser("", "", []string{"./testdata/missing"})
+	assert.Error(t, err)
+}
+
+func TestFormatGauge(t *testing.T) {
+	assert.Equal(t, "1.0", formatGauge(1))
+	assert.Equal(t, "-2.5", formatGauge(-2.5))
+	assert.Equal(t, "1e+21", formatGauge(1e21))
+	assert.Equal(t, "NaN", formatGauge(math.NaN()))
+}
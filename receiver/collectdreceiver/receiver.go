@@ -21,11 +21,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.uber.org/zap"
@@ -46,7 +49,10 @@ type collectdReceiver struct {
 	addr               string
 	server             *http.Server
 	defaultAttrsPrefix string
-	nextConsumer       consumer.MetricsConsumerOld
+	// notificationsAsMetrics converts the notifications to metrics instead
+	// of dropping them.
+	notificationsAsMetrics bool
+	nextConsumer           consumer.MetricsConsumerOld
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -59,21 +65,35 @@ func New(
 	timeout time.Duration,
 	defaultAttrsPrefix string,
 	nextConsumer consumer.MetricsConsumerOld) (component.MetricsReceiver, error) {
+	return newCollectdReceiver(logger, &Config{
+		TCPAddr:          confignet.TCPAddr{Endpoint: addr},
+		Timeout:          timeout,
+		AttributesPrefix: defaultAttrsPrefix,
+	}, nextConsumer)
+}
+
+// newCollectdReceiver creates the CollectD JSON receiver with the given
+// configuration.
+func newCollectdReceiver(
+	logger *zap.Logger,
+	cfg *Config,
+	nextConsumer consumer.MetricsConsumerOld) (component.MetricsReceiver, error) {
 	if nextConsumer == nil {
 		return nil, errNilNextConsumer
 	}
 
 	r := &collectdReceiver{
-		logger:             logger,
-		addr:               addr,
-		nextConsumer:       nextConsumer,
-		defaultAttrsPrefix: defaultAttrsPrefix,
+		logger:                 logger,
+		addr:                   cfg.Endpoint,
+		nextConsumer:           nextConsumer,
+		defaultAttrsPrefix:     cfg.AttributesPrefix,
+		notificationsAsMetrics: cfg.NotificationsAsMetrics,
 	}
 	r.server = &http.Server{
-		Addr:         addr,
+		Addr:         cfg.Endpoint,
 		Handler:      r,
-		ReadTimeout:  timeout,
-		WriteTimeout: timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
 	}
 	return r, nil
 }
@@ -132,16 +152,14 @@ func (cdr *collectdReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	defaultAttrs := cdr.defaultAttributes(r)
+	defaultAttrs := defaultAttributes(cdr.defaultAttrsPrefix, r.URL.Query())
 
 	md := consumerdata.MetricsData{}
 	ctx := context.Background()
-	for _, record := range records {
-		md.Metrics, err = record.appendToMetrics(md.Metrics, defaultAttrs)
-		if err != nil {
-			cdr.handleHTTPErr(w, err, "unable to process metrics")
-			return
-		}
+	md.Metrics, err = recordsToMetrics(records, defaultAttrs, cdr.notificationsAsMetrics)
+	if err != nil {
+		cdr.handleHTTPErr(w, err, "unable to process metrics")
+		return
 	}
 
 	err = cdr.nextConsumer.ConsumeMetricsData(ctx, md)
@@ -152,20 +170,43 @@ func (cdr *collectdReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func (cdr *collectdReceiver) defaultAttributes(req *http.Request) map[string]string {
-	if cdr.defaultAttrsPrefix == "" {
+// recordsToMetrics converts the records received with either encoding to
+// metrics, the notifications are dropped unless notificationsAsMetrics is set.
+func recordsToMetrics(
+	records []collectDRecord,
+	defaultAttrs map[string]string,
+	notificationsAsMetrics bool,
+) ([]*metricspb.Metric, error) {
+	var metrics []*metricspb.Metric
+	for _, record := range records {
+		if notificationsAsMetrics && record.isEvent() {
+			metrics = record.appendNotificationToMetrics(metrics, defaultAttrs)
+			continue
+		}
+		var err error
+		metrics, err = record.appendToMetrics(metrics, defaultAttrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+// defaultAttributes returns the parameters starting with the prefix, without
+// it, as the default attributes of the metrics.
+func defaultAttributes(prefix string, params url.Values) map[string]string {
+	if prefix == "" {
 		return nil
 	}
-	params := req.URL.Query()
 	attrs := make(map[string]string)
 	for key := range params {
-		if strings.HasPrefix(key, cdr.defaultAttrsPrefix) {
+		if strings.HasPrefix(key, prefix) {
 			value := params.Get(key)
 			if len(value) == 0 {
 				recordDefaultBlankAttrs()
 				continue
 			}
-			key = key[len(cdr.defaultAttrsPrefix):]
+			key = key[len(prefix):]
 			attrs[key] = value
 		}
 	}
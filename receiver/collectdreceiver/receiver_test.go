@@ -17,6 +17,7 @@ package collectdreceiver
 import (
 	"bytes"
 	"context"
+	"net"
 	"net/http"
 	"sync"
 	"testing"
@@ -27,9 +28,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
 	"go.uber.org/zap"
 )
 
@@ -207,6 +210,68 @@ func TestCollectDServer(t *testing.T) {
 	}
 }
 
+func TestCollectDServerNotificationsAsMetrics(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkMetricsExporterOld)
+	cdr, err := newCollectdReceiver(zap.NewNop(), &Config{
+		TCPAddr:                confignet.TCPAddr{Endpoint: endpoint},
+		Timeout:                defaultTimeout,
+		NotificationsAsMetrics: true,
+	}, sink)
+	require.NoError(t, err)
+	require.NoError(t, cdr.Start(context.Background(), componenttest.NewNopHost()))
+	defer cdr.Shutdown(context.Background())
+	require.True(t, testutil.WaitFor(t, func() bool {
+		conn, err := net.Dial("tcp", endpoint)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}))
+
+	const notification = `[
+    {
+        "host": "i-b13d1e5f",
+        "message": "Host i-b13d1e5f, plugin memory type memory: Data source \"value\" is currently 2.1474.",
+        "meta": {},
+        "plugin": "memory",
+        "plugin_instance": "",
+        "severity": "FAILURE",
+        "time": 1415062577.4949999,
+        "type": "memory",
+        "type_instance": "free"
+    }
+]`
+	resp, err := http.Post("http://"+endpoint, "application/json", bytes.NewBufferString(notification))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	require.Len(t, mds[0].Metrics, 1)
+	metric := mds[0].Metrics[0]
+	assert.Equal(t, "collectd.notification", metric.MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, metric.MetricDescriptor.Type)
+
+	labels := map[string]string{}
+	for i, k := range metric.MetricDescriptor.LabelKeys {
+		labels[k.Key] = metric.Timeseries[0].LabelValues[i].Value
+	}
+	assert.Equal(t, map[string]string{
+		"plugin":        "memory",
+		"host":          "i-b13d1e5f",
+		"type":          "memory",
+		"type_instance": "free",
+		"severity":      "FAILURE",
+		"message":       `Host i-b13d1e5f, plugin memory type memory: Data source "value" is currently 2.1474.`,
+	}, labels)
+	assert.Equal(t, []*metricspb.Point{{
+		Timestamp: &timestamp.Timestamp{Seconds: 1415062577, Nanos: 494999808},
+		Value:     &metricspb.Point_Int64Value{Int64Value: 1},
+	}}, metric.Timeseries[0].Points)
+}
+
 type mockMetricsSink struct {
 	wg           *sync.WaitGroup
 	queue        chan consumerdata.MetricsData
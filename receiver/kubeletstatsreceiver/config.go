@@ -34,6 +34,15 @@ type Config struct {
 	// ExtraMetadataLabels contains list of extra metadata that should be taken from /pods endpoint
 	// and put as extra labels on metrics resource.
 	// No additional metadata is fetched by default, so there are no extra calls to /pods endpoint.
-	// Only container.id label is supported at the moment.
+	// The container.id, container.image.name, container.image.tag, k8s.node.name,
+	// k8s.volume.type, k8s.workload.kind and k8s.workload.name labels are supported.
 	ExtraMetadataLabels []kubelet.MetadataLabel `mapstructure:"extra_metadata_labels"`
+
+	// Extract allows extracting pod labels and annotations from /pods endpoint
+	// and putting them as extra labels on pod, container and volume resources.
+	Extract kubelet.ExtractConfig `mapstructure:"extract"`
+
+	// MetricGroupsToCollect is the list of metric groups to collect among
+	// node, pod, container and volume. All of them are collected by default.
+	MetricGroupsToCollect []kubelet.MetricGroup `mapstructure:"metric_groups"`
 }
@@ -91,7 +91,31 @@ func TestLoadConfig(t *testing.T) {
 				AuthType: "serviceAccount",
 			},
 		},
-		CollectionInterval:  duration,
-		ExtraMetadataLabels: []kubelet.MetadataLabel{kubelet.MetadataLabelContainerID},
+		CollectionInterval: duration,
+		ExtraMetadataLabels: []kubelet.MetadataLabel{
+			kubelet.MetadataLabelContainerID,
+			kubelet.MetadataLabelWorkloadName,
+		},
+		Extract: kubelet.ExtractConfig{
+			Labels: []kubelet.FieldExtractConfig{{Key: "app"}},
+			Annotations: []kubelet.FieldExtractConfig{
+				{TagName: "git.sha", Key: "kubernetes.io/change-cause", Regex: `GIT_SHA=(?P<value>\w+)`},
+			},
+		},
 	}, metadataCfg)
+
+	metricGroupsCfg := cfg.Receivers["kubeletstats/metric_groups"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/metric_groups",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "serviceAccount",
+			},
+		},
+		CollectionInterval:    duration,
+		MetricGroupsToCollect: []kubelet.MetricGroup{kubelet.NodeMetricGroup, kubelet.PodMetricGroup},
+	}, metricGroupsCfg)
 }
@@ -77,18 +77,45 @@ func (f *Factory) CreateMetricsReceiver(
 	if err != nil {
 		return nil, err
 	}
+	rules, err := kubelet.NewExtractionRules(cfg.Extract)
+	if err != nil {
+		return nil, err
+	}
+	metricGroups, err := metricGroupsToCollect(cfg.MetricGroupsToCollect)
+	if err != nil {
+		return nil, err
+	}
 	rest, err := f.restClient(logger, cfg)
 	if err != nil {
 		return nil, err
 	}
 	return &receiver{
-		logger:   logger,
-		cfg:      cfg,
-		consumer: consumer,
-		rest:     rest,
+		logger:                logger,
+		cfg:                   cfg,
+		consumer:              consumer,
+		rest:                  rest,
+		rules:                 rules,
+		metricGroupsToCollect: metricGroups,
 	}, nil
 }
 
+// metricGroupsToCollect validates the configured metric groups and returns
+// them as a set, defaulting to all the metric groups.
+func metricGroupsToCollect(groups []kubelet.MetricGroup) (map[kubelet.MetricGroup]bool, error) {
+	err := kubelet.ValidateMetricGroupsConfig(groups)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		groups = kubelet.DefaultMetricGroups
+	}
+	out := map[kubelet.MetricGroup]bool{}
+	for _, group := range groups {
+		out[group] = true
+	}
+	return out, nil
+}
+
 func (f *Factory) restClient(logger *zap.Logger, cfg *Config) (kubelet.RestClient, error) {
 	clientProvider, err := kubelet.NewClientProvider(cfg.Endpoint, &cfg.ClientConfig, logger)
 	if err != nil {
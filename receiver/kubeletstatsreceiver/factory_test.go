@@ -81,6 +81,40 @@ func TestFactoryInvalidExtraMetadataLabels(t *testing.T) {
 	require.Nil(t, metricsReceiver)
 }
 
+func TestFactoryInvalidExtractConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := Config{
+		Extract: kubelet.ExtractConfig{
+			Labels: []kubelet.FieldExtractConfig{{Key: "app", Regex: "(.*)"}},
+		},
+	}
+	metricsReceiver, err := factory.CreateMetricsReceiver(
+		context.Background(),
+		zap.NewNop(),
+		&cfg,
+		&testbed.MockMetricConsumer{},
+	)
+	require.Error(t, err)
+	require.Equal(t, "regex for label \"app\" must contain exactly one named submatch (value)", err.Error())
+	require.Nil(t, metricsReceiver)
+}
+
+func TestFactoryInvalidMetricGroups(t *testing.T) {
+	factory := &Factory{}
+	cfg := Config{
+		MetricGroupsToCollect: []kubelet.MetricGroup{kubelet.NodeMetricGroup, kubelet.MetricGroup("cluster")},
+	}
+	metricsReceiver, err := factory.CreateMetricsReceiver(
+		context.Background(),
+		zap.NewNop(),
+		&cfg,
+		&testbed.MockMetricConsumer{},
+	)
+	require.Error(t, err)
+	require.Equal(t, "metric group \"cluster\" is not supported", err.Error())
+	require.Nil(t, metricsReceiver)
+}
+
 func TestFactoryBadAuthType(t *testing.T) {
 	factory := &Factory{}
 	cfg := &Config{
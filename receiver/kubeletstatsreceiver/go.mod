@@ -7,7 +7,6 @@ require (
 	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/golang/protobuf v1.4.2
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
-	github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.7.0
@@ -19,7 +18,5 @@ require (
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/common => ../../internal/common
 
-replace github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver => ../redisreceiver
-
 // Yet another hack that we need until kubernetes client moves to the new github.com/googleapis/gnostic
 replace github.com/googleapis/gnostic => github.com/googleapis/gnostic v0.3.1
@@ -15,6 +15,8 @@
 package kubelet
 
 import (
+	"time"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -33,22 +35,80 @@ const (
 	k8sPrefix       = "k8s."
 	nodePrefix      = k8sPrefix + "node."
 	podPrefix       = k8sPrefix + "pod."
+	volumePrefix    = k8sPrefix + "volume."
 	containerPrefix = "container."
+
+	nodeImageFsPrefix         = nodePrefix + "imagefs."
+	containerLogsPrefix       = containerPrefix + "logs."
+	systemContainerPrefix     = k8sPrefix + "system_container."
+	systemContainerLogsPrefix = systemContainerPrefix + "logs."
 )
 
-func (a *metricDataAccumulator) nodeStats(s stats.NodeStats) {
-	// todo s.Runtime.ImageFs
+func (a *metricDataAccumulator) nodeStats(nodeResource *resourcepb.Resource, s stats.NodeStats) {
+	var imageFs *stats.FsStats
+	if s.Runtime != nil {
+		imageFs = s.Runtime.ImageFs
+	}
 	a.accumulate(
 		timestampProto(s.StartTime.Time),
-		nodeResource(s),
+		nodeResource,
 
 		cpuMetrics(nodePrefix, s.CPU),
 		fsMetrics(nodePrefix, s.Fs),
 		memMetrics(nodePrefix, s.Memory),
 		networkMetrics(nodePrefix, s.Network),
+		filesystemMetrics(nodeImageFsPrefix, imageFs),
+	)
+}
+
+// systemContainerStats accumulates the metrics of a system container of the
+// node, e.g. the kubelet, the container runtime or the pods cgroup.
+func (a *metricDataAccumulator) systemContainerStats(nodeResource *resourcepb.Resource, s stats.ContainerStats) {
+	a.accumulate(
+		timestampProto(s.StartTime.Time),
+		systemContainerResource(nodeResource, s),
+
+		cpuMetrics(systemContainerPrefix, s.CPU),
+		memMetrics(systemContainerPrefix, s.Memory),
+		fsMetrics(systemContainerPrefix, s.Rootfs),
+		filesystemMetrics(systemContainerLogsPrefix, s.Logs),
 	)
 }
 
+// podsStats accumulates the metrics of a pod, its containers and its volumes
+// for the collected metric groups, propagating the pod resource down to them.
+func (a *metricDataAccumulator) podsStats(s stats.PodStats, metricGroupsToCollect map[MetricGroup]bool) {
+	// The metrics of a pod missing from the fetched metadata, e.g. created
+	// after the metadata were fetched, are kept with their base labels only.
+	metadata := a.metadata
+	if metadata.PodsMetadata != nil {
+		if _, err := metadata.getPod(s.PodRef.UID); err != nil {
+			a.logger.Warn("pod not found in the metadata, its metrics only have their base labels",
+				zap.String("pod", s.PodRef.Name), zap.Error(err))
+			metadata = Metadata{}
+		}
+	}
+
+	podResource, err := podResource(s, metadata)
+	if err != nil {
+		a.logger.Warn("failed to fetch pod metrics", zap.String("pod", s.PodRef.Name), zap.Error(err))
+		return
+	}
+	if metricGroupsToCollect[PodMetricGroup] {
+		a.podStats(podResource, s)
+	}
+	if metricGroupsToCollect[ContainerMetricGroup] {
+		for _, containerStats := range s.Containers {
+			a.containerStats(podResource, containerStats, metadata)
+		}
+	}
+	if metricGroupsToCollect[VolumeMetricGroup] {
+		for _, volumeStats := range s.VolumeStats {
+			a.volumeStats(podResource, s.StartTime.Time, volumeStats, metadata)
+		}
+	}
+}
+
 func (a *metricDataAccumulator) podStats(podResource *resourcepb.Resource, s stats.PodStats) {
 	a.accumulate(
 		timestampProto(s.StartTime.Time),
@@ -61,15 +121,14 @@ func (a *metricDataAccumulator) podStats(podResource *resourcepb.Resource, s sta
 	)
 }
 
-func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource, s stats.ContainerStats) {
-	resource, err := containerResource(podResource, s, a.metadata)
+func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource, s stats.ContainerStats, metadata Metadata) {
+	resource, err := containerResource(podResource, s, metadata)
 	if err != nil {
 		a.logger.Warn("failed to fetch container metrics", zap.String("pod", podResource.Labels[labelPodName]),
 			zap.String("container", podResource.Labels[labelContainerName]), zap.Error(err))
 		return
 	}
 
-	// todo s.Logs
 	a.accumulate(
 		timestampProto(s.StartTime.Time),
 		resource,
@@ -77,6 +136,24 @@ func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource,
 		cpuMetrics(containerPrefix, s.CPU),
 		memMetrics(containerPrefix, s.Memory),
 		fsMetrics(containerPrefix, s.Rootfs),
+		filesystemMetrics(containerLogsPrefix, s.Logs),
+	)
+}
+
+// Volumes do not have a start time, the start time of their pod is used.
+func (a *metricDataAccumulator) volumeStats(podResource *resourcepb.Resource, podStartTime time.Time, s stats.VolumeStats, metadata Metadata) {
+	resource, err := volumeResource(podResource, s, metadata)
+	if err != nil {
+		a.logger.Warn("failed to fetch volume metrics", zap.String("pod", podResource.Labels[labelPodName]),
+			zap.String("volume", s.Name), zap.Error(err))
+		return
+	}
+
+	a.accumulate(
+		timestampProto(podStartTime),
+		resource,
+
+		filesystemMetrics(volumePrefix, &s.FsStats),
 	)
 }
 
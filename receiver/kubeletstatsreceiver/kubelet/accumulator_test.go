@@ -16,6 +16,7 @@ package kubelet
 
 import (
 	"testing"
+	"time"
 
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/stretchr/testify/assert"
@@ -75,9 +76,177 @@ func TestContainerStatsMetadataNotFound(t *testing.T) {
 		logger:   logger,
 	}
 
-	acc.containerStats(podResource, containerStats)
+	acc.containerStats(podResource, containerStats, acc.metadata)
 
 	assert.Equal(t, 0, len(mds))
 	assert.Equal(t, 1, logs.Len())
 	assert.Equal(t, "failed to fetch container metrics", logs.All()[0].Message)
 }
+
+func TestPodsStatsPodNotFound(t *testing.T) {
+	now := metav1.Now()
+	used := uint64(7)
+	podStats := stats.PodStats{
+		PodRef:    stats.PodReference{UID: "pod-uid-123", Name: "pod1", Namespace: "ns"},
+		StartTime: now,
+		Memory:    &stats.MemoryStats{Time: now, UsageBytes: &used},
+		Containers: []stats.ContainerStats{
+			{Name: "container1", StartTime: now, Memory: &stats.MemoryStats{Time: now, UsageBytes: &used}},
+			{Name: "container2", StartTime: now, Memory: &stats.MemoryStats{Time: now, UsageBytes: &used}},
+		},
+		VolumeStats: []stats.VolumeStats{
+			{Name: "data", FsStats: stats.FsStats{Time: now, UsedBytes: &used}},
+		},
+	}
+
+	observedLogger, logs := observer.New(zapcore.WarnLevel)
+	acc := metricDataAccumulator{
+		// The pod was created after the metadata were fetched.
+		metadata: NewMetadata(
+			[]MetadataLabel{MetadataLabelContainerID, MetadataLabelVolumeType, MetadataLabelNodeName},
+			&v1.PodList{},
+		),
+		logger: zap.New(observedLogger),
+	}
+	acc.podsStats(podStats, allMetricGroups())
+
+	assert.Equal(t, 4, len(acc.m))
+	podLabels := map[string]string{
+		labelPodUID:        "pod-uid-123",
+		labelPodName:       "pod1",
+		labelNamespaceName: "ns",
+	}
+	assert.Equal(t, podLabels, acc.m[0].Resource.Labels)
+	assert.Equal(t, "container1", acc.m[1].Resource.Labels[labelContainerName])
+	assert.Equal(t, "container2", acc.m[2].Resource.Labels[labelContainerName])
+	assert.Equal(t, "data", acc.m[3].Resource.Labels[labelVolumeName])
+	for _, md := range acc.m[1:] {
+		assert.Len(t, md.Resource.Labels, len(podLabels)+1)
+	}
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "pod not found in the metadata, its metrics only have their base labels", logs.All()[0].Message)
+}
+
+func TestVolumeStats(t *testing.T) {
+	now := metav1.Now()
+	podResource := &resourcepb.Resource{
+		Labels: map[string]string{
+			labelPodUID:        "pod-uid-123",
+			labelPodName:       "postgres-0",
+			labelNamespaceName: "db",
+		},
+	}
+	available, capacity, used := uint64(3), uint64(10), uint64(7)
+	volumeStats := stats.VolumeStats{
+		Name: "data",
+		FsStats: stats.FsStats{
+			Time:           now,
+			AvailableBytes: &available,
+			CapacityBytes:  &capacity,
+			UsedBytes:      &used,
+		},
+		PVCRef: &stats.PVCReference{Name: "data-postgres-0", Namespace: "db"},
+	}
+
+	acc := metricDataAccumulator{
+		metadata: NewMetadata(nil, nil),
+		logger:   zap.NewNop(),
+	}
+	acc.volumeStats(podResource, now.Add(-time.Hour), volumeStats, acc.metadata)
+
+	assert.Equal(t, 1, len(acc.m))
+	assert.Equal(t, map[string]string{
+		labelPodUID:                           "pod-uid-123",
+		labelPodName:                          "postgres-0",
+		labelNamespaceName:                    "db",
+		"k8s.volume.name":                     "data",
+		"k8s.persistentvolumeclaim.name":      "data-postgres-0",
+		"k8s.persistentvolumeclaim.namespace": "db",
+	}, acc.m[0].Resource.Labels)
+	// the inodes are not set
+	assert.Equal(t, []string{"k8s.volume.available", "k8s.volume.capacity", "k8s.volume.usage"}, metricNames(acc.m[0]))
+}
+
+// TestVolumeStatsMetadataNotFound walks through the error cases of volumeStats.
+func TestVolumeStatsMetadataNotFound(t *testing.T) {
+	podResource := &resourcepb.Resource{
+		Labels: map[string]string{
+			labelPodUID: "pod-uid-123",
+		},
+	}
+	observedLogger, logs := observer.New(zapcore.WarnLevel)
+	acc := metricDataAccumulator{
+		metadata: NewMetadata([]MetadataLabel{MetadataLabelVolumeType}, &v1.PodList{}),
+		logger:   zap.New(observedLogger),
+	}
+
+	acc.volumeStats(podResource, time.Now(), stats.VolumeStats{Name: "data"}, acc.metadata)
+
+	assert.Equal(t, 0, len(acc.m))
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "failed to fetch volume metrics", logs.All()[0].Message)
+}
+
+func TestNodeStats(t *testing.T) {
+	now := metav1.Now()
+	capacity, used := uint64(10), uint64(7)
+	nodeStats := stats.NodeStats{
+		NodeName:  "node-1",
+		StartTime: metav1.NewTime(now.Add(-time.Hour)),
+		Runtime: &stats.RuntimeStats{
+			ImageFs: &stats.FsStats{Time: now, CapacityBytes: &capacity, UsedBytes: &used},
+		},
+		SystemContainers: []stats.ContainerStats{
+			{
+				Name:      "kubelet",
+				StartTime: metav1.NewTime(now.Add(-time.Hour)),
+				Memory:    &stats.MemoryStats{Time: now, UsageBytes: &used},
+			},
+		},
+	}
+
+	acc := metricDataAccumulator{logger: zap.NewNop()}
+	nodeResource := nodeResource(nodeStats)
+	acc.nodeStats(nodeResource, nodeStats)
+	for _, s := range nodeStats.SystemContainers {
+		acc.systemContainerStats(nodeResource, s)
+	}
+
+	assert.Equal(t, 2, len(acc.m))
+	assert.Equal(t, map[string]string{labelNodeName: "node-1"}, acc.m[0].Resource.Labels)
+	assert.Equal(t, []string{"k8s.node.imagefs.capacity", "k8s.node.imagefs.usage"}, metricNames(acc.m[0]))
+	assert.Equal(t, map[string]string{
+		labelNodeName:               "node-1",
+		"k8s.system_container.name": "kubelet",
+	}, acc.m[1].Resource.Labels)
+	assert.Equal(t, []string{"k8s.system_container.memory.usage"}, metricNames(acc.m[1]))
+}
+
+func TestContainerLogsStats(t *testing.T) {
+	now := metav1.Now()
+	used := uint64(7)
+	podResource := &resourcepb.Resource{
+		Labels: map[string]string{
+			labelPodUID: "pod-uid-123",
+		},
+	}
+	containerStats := stats.ContainerStats{
+		Name:      "container1",
+		StartTime: metav1.NewTime(now.Add(-time.Hour)),
+		Logs:      &stats.FsStats{Time: now, UsedBytes: &used},
+	}
+
+	acc := metricDataAccumulator{metadata: NewMetadata(nil, nil), logger: zap.NewNop()}
+	acc.containerStats(podResource, containerStats, acc.metadata)
+
+	assert.Equal(t, 1, len(acc.m))
+	assert.Equal(t, []string{"container.logs.usage"}, metricNames(acc.m[0]))
+}
+
+func metricNames(md *consumerdata.MetricsData) []string {
+	var names []string
+	for _, m := range md.Metrics {
+		names = append(names, m.MetricDescriptor.Name)
+	}
+	return names
+}
@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -31,8 +32,10 @@ import (
 const svcAcctCACertPath = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
 const svcAcctTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 
+// Client performs the requests to the kubelet, they are cancelled when their
+// context is done.
 type Client interface {
-	Get(path string) ([]byte, error)
+	Get(ctx context.Context, path string) ([]byte, error)
 }
 
 func NewClientProvider(endpoint string, cfg *ClientConfig, logger *zap.Logger) (ClientProvider, error) {
@@ -164,8 +167,8 @@ type clientImpl struct {
 	tok        []byte
 }
 
-func (c *clientImpl) Get(path string) ([]byte, error) {
-	req, err := c.buildReq(path)
+func (c *clientImpl) Get(ctx context.Context, path string) ([]byte, error) {
+	req, err := c.buildReq(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -193,9 +196,9 @@ func (c *clientImpl) Get(path string) ([]byte, error) {
 	return body, nil
 }
 
-func (c *clientImpl) buildReq(path string) (*http.Request, error) {
+func (c *clientImpl) buildReq(ctx context.Context, path string) (*http.Request, error) {
 	url := c.baseURL + path
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
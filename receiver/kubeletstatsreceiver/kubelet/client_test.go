@@ -15,12 +15,15 @@
 package kubelet
 
 import (
+	"context"
 	"crypto/x509"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -41,7 +44,7 @@ func TestClient(t *testing.T) {
 		httpClient: http.Client{Transport: tr},
 	}
 	require.False(t, tr.closed)
-	resp, err := client.Get("/foo")
+	resp, err := client.Get(context.Background(), "/foo")
 	require.NoError(t, err)
 	require.Equal(t, "hello", string(resp))
 	require.True(t, tr.closed)
@@ -180,7 +183,7 @@ func TestBuildReq(t *testing.T) {
 	}
 	cl, err := p.BuildClient()
 	require.NoError(t, err)
-	req, err := cl.(*clientImpl).buildReq("/foo")
+	req, err := cl.(*clientImpl).buildReq(context.Background(), "/foo")
 	require.NoError(t, err)
 	require.NotNil(t, req)
 	require.Equal(t, req.Header["Authorization"][0], "bearer s3cr3t")
@@ -196,7 +199,7 @@ func TestBuildBadReq(t *testing.T) {
 	cl, err := p.BuildClient()
 	require.NoError(t, err)
 	require.NoError(t, err)
-	_, err = cl.(*clientImpl).buildReq(" ")
+	_, err = cl.(*clientImpl).buildReq(context.Background(), " ")
 	require.Error(t, err)
 }
 
@@ -207,10 +210,26 @@ func TestFailedRT(t *testing.T) {
 		baseURL:    baseURL,
 		httpClient: http.Client{Transport: tr},
 	}
-	_, err := client.Get("/foo")
+	_, err := client.Get(context.Background(), "/foo")
 	require.Error(t, err)
 }
 
+func TestCancelledReq(t *testing.T) {
+	// The server only replies once the request is cancelled.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := &clientImpl{
+		baseURL: server.URL,
+		logger:  zap.NewNop(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.Get(ctx, "/foo")
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
 func TestBadReq(t *testing.T) {
 	tr := &fakeRoundTripper{}
 	baseURL := "http://localhost:9876"
@@ -218,7 +237,7 @@ func TestBadReq(t *testing.T) {
 		baseURL:    baseURL,
 		httpClient: http.Client{Transport: tr},
 	}
-	_, err := client.Get(" ")
+	_, err := client.Get(context.Background(), " ")
 	require.Error(t, err)
 }
 
@@ -230,7 +249,7 @@ func TestErrOnClose(t *testing.T) {
 		httpClient: http.Client{Transport: tr},
 		logger:     zap.NewNop(),
 	}
-	resp, err := client.Get("/foo")
+	resp, err := client.Get(context.Background(), "/foo")
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 }
@@ -243,7 +262,7 @@ func TestErrOnRead(t *testing.T) {
 		httpClient: http.Client{Transport: tr},
 		logger:     zap.NewNop(),
 	}
-	resp, err := client.Get("/foo")
+	resp, err := client.Get(context.Background(), "/foo")
 	require.Error(t, err)
 	require.Nil(t, resp)
 }
@@ -256,7 +275,7 @@ func TestErrCode(t *testing.T) {
 		httpClient: http.Client{Transport: tr},
 		logger:     zap.NewNop(),
 	}
-	resp, err := client.Get("/foo")
+	resp, err := client.Get(context.Background(), "/foo")
 	require.Error(t, err)
 	require.Nil(t, resp)
 }
@@ -15,10 +15,19 @@
 package kubelet
 
 const (
-	labelContainerID   = "container.id"
-	labelContainerName = "container.name"
-	labelNamespaceName = "k8s.namespace.name"
-	labelNodeName      = "k8s.node.name"
-	labelPodName       = "k8s.pod.name"
-	labelPodUID        = "k8s.pod.uid"
+	labelContainerID         = "container.id"
+	labelContainerImageName  = "container.image.name"
+	labelContainerImageTag   = "container.image.tag"
+	labelContainerName       = "container.name"
+	labelNamespaceName       = "k8s.namespace.name"
+	labelNodeName            = "k8s.node.name"
+	labelPodName             = "k8s.pod.name"
+	labelPodUID              = "k8s.pod.uid"
+	labelSystemContainerName = "k8s.system_container.name"
+	labelVolumeName          = "k8s.volume.name"
+	labelVolumeType          = "k8s.volume.type"
+	labelPVCName             = "k8s.persistentvolumeclaim.name"
+	labelPVCNamespace        = "k8s.persistentvolumeclaim.namespace"
+	labelWorkloadKind        = "k8s.workload.kind"
+	labelWorkloadName        = "k8s.workload.name"
 )
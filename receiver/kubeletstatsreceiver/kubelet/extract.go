@@ -0,0 +1,128 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"fmt"
+	"regexp"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ExtractConfig allows extracting pod labels and annotations from the /pods
+// endpoint and recording them as labels on the pod, container and volume resources.
+type ExtractConfig struct {
+	// Labels is a list of rules extracting data from pod labels.
+	Labels []FieldExtractConfig `mapstructure:"labels"`
+
+	// Annotations is a list of rules extracting data from pod annotations.
+	Annotations []FieldExtractConfig `mapstructure:"annotations"`
+}
+
+// FieldExtractConfig allows specifying an extraction rule to extract a value
+// from exactly one pod label or annotation, as in the k8s processor.
+//
+// The key is the name of the pod label or annotation and must match it exactly.
+// The tag name is the name of the resource label, which defaults to
+// k8s.label.<key> for labels and k8s.annotation.<key> for annotations.
+// The optional regex extracts a sub-string from the value and must contain
+// exactly one named group called "value", e.g. GIT_SHA=(?P<value>\w+).
+type FieldExtractConfig struct {
+	TagName string `mapstructure:"tag_name"`
+	Key     string `mapstructure:"key"`
+	Regex   string `mapstructure:"regex"`
+}
+
+// ExtractionRules are the validated rules of an ExtractConfig.
+type ExtractionRules struct {
+	labels      []fieldExtractionRule
+	annotations []fieldExtractionRule
+}
+
+type fieldExtractionRule struct {
+	name  string
+	key   string
+	regex *regexp.Regexp
+}
+
+// NewExtractionRules validates the provided config and compiles its regular expressions.
+func NewExtractionRules(cfg ExtractConfig) (ExtractionRules, error) {
+	labels, err := fieldExtractionRules("label", cfg.Labels)
+	if err != nil {
+		return ExtractionRules{}, err
+	}
+	annotations, err := fieldExtractionRules("annotation", cfg.Annotations)
+	if err != nil {
+		return ExtractionRules{}, err
+	}
+	return ExtractionRules{labels: labels, annotations: annotations}, nil
+}
+
+func fieldExtractionRules(fieldType string, fields []FieldExtractConfig) ([]fieldExtractionRule, error) {
+	var rules []fieldExtractionRule
+	for _, f := range fields {
+		if f.Key == "" {
+			return nil, fmt.Errorf("%s extraction rule is missing a key", fieldType)
+		}
+		name := f.TagName
+		if name == "" {
+			name = fmt.Sprintf("k8s.%s.%s", fieldType, f.Key)
+		}
+
+		var r *regexp.Regexp
+		if f.Regex != "" {
+			var err error
+			r, err = regexp.Compile(f.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex for %s %q: %w", fieldType, f.Key, err)
+			}
+			names := r.SubexpNames()
+			if len(names) != 2 || names[1] != "value" {
+				return nil, fmt.Errorf("regex for %s %q must contain exactly one named submatch (value)", fieldType, f.Key)
+			}
+		}
+
+		rules = append(rules, fieldExtractionRule{name: name, key: f.Key, regex: r})
+	}
+	return rules, nil
+}
+
+// Empty returns true when there is no rule, in which case pods metadata are not needed.
+func (r ExtractionRules) Empty() bool {
+	return len(r.labels) == 0 && len(r.annotations) == 0
+}
+
+// extract sets in `labels` the values extracted from the labels and annotations of the pod.
+func (r ExtractionRules) extract(pod *v1.Pod, labels map[string]string) {
+	extractFields(r.labels, pod.Labels, labels)
+	extractFields(r.annotations, pod.Annotations, labels)
+}
+
+func extractFields(rules []fieldExtractionRule, fields map[string]string, labels map[string]string) {
+	for _, rule := range rules {
+		value, ok := fields[rule.key]
+		if !ok {
+			continue
+		}
+		if rule.regex != nil {
+			matches := rule.regex.FindStringSubmatch(value)
+			if len(matches) != 2 {
+				continue
+			}
+			value = matches[1]
+		}
+		labels[rule.name] = value
+	}
+}
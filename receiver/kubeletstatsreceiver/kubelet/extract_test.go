@@ -0,0 +1,77 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExtractionRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       ExtractConfig
+		wantError string
+	}{
+		{
+			name: "empty",
+			cfg:  ExtractConfig{},
+		},
+		{
+			name: "valid",
+			cfg: ExtractConfig{
+				Labels:      []FieldExtractConfig{{Key: "app"}},
+				Annotations: []FieldExtractConfig{{TagName: "git.sha", Key: "change-cause", Regex: `GIT_SHA=(?P<value>\w+)`}},
+			},
+		},
+		{
+			name:      "missing_key",
+			cfg:       ExtractConfig{Labels: []FieldExtractConfig{{TagName: "app"}}},
+			wantError: "label extraction rule is missing a key",
+		},
+		{
+			name:      "invalid_regex",
+			cfg:       ExtractConfig{Annotations: []FieldExtractConfig{{Key: "change-cause", Regex: `(?P<value>`}}},
+			wantError: "invalid regex for annotation \"change-cause\": error parsing regexp: missing closing ): `(?P<value>`",
+		},
+		{
+			name:      "regex_without_value_group",
+			cfg:       ExtractConfig{Labels: []FieldExtractConfig{{Key: "app", Regex: `(\w+)`}}},
+			wantError: "regex for label \"app\" must contain exactly one named submatch (value)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExtractionRules(tt.cfg)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExtractionRulesEmpty(t *testing.T) {
+	rules, err := NewExtractionRules(ExtractConfig{})
+	require.NoError(t, err)
+	assert.True(t, rules.Empty())
+
+	rules, err = NewExtractionRules(ExtractConfig{Annotations: []FieldExtractConfig{{Key: "owner"}}})
+	require.NoError(t, err)
+	assert.False(t, rules.Empty())
+}
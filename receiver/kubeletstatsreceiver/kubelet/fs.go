@@ -20,6 +20,9 @@ import (
 )
 
 func fsMetrics(prefix string, s *stats.FsStats) []*metricspb.Metric {
+	if s == nil {
+		return nil
+	}
 	return applyCurrentTime([]*metricspb.Metric{
 		fsAvailableMetric(prefix, s),
 		fsCapacityMetric(prefix, s),
@@ -38,3 +41,19 @@ func fsCapacityMetric(prefix string, s *stats.FsStats) *metricspb.Metric {
 func fsUsedMetric(prefix string, s *stats.FsStats) *metricspb.Metric {
 	return intGauge(prefix+"filesystem.usage", "By", s.UsedBytes)
 }
+
+// filesystemMetrics returns the space and inodes metrics of a filesystem that
+// is not the root filesystem of its resource, e.g. a volume, named after prefix.
+func filesystemMetrics(prefix string, s *stats.FsStats) []*metricspb.Metric {
+	if s == nil {
+		return nil
+	}
+	return applyCurrentTime([]*metricspb.Metric{
+		intGauge(prefix+"available", "By", s.AvailableBytes),
+		intGauge(prefix+"capacity", "By", s.CapacityBytes),
+		intGauge(prefix+"usage", "By", s.UsedBytes),
+		intGauge(prefix+"inodes", "1", s.Inodes),
+		intGauge(prefix+"inodes.free", "1", s.InodesFree),
+		intGauge(prefix+"inodes.used", "1", s.InodesUsed),
+	}, s.Time.Time)
+}
@@ -20,6 +20,9 @@ import (
 )
 
 func memMetrics(prefix string, s *stats.MemoryStats) []*metricspb.Metric {
+	if s == nil {
+		return nil
+	}
 	return applyCurrentTime([]*metricspb.Metric{
 		memAvailableMetric(prefix, s),
 		memUsageMetric(prefix, s),
@@ -16,9 +16,12 @@ package kubelet
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -26,13 +29,30 @@ import (
 type MetadataLabel string
 
 const (
-	MetadataLabelContainerID MetadataLabel = labelContainerID
+	MetadataLabelContainerID        MetadataLabel = labelContainerID
+	MetadataLabelContainerImageName MetadataLabel = labelContainerImageName
+	MetadataLabelContainerImageTag  MetadataLabel = labelContainerImageTag
+	MetadataLabelNodeName           MetadataLabel = labelNodeName
+	MetadataLabelVolumeType         MetadataLabel = labelVolumeType
+	MetadataLabelWorkloadKind       MetadataLabel = labelWorkloadKind
+	MetadataLabelWorkloadName       MetadataLabel = labelWorkloadName
 )
 
-var supportedLabels = map[MetadataLabel]bool{
-	MetadataLabelContainerID: true,
+// supportedLabels maps the supported labels to the metric groups with the
+// resources they are set on.
+var supportedLabels = map[MetadataLabel][]MetricGroup{
+	MetadataLabelContainerID:        {ContainerMetricGroup},
+	MetadataLabelContainerImageName: {ContainerMetricGroup},
+	MetadataLabelContainerImageTag:  {ContainerMetricGroup},
+	MetadataLabelNodeName:           podMetricGroups,
+	MetadataLabelVolumeType:         {VolumeMetricGroup},
+	MetadataLabelWorkloadKind:       podMetricGroups,
+	MetadataLabelWorkloadName:       podMetricGroups,
 }
 
+// podMetricGroups are the metric groups whose resources inherit the pod labels.
+var podMetricGroups = []MetricGroup{PodMetricGroup, ContainerMetricGroup, VolumeMetricGroup}
+
 // ValidateMetadataLabelsConfig validates that provided list of metadata labels is supported
 func ValidateMetadataLabelsConfig(labels []MetadataLabel) error {
 	labelsFound := map[MetadataLabel]bool{}
@@ -49,9 +69,35 @@ func ValidateMetadataLabelsConfig(labels []MetadataLabel) error {
 	return nil
 }
 
+// NeedsPodsMetadata returns true when pods metadata have to be fetched to set
+// the provided labels or to apply the extraction rules on the resources of the
+// collected metric groups.
+func NeedsPodsMetadata(labels []MetadataLabel, rules ExtractionRules, metricGroupsToCollect map[MetricGroup]bool) bool {
+	if !rules.Empty() && anyMetricGroup(podMetricGroups, metricGroupsToCollect) {
+		return true
+	}
+	for _, label := range labels {
+		if anyMetricGroup(supportedLabels[label], metricGroupsToCollect) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyMetricGroup(groups []MetricGroup, metricGroupsToCollect map[MetricGroup]bool) bool {
+	for _, group := range groups {
+		if metricGroupsToCollect[group] {
+			return true
+		}
+	}
+	return false
+}
+
 type Metadata struct {
 	Labels       []MetadataLabel
 	PodsMetadata *v1.PodList
+	// Rules extract pod labels and annotations as labels of the pod resource.
+	Rules ExtractionRules
 }
 
 func NewMetadata(labels []MetadataLabel, podsMetadata *v1.PodList) Metadata {
@@ -61,6 +107,52 @@ func NewMetadata(labels []MetadataLabel, podsMetadata *v1.PodList) Metadata {
 	}
 }
 
+// setExtraPodLabels sets extra labels of a pod in `labels` map based on available
+// metadata: its node, its workload and the values extracted from its labels and annotations.
+func (m *Metadata) setExtraPodLabels(labels map[string]string, podUID string) error {
+	var pod *v1.Pod
+	getPod := func() (*v1.Pod, error) {
+		if pod != nil {
+			return pod, nil
+		}
+		var err error
+		pod, err = m.getPod(podUID)
+		return pod, err
+	}
+
+	for _, label := range m.Labels {
+		switch label {
+		case MetadataLabelNodeName:
+			pod, err := getPod()
+			if err != nil {
+				return err
+			}
+			labels[labelNodeName] = pod.Spec.NodeName
+		case MetadataLabelWorkloadKind, MetadataLabelWorkloadName:
+			pod, err := getPod()
+			if err != nil {
+				return err
+			}
+			if kind, name := workload(pod); kind != "" {
+				if label == MetadataLabelWorkloadKind {
+					labels[labelWorkloadKind] = kind
+				} else {
+					labels[labelWorkloadName] = name
+				}
+			}
+		}
+	}
+
+	if !m.Rules.Empty() {
+		pod, err := getPod()
+		if err != nil {
+			return err
+		}
+		m.Rules.extract(pod, labels)
+	}
+	return nil
+}
+
 // setExtraLabels sets extra labels in `lables` map based on available metadata
 func (m *Metadata) setExtraLabels(labels map[string]string, podUID string, containerName string) error {
 	for _, label := range m.Labels {
@@ -71,6 +163,38 @@ func (m *Metadata) setExtraLabels(labels map[string]string, podUID string, conta
 				return err
 			}
 			labels[labelContainerID] = containerID
+		case MetadataLabelContainerImageName, MetadataLabelContainerImageTag:
+			image, err := m.getContainerImage(podUID, containerName)
+			if err != nil {
+				return err
+			}
+			name, tag := parseImage(image)
+			if label == MetadataLabelContainerImageName {
+				labels[labelContainerImageName] = name
+			} else if tag != "" {
+				labels[labelContainerImageTag] = tag
+			}
+		}
+	}
+	return nil
+}
+
+// setExtraVolumeLabels sets extra labels of a volume in `labels` map based on
+// available metadata: its type and, for a persistent volume claim, the name
+// and namespace of the claim.
+func (m *Metadata) setExtraVolumeLabels(labels map[string]string, podUID string, volumeName string) error {
+	for _, label := range m.Labels {
+		switch label {
+		case MetadataLabelVolumeType:
+			pod, volume, err := m.getVolume(podUID, volumeName)
+			if err != nil {
+				return err
+			}
+			labels[labelVolumeType] = volumeType(volume.VolumeSource)
+			if pvc := volume.PersistentVolumeClaim; pvc != nil {
+				labels[labelPVCName] = pvc.ClaimName
+				labels[labelPVCNamespace] = pod.Namespace
+			}
 			return nil
 		}
 	}
@@ -97,6 +221,108 @@ func (m *Metadata) getContainerID(podUID string, containerName string) (string,
 	return "", fmt.Errorf("pod %q with container %q not found in the fetched metadata", podUID, containerName)
 }
 
+// getPod retrieves a pod from metadata for given pod UID, returns an error if
+// no pod found in the metadata that matches the requirements.
+func (m *Metadata) getPod(podUID string) (*v1.Pod, error) {
+	if m.PodsMetadata == nil {
+		return nil, errors.New("pods metadata were not fetched")
+	}
+
+	for i := range m.PodsMetadata.Items {
+		if m.PodsMetadata.Items[i].UID == types.UID(podUID) {
+			return &m.PodsMetadata.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("pod %q not found in the fetched metadata", podUID)
+}
+
+// getContainerImage retrieves the image of a container from the pod spec in metadata for given
+// pod UID and container name, returns an error if no container found in the metadata that matches
+// the requirements.
+func (m *Metadata) getContainerImage(podUID string, containerName string) (string, error) {
+	pod, err := m.getPod(podUID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if containerName == container.Name {
+			return container.Image, nil
+		}
+	}
+
+	return "", fmt.Errorf("pod %q with container %q not found in the fetched metadata", podUID, containerName)
+}
+
+// getVolume retrieves a pod and the spec of one of its volumes from metadata for given pod UID
+// and volume name, returns an error if no volume found in the metadata that matches the requirements.
+func (m *Metadata) getVolume(podUID string, volumeName string) (*v1.Pod, *v1.Volume, error) {
+	if m.PodsMetadata == nil {
+		return nil, nil, errors.New("pods metadata were not fetched")
+	}
+
+	for i := range m.PodsMetadata.Items {
+		pod := &m.PodsMetadata.Items[i]
+		if pod.UID == types.UID(podUID) {
+			for j := range pod.Spec.Volumes {
+				if volumeName == pod.Spec.Volumes[j].Name {
+					return pod, &pod.Spec.Volumes[j], nil
+				}
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("pod %q with volume %q not found in the fetched metadata", podUID, volumeName)
+}
+
+// volumeType returns the name of the source of a volume as in the pod spec,
+// e.g. "persistentVolumeClaim" or "emptyDir". Every source is a pointer, and
+// only one of them is set.
+func volumeType(source v1.VolumeSource) string {
+	v := reflect.ValueOf(source)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			return strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return "unknown"
+}
+
+// workload returns the kind and name of the controller of a pod from its owner
+// references. A pod owned by a ReplicaSet created by a Deployment is reported as
+// owned by the Deployment, whose name is the one of the ReplicaSet without the
+// pod template hash.
+func workload(pod *v1.Pod) (kind string, name string) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		if hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok && owner.Kind == "ReplicaSet" &&
+			strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+		return owner.Kind, owner.Name
+	}
+	return "", ""
+}
+
+// parseImage splits a container image, e.g. "docker.io/library/redis:6.0",
+// into its name and tag. The tag defaults to "latest", except for an image
+// only pinned by digest, which has no tag.
+func parseImage(image string) (name string, tag string) {
+	defaultTag := "latest"
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+		defaultTag = ""
+	}
+	// a colon before the last slash separates the port of the registry
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, defaultTag
+}
+
 var containerSchemeRegexp = regexp.MustCompile(`^[\w_-]+://`)
 
 // stripContainerID returns a pure container id without the runtime scheme://
@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"encoding/json"
 
 	v1 "k8s.io/api/core/v1"
@@ -31,8 +32,8 @@ func NewMetadataProvider(rc RestClient) *MetadataProvider {
 
 // Pods calls the /pods endpoint and unmarshals the
 // results into a v1.PodList struct.
-func (p *MetadataProvider) Pods() (*v1.PodList, error) {
-	pods, err := p.rc.Pods()
+func (p *MetadataProvider) Pods(ctx context.Context) (*v1.PodList, error) {
+	pods, err := p.rc.Pods(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"io/ioutil"
 	"testing"
 
@@ -28,11 +29,11 @@ type testRestClient struct {
 	invalidJSON bool
 }
 
-func (f testRestClient) StatsSummary() ([]byte, error) {
+func (f testRestClient) StatsSummary(context.Context) ([]byte, error) {
 	return []byte{}, nil
 }
 
-func (f testRestClient) Pods() ([]byte, error) {
+func (f testRestClient) Pods(context.Context) ([]byte, error) {
 	if f.fail {
 		return []byte{}, errors.New("failed")
 	}
@@ -68,7 +69,7 @@ func TestPods(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			metadataProvider := NewMetadataProvider(tt.client)
-			podsMetadata, err := metadataProvider.Pods()
+			podsMetadata, err := metadataProvider.Pods(context.Background())
 			if tt.wantError == "" {
 				require.NoError(t, err)
 				require.Less(t, 0, len(podsMetadata.Items))
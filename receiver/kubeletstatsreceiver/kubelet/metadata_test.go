@@ -46,6 +46,11 @@ func TestValidateMetadataLabelsConfig(t *testing.T) {
 			labels:    []MetadataLabel{MetadataLabelContainerID, MetadataLabelContainerID},
 			wantError: "duplicate metadata label: \"container.id\"",
 		},
+		{
+			name:      "volume_type_valid",
+			labels:    []MetadataLabel{MetadataLabelContainerID, MetadataLabelVolumeType},
+			wantError: "",
+		},
 		{
 			name:      "unknown_label",
 			labels:    []MetadataLabel{MetadataLabel("wrong-label")},
@@ -64,6 +69,65 @@ func TestValidateMetadataLabelsConfig(t *testing.T) {
 	}
 }
 
+func TestNeedsPodsMetadata(t *testing.T) {
+	rules, err := NewExtractionRules(ExtractConfig{Labels: []FieldExtractConfig{{Key: "app"}}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		labels       []MetadataLabel
+		rules        ExtractionRules
+		metricGroups []MetricGroup
+		want         bool
+	}{
+		{
+			name:         "no_labels",
+			metricGroups: DefaultMetricGroups,
+			want:         false,
+		},
+		{
+			name:         "container_id_with_container_metrics",
+			labels:       []MetadataLabel{MetadataLabelContainerID},
+			metricGroups: []MetricGroup{ContainerMetricGroup},
+			want:         true,
+		},
+		{
+			name:         "container_id_without_container_metrics",
+			labels:       []MetadataLabel{MetadataLabelContainerID},
+			metricGroups: []MetricGroup{NodeMetricGroup, PodMetricGroup, VolumeMetricGroup},
+			want:         false,
+		},
+		{
+			name:         "node_name_with_volume_metrics",
+			labels:       []MetadataLabel{MetadataLabelNodeName},
+			metricGroups: []MetricGroup{VolumeMetricGroup},
+			want:         true,
+		},
+		{
+			name:         "rules_with_pod_metrics",
+			rules:        rules,
+			metricGroups: []MetricGroup{PodMetricGroup},
+			want:         true,
+		},
+		{
+			name:         "rules_with_node_metrics",
+			labels:       []MetadataLabel{MetadataLabelWorkloadName},
+			rules:        rules,
+			metricGroups: []MetricGroup{NodeMetricGroup},
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricGroups := map[MetricGroup]bool{}
+			for _, group := range tt.metricGroups {
+				metricGroups[group] = true
+			}
+			assert.Equal(t, tt.want, NeedsPodsMetadata(tt.labels, tt.rules, metricGroups))
+		})
+	}
+}
+
 func TestSetExtraLabels(t *testing.T) {
 
 	tests := []struct {
@@ -109,6 +173,44 @@ func TestSetExtraLabels(t *testing.T) {
 				string(MetadataLabelContainerID): "test-container",
 			},
 		},
+		{
+			name: "set_container_id_and_image",
+			metadata: NewMetadata(
+				[]MetadataLabel{MetadataLabelContainerID, MetadataLabelContainerImageName, MetadataLabelContainerImageTag},
+				&v1.PodList{
+					Items: []v1.Pod{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								UID: types.UID("uid-1234"),
+							},
+							Spec: v1.PodSpec{
+								Containers: []v1.Container{
+									{
+										Name:  "container1",
+										Image: "quay.io/prometheus/node-exporter:v1.0.1",
+									},
+								},
+							},
+							Status: v1.PodStatus{
+								ContainerStatuses: []v1.ContainerStatus{
+									{
+										Name:        "container1",
+										ContainerID: "docker://test-container",
+									},
+								},
+							},
+						},
+					},
+				},
+			),
+			podUID:        "uid-1234",
+			containerName: "container1",
+			want: map[string]string{
+				"container.id":         "test-container",
+				"container.image.name": "quay.io/prometheus/node-exporter",
+				"container.image.tag":  "v1.0.1",
+			},
+		},
 		{
 			name:          "set_container_id_no_metadata",
 			metadata:      NewMetadata([]MetadataLabel{MetadataLabelContainerID}, nil),
@@ -156,3 +258,240 @@ func TestSetExtraLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestSetExtraVolumeLabels(t *testing.T) {
+	podsMetadata := &v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:       types.UID("uid-1234"),
+					Namespace: "db",
+				},
+				Spec: v1.PodSpec{
+					Volumes: []v1.Volume{
+						{
+							Name: "data",
+							VolumeSource: v1.VolumeSource{
+								PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+									ClaimName: "data-postgres-0",
+								},
+							},
+						},
+						{
+							Name: "scratch",
+							VolumeSource: v1.VolumeSource{
+								EmptyDir: &v1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		metadata   Metadata
+		podUID     string
+		volumeName string
+		wantError  string
+		want       map[string]string
+	}{
+		{
+			name:       "no_labels",
+			metadata:   NewMetadata([]MetadataLabel{MetadataLabelContainerID}, podsMetadata),
+			podUID:     "uid-1234",
+			volumeName: "data",
+			want:       map[string]string{},
+		},
+		{
+			name:       "set_volume_type_pvc",
+			metadata:   NewMetadata([]MetadataLabel{MetadataLabelContainerID, MetadataLabelVolumeType}, podsMetadata),
+			podUID:     "uid-1234",
+			volumeName: "data",
+			want: map[string]string{
+				"k8s.volume.type":                     "persistentVolumeClaim",
+				"k8s.persistentvolumeclaim.name":      "data-postgres-0",
+				"k8s.persistentvolumeclaim.namespace": "db",
+			},
+		},
+		{
+			name:       "set_volume_type_empty_dir",
+			metadata:   NewMetadata([]MetadataLabel{MetadataLabelVolumeType}, podsMetadata),
+			podUID:     "uid-1234",
+			volumeName: "scratch",
+			want: map[string]string{
+				"k8s.volume.type": "emptyDir",
+			},
+		},
+		{
+			name:       "set_volume_type_no_metadata",
+			metadata:   NewMetadata([]MetadataLabel{MetadataLabelVolumeType}, nil),
+			podUID:     "uid-1234",
+			volumeName: "data",
+			wantError:  "pods metadata were not fetched",
+		},
+		{
+			name:       "set_volume_type_not_found",
+			metadata:   NewMetadata([]MetadataLabel{MetadataLabelVolumeType}, podsMetadata),
+			podUID:     "uid-1234",
+			volumeName: "config",
+			wantError:  "pod \"uid-1234\" with volume \"config\" not found in the fetched metadata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]string{}
+			err := tt.metadata.setExtraVolumeLabels(fields, tt.podUID, tt.volumeName)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+				assert.EqualValues(t, tt.want, fields)
+			} else {
+				assert.Equal(t, tt.wantError, err.Error())
+			}
+		})
+	}
+}
+
+func TestSetExtraPodLabels(t *testing.T) {
+	controller := true
+	podsMetadata := &v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: types.UID("uid-1234"),
+					Labels: map[string]string{
+						"app":               "web",
+						"pod-template-hash": "5d8f9c7b6",
+					},
+					Annotations: map[string]string{
+						"kubernetes.io/change-cause": "GIT_SHA=58a1e39 CI_BUILD=4120",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "ReplicaSet",
+							Name:       "web-5d8f9c7b6",
+							Controller: &controller,
+						},
+					},
+				},
+				Spec: v1.PodSpec{
+					NodeName: "node-1",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: types.UID("uid-5678"),
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind: "ConfigMap",
+							Name: "owner",
+						},
+						{
+							Kind:       "StatefulSet",
+							Name:       "db",
+							Controller: &controller,
+						},
+					},
+				},
+			},
+		},
+	}
+	rules, err := NewExtractionRules(ExtractConfig{
+		Labels: []FieldExtractConfig{{Key: "app"}},
+		Annotations: []FieldExtractConfig{
+			{TagName: "git.sha", Key: "kubernetes.io/change-cause", Regex: `GIT_SHA=(?P<value>\w+)`},
+		},
+	})
+	require.NoError(t, err)
+	allLabels := []MetadataLabel{MetadataLabelNodeName, MetadataLabelWorkloadKind, MetadataLabelWorkloadName}
+
+	tests := []struct {
+		name      string
+		metadata  Metadata
+		podUID    string
+		wantError string
+		want      map[string]string
+	}{
+		{
+			name:     "no_labels",
+			metadata: NewMetadata([]MetadataLabel{MetadataLabelContainerID}, nil),
+			podUID:   "uid-1234",
+			want:     map[string]string{},
+		},
+		{
+			name:     "deployment",
+			metadata: NewMetadata(allLabels, podsMetadata),
+			podUID:   "uid-1234",
+			want: map[string]string{
+				"k8s.node.name":     "node-1",
+				"k8s.workload.kind": "Deployment",
+				"k8s.workload.name": "web",
+			},
+		},
+		{
+			name:     "statefulset",
+			metadata: NewMetadata([]MetadataLabel{MetadataLabelWorkloadKind, MetadataLabelWorkloadName}, podsMetadata),
+			podUID:   "uid-5678",
+			want: map[string]string{
+				"k8s.workload.kind": "StatefulSet",
+				"k8s.workload.name": "db",
+			},
+		},
+		{
+			name:     "extraction_rules",
+			metadata: Metadata{PodsMetadata: podsMetadata, Rules: rules},
+			podUID:   "uid-1234",
+			want: map[string]string{
+				"k8s.label.app": "web",
+				"git.sha":       "58a1e39",
+			},
+		},
+		{
+			name:      "no_metadata",
+			metadata:  Metadata{Rules: rules},
+			podUID:    "uid-1234",
+			wantError: "pods metadata were not fetched",
+		},
+		{
+			name:      "not_found",
+			metadata:  NewMetadata(allLabels, podsMetadata),
+			podUID:    "uid-0000",
+			wantError: "pod \"uid-0000\" not found in the fetched metadata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]string{}
+			err := tt.metadata.setExtraPodLabels(fields, tt.podUID)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+				assert.EqualValues(t, tt.want, fields)
+			} else {
+				assert.Equal(t, tt.wantError, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{"redis", "redis", "latest"},
+		{"redis:6.0", "redis", "6.0"},
+		{"localhost:5000/team/app", "localhost:5000/team/app", "latest"},
+		{"localhost:5000/team/app:1.2", "localhost:5000/team/app", "1.2"},
+		{"redis@sha256:0ed5d5928d47", "redis", ""},
+		{"redis:6.0@sha256:0ed5d5928d47", "redis", "6.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := parseImage(tt.image)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
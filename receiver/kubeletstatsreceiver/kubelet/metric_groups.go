@@ -0,0 +1,63 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import "fmt"
+
+// MetricGroup is a group of metrics that can be collected, named after the
+// resource the metrics are emitted for.
+type MetricGroup string
+
+const (
+	// NodeMetricGroup is the metrics of the node and of its system containers.
+	NodeMetricGroup MetricGroup = "node"
+	// PodMetricGroup is the metrics of the pods.
+	PodMetricGroup MetricGroup = "pod"
+	// ContainerMetricGroup is the metrics of the containers of the pods.
+	ContainerMetricGroup MetricGroup = "container"
+	// VolumeMetricGroup is the metrics of the volumes of the pods.
+	VolumeMetricGroup MetricGroup = "volume"
+)
+
+// DefaultMetricGroups are the metric groups collected when none is configured.
+var DefaultMetricGroups = []MetricGroup{
+	NodeMetricGroup,
+	PodMetricGroup,
+	ContainerMetricGroup,
+	VolumeMetricGroup,
+}
+
+var supportedMetricGroups = map[MetricGroup]bool{
+	NodeMetricGroup:      true,
+	PodMetricGroup:       true,
+	ContainerMetricGroup: true,
+	VolumeMetricGroup:    true,
+}
+
+// ValidateMetricGroupsConfig validates that provided list of metric groups is supported
+func ValidateMetricGroupsConfig(groups []MetricGroup) error {
+	groupsFound := map[MetricGroup]bool{}
+	for _, group := range groups {
+		if _, supported := supportedMetricGroups[group]; supported {
+			if _, duplicate := groupsFound[group]; duplicate {
+				return fmt.Errorf("duplicate metric group: %q", group)
+			}
+			groupsFound[group] = true
+		} else {
+			return fmt.Errorf("metric group %q is not supported", group)
+		}
+	}
+	return nil
+}
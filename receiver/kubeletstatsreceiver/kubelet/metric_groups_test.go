@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetricGroupsConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		groups    []MetricGroup
+		wantError string
+	}{
+		{
+			name:   "no_groups",
+			groups: []MetricGroup{},
+		},
+		{
+			name:   "all_groups",
+			groups: []MetricGroup{NodeMetricGroup, PodMetricGroup, ContainerMetricGroup, VolumeMetricGroup},
+		},
+		{
+			name:      "duplicate_group",
+			groups:    []MetricGroup{PodMetricGroup, PodMetricGroup},
+			wantError: "duplicate metric group: \"pod\"",
+		},
+		{
+			name:      "unknown_group",
+			groups:    []MetricGroup{MetricGroup("cluster")},
+			wantError: "metric group \"cluster\" is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMetricGroupsConfig(tt.groups)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.wantError, err.Error())
+			}
+		})
+	}
+}
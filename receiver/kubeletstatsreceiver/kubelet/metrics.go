@@ -20,18 +20,28 @@ import (
 	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 )
 
-func MetricsData(logger *zap.Logger, summary *stats.Summary, metadata Metadata, typeStr string) []*consumerdata.MetricsData {
+// MetricsData returns the metrics of the collected metric groups from a
+// /stats/summary response, one MetricsData per resource.
+func MetricsData(
+	logger *zap.Logger, summary *stats.Summary, metadata Metadata, typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool,
+) []*consumerdata.MetricsData {
 	acc := &metricDataAccumulator{
 		metadata: metadata,
 		logger:   logger,
 	}
-	acc.nodeStats(summary.Node)
-	for _, podStats := range summary.Pods {
-		// propagate the pod resource down to the container
-		podResource := podResource(podStats)
-		acc.podStats(podResource, podStats)
-		for _, containerStats := range podStats.Containers {
-			acc.containerStats(podResource, containerStats)
+	if metricGroupsToCollect[NodeMetricGroup] {
+		// propagate the node resource down to the system containers
+		nodeResource := nodeResource(summary.Node)
+		acc.nodeStats(nodeResource, summary.Node)
+		for _, systemContainerStats := range summary.Node.SystemContainers {
+			acc.systemContainerStats(nodeResource, systemContainerStats)
+		}
+	}
+	// the pod resource is only built when the metrics of the pods, their containers or their volumes are collected
+	if anyMetricGroup(podMetricGroups, metricGroupsToCollect) {
+		for _, podStats := range summary.Pods {
+			acc.podsStats(podStats, metricGroupsToCollect)
 		}
 	}
 	for _, md := range acc.m {
@@ -39,5 +49,4 @@ func MetricsData(logger *zap.Logger, summary *stats.Summary, metadata Metadata,
 		md.Resource.Labels["receiver"] = typeStr
 	}
 	return acc.m
-
 }
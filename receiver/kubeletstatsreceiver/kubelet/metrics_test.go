@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"io/ioutil"
 	"testing"
 
@@ -28,22 +29,55 @@ import (
 type fakeRestClient struct {
 }
 
-func (f fakeRestClient) StatsSummary() ([]byte, error) {
+func (f fakeRestClient) StatsSummary(context.Context) ([]byte, error) {
 	return ioutil.ReadFile("../testdata/stats-summary.json")
 }
 
-func (f fakeRestClient) Pods() ([]byte, error) {
+func (f fakeRestClient) Pods(context.Context) ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
 	statsProvider := NewStatsProvider(rc)
-	summary, _ := statsProvider.StatsSummary()
+	summary, _ := statsProvider.StatsSummary(context.Background())
 	metadataProvider := NewMetadataProvider(rc)
-	podsMetadata, _ := metadataProvider.Pods()
+	podsMetadata, _ := metadataProvider.Pods(context.Background())
 	metadata := NewMetadata([]MetadataLabel{MetadataLabelContainerID}, podsMetadata)
-	requireMetricsDataOk(t, MetricsData(zap.NewNop(), summary, metadata, ""))
+	requireMetricsDataOk(t, MetricsData(zap.NewNop(), summary, metadata, "", allMetricGroups()))
+}
+
+func TestMetricGroups(t *testing.T) {
+	rc := &fakeRestClient{}
+	statsProvider := NewStatsProvider(rc)
+	summary, _ := statsProvider.StatsSummary(context.Background())
+	metadata := NewMetadata([]MetadataLabel{}, nil)
+
+	tests := []struct {
+		group   MetricGroup
+		dataLen int
+	}{
+		// the node and its 3 system containers
+		{NodeMetricGroup, 4},
+		{PodMetricGroup, 9},
+		{ContainerMetricGroup, 9},
+		{VolumeMetricGroup, 8},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.group), func(t *testing.T) {
+			mds := MetricsData(zap.NewNop(), summary, metadata, "", map[MetricGroup]bool{tt.group: true})
+			require.Equal(t, tt.dataLen, len(mds))
+			requireMetricsDataOk(t, mds)
+		})
+	}
+}
+
+func allMetricGroups() map[MetricGroup]bool {
+	out := map[MetricGroup]bool{}
+	for _, group := range DefaultMetricGroups {
+		out[group] = true
+	}
+	return out
 }
 
 func requireMetricsDataOk(t *testing.T, mds []*consumerdata.MetricsData) {
@@ -29,15 +29,32 @@ func nodeResource(s stats.NodeStats) *resourcepb.Resource {
 	}
 }
 
-func podResource(s stats.PodStats) *resourcepb.Resource {
+func systemContainerResource(node *resourcepb.Resource, s stats.ContainerStats) *resourcepb.Resource {
+	labels := map[string]string{}
+	for k, v := range node.Labels {
+		labels[k] = v
+	}
+	labels[labelSystemContainerName] = s.Name
 	return &resourcepb.Resource{
-		Type: "k8s", // k8s/pod
-		Labels: map[string]string{
-			labelPodUID:        s.PodRef.UID,
-			labelPodName:       s.PodRef.Name,
-			labelNamespaceName: s.PodRef.Namespace,
-		},
+		Type:   "k8s", // k8s/node/system_container
+		Labels: labels,
+	}
+}
+
+func podResource(s stats.PodStats, metadata Metadata) (*resourcepb.Resource, error) {
+	labels := map[string]string{
+		labelPodUID:        s.PodRef.UID,
+		labelPodName:       s.PodRef.Name,
+		labelNamespaceName: s.PodRef.Namespace,
 	}
+	err := metadata.setExtraPodLabels(labels, s.PodRef.UID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to set extra labels from metadata")
+	}
+	return &resourcepb.Resource{
+		Type:   "k8s", // k8s/pod
+		Labels: labels,
+	}, nil
 }
 
 func containerResource(pod *resourcepb.Resource, s stats.ContainerStats, metadata Metadata) (*resourcepb.Resource, error) {
@@ -57,3 +74,24 @@ func containerResource(pod *resourcepb.Resource, s stats.ContainerStats, metadat
 		Labels: labels,
 	}, nil
 }
+
+func volumeResource(pod *resourcepb.Resource, s stats.VolumeStats, metadata Metadata) (*resourcepb.Resource, error) {
+	labels := map[string]string{}
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	// augment the volume resource with pod labels
+	labels[labelVolumeName] = s.Name
+	if s.PVCRef != nil {
+		labels[labelPVCName] = s.PVCRef.Name
+		labels[labelPVCNamespace] = s.PVCRef.Namespace
+	}
+	err := metadata.setExtraVolumeLabels(labels, labels[labelPodUID], labels[labelVolumeName])
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to set extra labels from metadata")
+	}
+	return &resourcepb.Resource{
+		Type:   "k8s", // k8s/pod/volume
+		Labels: labels,
+	}, nil
+}
@@ -14,10 +14,12 @@
 
 package kubelet
 
+import "context"
+
 // RestClient is swappable for testing.
 type RestClient interface {
-	StatsSummary() ([]byte, error)
-	Pods() ([]byte, error)
+	StatsSummary(ctx context.Context) ([]byte, error)
+	Pods(ctx context.Context) ([]byte, error)
 }
 
 // RestClient is a thin wrapper around a kubelet client, encapsulating endpoints
@@ -32,10 +34,10 @@ func NewRestClient(client Client) *HTTPRestClient {
 	return &HTTPRestClient{client: client}
 }
 
-func (c *HTTPRestClient) StatsSummary() ([]byte, error) {
-	return c.client.Get("/stats/summary")
+func (c *HTTPRestClient) StatsSummary(ctx context.Context) ([]byte, error) {
+	return c.client.Get(ctx, "/stats/summary")
 }
 
-func (c *HTTPRestClient) Pods() ([]byte, error) {
-	return c.client.Get("/pods")
+func (c *HTTPRestClient) Pods(ctx context.Context) ([]byte, error) {
+	return c.client.Get(ctx, "/pods")
 }
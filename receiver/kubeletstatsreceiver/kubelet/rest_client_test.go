@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,9 +23,9 @@ import (
 
 func TestRestClient(t *testing.T) {
 	rest := NewRestClient(&fakeClient{})
-	resp, _ := rest.StatsSummary()
+	resp, _ := rest.StatsSummary(context.Background())
 	require.Equal(t, "/stats/summary", string(resp))
-	resp, _ = rest.Pods()
+	resp, _ = rest.Pods(context.Background())
 	require.Equal(t, "/pods", string(resp))
 }
 
@@ -32,6 +33,6 @@ var _ Client = (*fakeClient)(nil)
 
 type fakeClient struct{}
 
-func (f *fakeClient) Get(path string) ([]byte, error) {
+func (f *fakeClient) Get(_ context.Context, path string) ([]byte, error) {
 	return []byte(path), nil
 }
@@ -15,6 +15,7 @@
 package kubelet
 
 import (
+	"context"
 	"encoding/json"
 
 	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
@@ -32,8 +33,8 @@ func NewStatsProvider(rc RestClient) *StatsProvider {
 
 // StatsSummary calls the /stats/summary kubelet endpoint and unmarshals the
 // results into a stats.Summary struct.
-func (p *StatsProvider) StatsSummary() (*stats.Summary, error) {
-	summary, err := p.rc.StatsSummary()
+func (p *StatsProvider) StatsSummary(ctx context.Context) (*stats.Summary, error) {
+	summary, err := p.rc.StatsSummary(ctx)
 	if err != nil {
 		return nil, err
 	}
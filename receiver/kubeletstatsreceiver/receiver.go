@@ -21,35 +21,39 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
 var _ component.MetricsReceiver = (*receiver)(nil)
 
 type receiver struct {
-	cfg      *Config
-	logger   *zap.Logger
-	consumer consumer.MetricsConsumerOld
-	runner   *interval.Runner
-	rest     kubelet.RestClient
+	cfg                   *Config
+	logger                *zap.Logger
+	consumer              consumer.MetricsConsumerOld
+	runner                *interval.Runner
+	rest                  kubelet.RestClient
+	rules                 kubelet.ExtractionRules
+	metricGroupsToCollect map[kubelet.MetricGroup]bool
 }
 
 // Creates and starts the kubelet stats runnable.
 func (r *receiver) Start(ctx context.Context, host component.Host) error {
-	runnable := newRunnable(ctx, r.cfg.Name(), r.consumer, r.rest, r.cfg.ExtraMetadataLabels, r.logger)
-	r.runner = interval.NewRunner(r.cfg.CollectionInterval, runnable)
-
-	go func() {
-		if err := r.runner.Start(); err != nil {
-			host.ReportFatalError(err)
-		}
-	}()
-	return nil
+	runnable := newRunnable(r.cfg.Name(), r.consumer, r.rest, r.cfg.ExtraMetadataLabels, r.rules, r.metricGroupsToCollect, r.logger)
+	r.runner = interval.NewRunner(
+		r.cfg.CollectionInterval,
+		[]interval.Runnable{runnable},
+		interval.WithErrorHandler(func(err error) {
+			r.logger.Error("failed to scrape kubelet stats", zap.Error(err))
+		}),
+	)
+	return r.runner.Start()
 }
 
-// Stops the kubelet stats runner.
+// Stops the kubelet stats runner, waiting for the scrape in progress.
 func (r *receiver) Shutdown(ctx context.Context) error {
-	r.runner.Stop()
+	if r.runner != nil {
+		r.runner.Stop()
+	}
 	return nil
 }
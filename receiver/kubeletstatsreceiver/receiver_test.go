@@ -17,11 +17,13 @@ package kubeletstatsreceiver
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestMetricsReceiver(t *testing.T) {
@@ -39,3 +41,23 @@ func TestMetricsReceiver(t *testing.T) {
 	err = metricsReceiver.Shutdown(ctx)
 	require.NoError(t, err)
 }
+
+func TestMetricsReceiverScrapeError(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	core, observedLogs := observer.New(zap.ErrorLevel)
+	metricsReceiver := receiver{
+		cfg:      cfg,
+		logger:   zap.New(core),
+		consumer: &testbed.MockMetricConsumer{},
+		rest:     &fakeRestClient{statsSummaryFail: true},
+	}
+	ctx := context.Background()
+	require.NoError(t, metricsReceiver.Start(ctx, componenttest.NewNopHost()))
+	defer metricsReceiver.Shutdown(ctx)
+	// The first scrape runs right after Start.
+	require.Eventually(t, func() bool {
+		return observedLogs.Len() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "failed to scrape kubelet stats", observedLogs.All()[0].Message)
+}
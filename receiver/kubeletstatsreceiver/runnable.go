@@ -16,45 +16,48 @@ package kubeletstatsreceiver
 
 import (
 	"context"
+	"fmt"
 
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
-	// todo replace with scraping lib when it's ready
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
 var _ interval.Runnable = (*runnable)(nil)
 
 type runnable struct {
-	ctx                 context.Context
-	receiverName        string
-	statsProvider       *kubelet.StatsProvider
-	metadataProvider    *kubelet.MetadataProvider
-	consumer            consumer.MetricsConsumerOld
-	logger              *zap.Logger
-	restClient          kubelet.RestClient
-	extraMetadataLabels []kubelet.MetadataLabel
+	receiverName          string
+	statsProvider         *kubelet.StatsProvider
+	metadataProvider      *kubelet.MetadataProvider
+	consumer              consumer.MetricsConsumerOld
+	logger                *zap.Logger
+	restClient            kubelet.RestClient
+	extraMetadataLabels   []kubelet.MetadataLabel
+	extractionRules       kubelet.ExtractionRules
+	metricGroupsToCollect map[kubelet.MetricGroup]bool
 }
 
 func newRunnable(
-	ctx context.Context,
 	receiverName string,
 	consumer consumer.MetricsConsumerOld,
 	restClient kubelet.RestClient,
 	extraMetadataLabels []kubelet.MetadataLabel,
+	extractionRules kubelet.ExtractionRules,
+	metricGroupsToCollect map[kubelet.MetricGroup]bool,
 	logger *zap.Logger,
 ) *runnable {
 	return &runnable{
-		ctx:                 ctx,
-		receiverName:        receiverName,
-		consumer:            consumer,
-		restClient:          restClient,
-		logger:              logger,
-		extraMetadataLabels: extraMetadataLabels,
+		receiverName:          receiverName,
+		consumer:              consumer,
+		restClient:            restClient,
+		logger:                logger,
+		extraMetadataLabels:   extraMetadataLabels,
+		extractionRules:       extractionRules,
+		metricGroupsToCollect: metricGroupsToCollect,
 	}
 }
 
@@ -65,27 +68,27 @@ func (r *runnable) Setup() error {
 	return nil
 }
 
-func (r *runnable) Run() error {
+func (r *runnable) Run(ctx context.Context) error {
 	const transport = "http"
-	summary, err := r.statsProvider.StatsSummary()
+	summary, err := r.statsProvider.StatsSummary(ctx)
 	if err != nil {
-		r.logger.Error("call to /stats/summary endpoint failed", zap.Error(err))
-		return nil
+		return fmt.Errorf("call to /stats/summary endpoint failed: %v", err)
 	}
 
 	var podsMetadata *v1.PodList
-	// fetch metadata only when extra metadata labels are needed
-	if len(r.extraMetadataLabels) > 0 {
-		podsMetadata, err = r.metadataProvider.Pods()
+	// fetch metadata only when extra metadata labels or extraction rules are needed
+	// by the collected metric groups
+	if kubelet.NeedsPodsMetadata(r.extraMetadataLabels, r.extractionRules, r.metricGroupsToCollect) {
+		podsMetadata, err = r.metadataProvider.Pods(ctx)
 		if err != nil {
-			r.logger.Error("call to /pods endpoint failed", zap.Error(err))
-			return nil
+			return fmt.Errorf("call to /pods endpoint failed: %v", err)
 		}
 	}
 
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata)
-	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr)
-	ctx := obsreport.ReceiverContext(r.ctx, typeStr, transport, r.receiverName)
+	metadata.Rules = r.extractionRules
+	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
+	ctx = obsreport.ReceiverContext(ctx, typeStr, transport, r.receiverName)
 	for _, md := range mds {
 		ctx = obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
 		err = r.consumer.ConsumeMetricsData(ctx, *md)
@@ -29,21 +29,30 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 )
 
-const dataLen = 19
+// 1 node, 3 system containers, 9 pods, 9 containers and 8 volumes
+const dataLen = 30
+
+var allMetricGroups = map[kubelet.MetricGroup]bool{
+	kubelet.NodeMetricGroup:      true,
+	kubelet.PodMetricGroup:       true,
+	kubelet.ContainerMetricGroup: true,
+	kubelet.VolumeMetricGroup:    true,
+}
 
 func TestRunnable(t *testing.T) {
 	consumer := &fakeConsumer{}
 	r := newRunnable(
-		context.Background(),
 		"",
 		consumer,
 		&fakeRestClient{},
 		nil,
+		kubelet.ExtractionRules{},
+		allMetricGroups,
 		zap.NewNop(),
 	)
 	err := r.Setup()
 	require.NoError(t, err)
-	err = r.Run()
+	err = r.Run(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, dataLen, len(consumer.mds))
 }
@@ -51,16 +60,17 @@ func TestRunnable(t *testing.T) {
 func TestRunnableWithMetadata(t *testing.T) {
 	consumer := &fakeConsumer{}
 	r := newRunnable(
-		context.Background(),
 		"",
 		consumer,
 		&fakeRestClient{},
 		[]kubelet.MetadataLabel{kubelet.MetadataLabelContainerID},
+		kubelet.ExtractionRules{},
+		allMetricGroups,
 		zap.NewNop(),
 	)
 	err := r.Setup()
 	require.NoError(t, err)
-	err = r.Run()
+	err = r.Run(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, dataLen, len(consumer.mds))
 
@@ -76,24 +86,56 @@ func TestRunnableWithMetadata(t *testing.T) {
 	}
 }
 
+func TestRunnableMetricGroups(t *testing.T) {
+	tests := []struct {
+		name         string
+		metricGroups map[kubelet.MetricGroup]bool
+		dataLen      int
+		wantErr      bool
+	}{
+		{"node", map[kubelet.MetricGroup]bool{kubelet.NodeMetricGroup: true}, 4, false},
+		{"pod", map[kubelet.MetricGroup]bool{kubelet.PodMetricGroup: true}, 9, false},
+		// container.id is set on the container metrics only, so /pods is called and fails
+		{"container", map[kubelet.MetricGroup]bool{kubelet.ContainerMetricGroup: true}, 0, true},
+		{"node_and_volume", map[kubelet.MetricGroup]bool{kubelet.NodeMetricGroup: true, kubelet.VolumeMetricGroup: true}, 12, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consumer := &fakeConsumer{}
+			r := newRunnable(
+				"",
+				consumer,
+				&fakeRestClient{podsFail: true},
+				[]kubelet.MetadataLabel{kubelet.MetadataLabelContainerID},
+				kubelet.ExtractionRules{},
+				test.metricGroups,
+				zap.NewNop(),
+			)
+			err := r.Setup()
+			require.NoError(t, err)
+			err = r.Run(context.Background())
+			require.Equal(t, test.wantErr, err != nil)
+			require.Equal(t, test.dataLen, len(consumer.mds))
+		})
+	}
+}
+
 func TestClientErrors(t *testing.T) {
 	tests := []struct {
 		name                string
 		statsSummaryFail    bool
 		podsFail            bool
 		extraMetadataLabels []kubelet.MetadataLabel
-		numLogs             int
+		wantErr             string
 	}{
-		{"no_errors_without_metadata", false, false, nil, 0},
-		{"no_errors_with_metadata", false, false, []kubelet.MetadataLabel{kubelet.MetadataLabelContainerID}, 0},
-		{"stats_summary_endpoint_error", true, false, nil, 1},
-		{"pods_endpoint_error", false, true, []kubelet.MetadataLabel{kubelet.MetadataLabelContainerID}, 1},
+		{"no_errors_without_metadata", false, false, nil, ""},
+		{"no_errors_with_metadata", false, false, []kubelet.MetadataLabel{kubelet.MetadataLabelContainerID}, ""},
+		{"stats_summary_endpoint_error", true, false, nil, "call to /stats/summary endpoint failed"},
+		{"pods_endpoint_error", false, true, []kubelet.MetadataLabel{kubelet.MetadataLabelContainerID}, "call to /pods endpoint failed"},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			core, observedLogs := observer.New(zap.ErrorLevel)
 			r := newRunnable(
-				context.Background(),
 				"",
 				&fakeConsumer{},
 				&fakeRestClient{
@@ -101,13 +143,19 @@ func TestClientErrors(t *testing.T) {
 					podsFail:         test.podsFail,
 				},
 				test.extraMetadataLabels,
-				zap.New(core),
+				kubelet.ExtractionRules{},
+				allMetricGroups,
+				zap.NewNop(),
 			)
 			err := r.Setup()
 			require.NoError(t, err)
-			err = r.Run()
-			require.NoError(t, err)
-			require.Equal(t, test.numLogs, observedLogs.Len())
+			err = r.Run(context.Background())
+			if test.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.wantErr)
 		})
 	}
 }
@@ -125,16 +173,17 @@ func TestConsumerErrors(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			core, observedLogs := observer.New(zap.ErrorLevel)
 			r := newRunnable(
-				context.Background(),
 				"",
 				&fakeConsumer{fail: test.fail},
 				&fakeRestClient{},
 				nil,
+				kubelet.ExtractionRules{},
+				allMetricGroups,
 				zap.New(core),
 			)
 			err := r.Setup()
 			require.NoError(t, err)
-			err = r.Run()
+			err = r.Run(context.Background())
 			require.NoError(t, err)
 			require.Equal(t, test.numLogs, observedLogs.Len())
 		})
@@ -164,14 +213,14 @@ type fakeRestClient struct {
 	podsFail         bool
 }
 
-func (f *fakeRestClient) StatsSummary() ([]byte, error) {
+func (f *fakeRestClient) StatsSummary(context.Context) ([]byte, error) {
 	if f.statsSummaryFail {
 		return nil, errors.New("")
 	}
 	return ioutil.ReadFile("testdata/stats-summary.json")
 }
 
-func (f *fakeRestClient) Pods() ([]byte, error) {
+func (f *fakeRestClient) Pods(context.Context) ([]byte, error) {
 	if f.podsFail {
 		return nil, errors.New("")
 	}
@@ -0,0 +1,32 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
+)
+
+// Config defines configuration for the prometheus_exec receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+	// ScrapeInterval is the time between each scrape of the exporter.
+	ScrapeInterval time.Duration `mapstructure:"scrape_interval"`
+	// SubprocessConfig is the configuration of the exporter to run.
+	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+}
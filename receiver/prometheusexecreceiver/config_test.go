@@ -0,0 +1,97 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := &Factory{}
+	factories.Receivers[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 5)
+
+	r0 := cfg.Receivers[typeStr]
+	assert.Equal(t, factory.CreateDefaultConfig(), r0)
+
+	r1 := cfg.Receivers["prometheus_exec/test"].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "prometheus_exec/test",
+			},
+			ScrapeInterval: defaultScrapeInterval,
+			SubprocessConfig: subprocessmanager.SubprocessConfig{
+				Command: "mysqld_exporter",
+				Port:    9104,
+			},
+		},
+		r1)
+
+	r2 := cfg.Receivers["prometheus_exec/test2"].(*Config)
+	assert.Equal(t, 90*time.Second, r2.ScrapeInterval)
+	assert.Equal(t, "postgres_exporter", r2.SubprocessConfig.Command)
+	assert.Equal(t, 0, r2.SubprocessConfig.Port)
+
+	r3 := cfg.Receivers["prometheus_exec/end_to_end_test/1"].(*Config)
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "prometheus_exec/end_to_end_test/1",
+			},
+			ScrapeInterval: 2 * time.Second,
+			SubprocessConfig: subprocessmanager.SubprocessConfig{
+				Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go 9999",
+				Port:    9999,
+				Env: []subprocessmanager.EnvConfig{
+					{
+						Name:  "DATA_SOURCE_NAME",
+						Value: "user:password@(hostname:port)/dbname",
+					},
+					{
+						Name:  "SECONDARY_PORT",
+						Value: "1234",
+					},
+				},
+			},
+		},
+		r3)
+
+	r4 := cfg.Receivers["prometheus_exec/end_to_end_test/2"].(*Config)
+	assert.Equal(t, "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}", r4.SubprocessConfig.Command)
+	assert.Equal(t, 0, r4.SubprocessConfig.Port)
+}
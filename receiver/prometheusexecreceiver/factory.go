@@ -0,0 +1,88 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+)
+
+// This file implements factory for prometheus_exec receiver
+const (
+	// The value of "type" key in configuration.
+	typeStr = "prometheus_exec"
+
+	defaultScrapeInterval = 60 * time.Second
+	defaultScrapeTimeout  = 10 * time.Second
+	defaultMetricsPath    = "/metrics"
+)
+
+// Factory is the factory for prometheus_exec receiver.
+type Factory struct {
+}
+
+var _ component.ReceiverFactoryOld = (*Factory)(nil)
+
+// Type gets the type of the Receiver Config created by this factory.
+func (f *Factory) Type() configmodels.Type {
+	return typeStr
+}
+
+// CustomUnmarshaler returns nil because we don't need custom unmarshaling for this Config.
+func (f *Factory) CustomUnmarshaler() component.CustomUnmarshaler {
+	return nil
+}
+
+// CreateDefaultConfig creates the default configuration for prometheus_exec receiver.
+func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		ScrapeInterval: defaultScrapeInterval,
+	}
+}
+
+// CreateTraceReceiver returns an error because traces are not supported.
+func (f *Factory) CreateTraceReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumerOld,
+) (component.TraceReceiver, error) {
+	return nil, configerror.ErrDataTypeIsNotSupported
+}
+
+// CreateMetricsReceiver creates a metrics receiver based on provided Config.
+func (f *Factory) CreateMetricsReceiver(
+	ctx context.Context,
+	logger *zap.Logger,
+	cfg configmodels.Receiver,
+	consumer consumer.MetricsConsumerOld,
+) (component.MetricsReceiver, error) {
+	rCfg := cfg.(*Config)
+	if rCfg.SubprocessConfig.Command == "" {
+		return nil, fmt.Errorf("no command to execute entered in config file for %v", rCfg.Name())
+	}
+	return new(logger, rCfg, consumer), nil
+}
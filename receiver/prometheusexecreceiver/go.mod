@@ -4,5 +4,13 @@ go 1.14
 
 require (
 	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51
+	github.com/prometheus/common v0.10.0
+	github.com/prometheus/prometheus v1.8.2-0.20200626085723-c448ada63d83
+	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.4
+	go.opentelemetry.io/collector v0.7.0
 	go.uber.org/zap v1.15.0
 )
+
+// Yet another hack that we need until kubernetes client moves to the new github.com/googleapis/gnostic
+replace github.com/googleapis/gnostic => github.com/googleapis/gnostic v0.3.1
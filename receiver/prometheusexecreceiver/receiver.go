@@ -0,0 +1,267 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	sdconfig "github.com/prometheus/prometheus/discovery/config"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
+)
+
+// portTemplate is replaced by the port of the exporter in its command and env.
+const portTemplate = "{{port}}"
+
+type prometheusExecReceiver struct {
+	sync.Mutex
+	logger   *zap.Logger
+	config   *Config
+	consumer consumer.MetricsConsumerOld
+
+	// prometheusReceiver scrapes the currently running exporter.
+	prometheusReceiver component.MetricsReceiver
+	// ctx is cancelled on Shutdown, which stops the exporter.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is closed once the exporter exited and is not restarted anymore.
+	done chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.MetricsReceiver = (*prometheusExecReceiver)(nil)
+
+// new returns a prometheusExecReceiver
+func new(logger *zap.Logger, cfg *Config, consumer consumer.MetricsConsumerOld) *prometheusExecReceiver {
+	ctx, cancel := context.WithCancel(obsreport.ReceiverContext(context.Background(), cfg.Name(), "", ""))
+	return &prometheusExecReceiver{
+		logger:   logger,
+		config:   cfg,
+		consumer: consumer,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start launches the exporter and scrapes it until Shutdown is called,
+// restarting it with a backoff whenever it exits.
+func (per *prometheusExecReceiver) Start(_ context.Context, host component.Host) error {
+	per.Lock()
+	defer per.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	per.startOnce.Do(func() {
+		err = nil
+		per.done = make(chan struct{})
+		go per.manageProcess(host)
+	})
+	return err
+}
+
+// manageProcess runs the exporter and the underlying Prometheus receiver
+// scraping it, and restarts both whenever the exporter exits.
+func (per *prometheusExecReceiver) manageProcess(host component.Host) {
+	defer close(per.done)
+
+	// the same process is reused across restarts so that they are counted
+	process := &subprocessmanager.Process{}
+	crashCount := 0
+	for {
+		port := per.config.SubprocessConfig.Port
+		if port == 0 {
+			var err error
+			// a new port is picked at each restart in case the previous one was taken
+			if port, err = getFreePort(); err != nil {
+				host.ReportFatalError(fmt.Errorf("unable to find a free port for %v: %w", per.config.Name(), err))
+				return
+			}
+		}
+
+		started, err := per.startPrometheusReceiver(host, port)
+		if err != nil {
+			host.ReportFatalError(err)
+			return
+		}
+		if !started {
+			// the receiver was shut down
+			return
+		}
+
+		applyPort(process, &per.config.SubprocessConfig, port)
+		per.logger.Info("Starting the exporter",
+			zap.String("command", process.Command),
+			zap.Int("port", port))
+		elapsed, err := process.Run(per.ctx, per.logger)
+		if err == context.Canceled {
+			per.logger.Info("Exporter stopped", zap.Duration("elapsed", elapsed))
+		} else if err != nil {
+			per.logger.Error("Exporter exited with an error", zap.Error(err), zap.Duration("elapsed", elapsed))
+		} else {
+			per.logger.Info("Exporter exited", zap.Duration("elapsed", elapsed))
+		}
+
+		if err = per.stopPrometheusReceiver(); err != nil {
+			per.logger.Warn("Failed to stop the prometheus receiver", zap.Error(err))
+		}
+
+		if elapsed > subprocessmanager.HealthyProcessTime {
+			crashCount = 0
+		}
+		crashCount++
+		delay := subprocessmanager.GetDelay(elapsed, crashCount)
+
+		select {
+		case <-per.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// startPrometheusReceiver starts scraping the exporter listening on the given
+// port, unless the receiver was shut down.
+func (per *prometheusExecReceiver) startPrometheusReceiver(host component.Host, port int) (bool, error) {
+	per.Lock()
+	defer per.Unlock()
+
+	if per.ctx.Err() != nil {
+		return false, nil
+	}
+
+	pFactory := &prometheusreceiver.Factory{}
+	pr, err := pFactory.CreateMetricsReceiver(context.Background(), per.logger, getPrometheusConfig(per.config, port), per.consumer)
+	if err != nil {
+		return false, fmt.Errorf("failed to create prometheus receiver: %w", err)
+	}
+	if err = pr.Start(context.Background(), host); err != nil {
+		return false, fmt.Errorf("failed to start prometheus receiver: %w", err)
+	}
+	per.prometheusReceiver = pr
+	return true, nil
+}
+
+func (per *prometheusExecReceiver) stopPrometheusReceiver() error {
+	per.Lock()
+	defer per.Unlock()
+
+	if per.prometheusReceiver == nil {
+		return nil
+	}
+	err := per.prometheusReceiver.Shutdown(context.Background())
+	per.prometheusReceiver = nil
+	return err
+}
+
+// applyPort sets the command and env of the exporter process from its config,
+// with the port template replaced.
+func applyPort(process *subprocessmanager.Process, cfg *subprocessmanager.SubprocessConfig, port int) {
+	portStr := strconv.Itoa(port)
+	env := make([]subprocessmanager.EnvConfig, len(cfg.Env))
+	for i, e := range cfg.Env {
+		env[i] = subprocessmanager.EnvConfig{
+			Name:  e.Name,
+			Value: strings.ReplaceAll(e.Value, portTemplate, portStr),
+		}
+	}
+	process.Command = strings.ReplaceAll(cfg.Command, portTemplate, portStr)
+	process.Port = port
+	process.Env = env
+}
+
+// getFreePort asks the kernel for a free TCP port on localhost.
+func getFreePort() (int, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func getPrometheusConfig(cfg *Config, port int) *prometheusreceiver.Config {
+	scrapeTimeout := defaultScrapeTimeout
+	if cfg.ScrapeInterval < scrapeTimeout {
+		scrapeTimeout = cfg.ScrapeInterval
+	}
+
+	scrapeConfig := &config.ScrapeConfig{
+		ScrapeInterval:  model.Duration(cfg.ScrapeInterval),
+		ScrapeTimeout:   model.Duration(scrapeTimeout),
+		JobName:         cfg.Name(),
+		HonorTimestamps: true,
+		Scheme:          "http",
+		MetricsPath:     defaultMetricsPath,
+		ServiceDiscoveryConfig: sdconfig.ServiceDiscoveryConfig{
+			StaticConfigs: []*targetgroup.Group{
+				{
+					Targets: []model.LabelSet{
+						{model.AddressLabel: model.LabelValue(net.JoinHostPort("localhost", strconv.Itoa(port)))},
+					},
+				},
+			},
+		},
+	}
+
+	return &prometheusreceiver.Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: cfg.Name(),
+		},
+		PrometheusConfig: &config.Config{ScrapeConfigs: []*config.ScrapeConfig{
+			scrapeConfig,
+		}},
+	}
+}
+
+// Shutdown stops scraping the exporter and waits for its process group to
+// exit, after being sent SIGTERM then SIGKILL once the grace period elapsed.
+func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	per.stopOnce.Do(func() {
+		per.Lock()
+		per.cancel()
+		done := per.done
+		per.Unlock()
+		err = per.stopPrometheusReceiver()
+
+		if done == nil {
+			return
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	})
+	return err
+}
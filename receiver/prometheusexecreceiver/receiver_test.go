@@ -0,0 +1,174 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	sdconfig "github.com/prometheus/prometheus/discovery/config"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
+)
+
+func TestApplyPort(t *testing.T) {
+	cfg := &subprocessmanager.SubprocessConfig{
+		Command: "mysqld_exporter --web.listen-address=:{{port}}",
+		Env: []subprocessmanager.EnvConfig{
+			{Name: "DATA_SOURCE_NAME", Value: "user:password@(hostname:port)/dbname"},
+			{Name: "PORT", Value: "{{port}}"},
+		},
+	}
+
+	got := &subprocessmanager.Process{}
+	applyPort(got, cfg, 9104)
+	assert.Equal(t, &subprocessmanager.Process{
+		Command: "mysqld_exporter --web.listen-address=:9104",
+		Port:    9104,
+		Env: []subprocessmanager.EnvConfig{
+			{Name: "DATA_SOURCE_NAME", Value: "user:password@(hostname:port)/dbname"},
+			{Name: "PORT", Value: "9104"},
+		},
+	}, got)
+	// the config itself is left untouched so that a new port can be used on restart
+	assert.Equal(t, "{{port}}", cfg.Env[1].Value)
+}
+
+func TestGetFreePort(t *testing.T) {
+	port, err := getFreePort()
+	require.NoError(t, err)
+	assert.Greater(t, port, 0)
+}
+
+func TestGetPrometheusConfig(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{NameVal: "prometheus_exec/mysql"},
+		ScrapeInterval:   5 * time.Second,
+	}
+	assert.Equal(t, &prometheusreceiver.Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: "prometheus_exec/mysql",
+		},
+		PrometheusConfig: &config.Config{
+			ScrapeConfigs: []*config.ScrapeConfig{
+				{
+					ScrapeInterval:  model.Duration(5 * time.Second),
+					ScrapeTimeout:   model.Duration(5 * time.Second),
+					JobName:         "prometheus_exec/mysql",
+					HonorTimestamps: true,
+					Scheme:          "http",
+					MetricsPath:     "/metrics",
+					ServiceDiscoveryConfig: sdconfig.ServiceDiscoveryConfig{
+						StaticConfigs: []*targetgroup.Group{
+							{
+								Targets: []model.LabelSet{
+									{model.AddressLabel: model.LabelValue("localhost:9104")},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, getPrometheusConfig(cfg, 9104))
+
+	// the scrape timeout is capped by the default timeout
+	cfg.ScrapeInterval = time.Minute
+	scrapeConfig := getPrometheusConfig(cfg, 9104).PrometheusConfig.ScrapeConfigs[0]
+	assert.Equal(t, model.Duration(defaultScrapeTimeout), scrapeConfig.ScrapeTimeout)
+}
+
+func TestShutdownStopsExporter(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: "prometheus_exec/shutdown_test",
+		},
+		ScrapeInterval: time.Second,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			// the exporter never exits by itself
+			Command: "sleep 60",
+		},
+	}
+	r := new(zap.NewNop(), cfg, &exportertest.SinkMetricsExporterOld{})
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// let the exporter start
+	time.Sleep(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, r.Shutdown(ctx))
+	select {
+	case <-r.done:
+	default:
+		t.Fatal("the exporter must have exited once Shutdown returns")
+	}
+}
+
+// TestEndToEnd runs an exporter which exits after a few seconds, and checks
+// that it is scraped, then restarted on a new port and scraped again.
+func TestEndToEnd(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the end to end test in short mode")
+	}
+
+	sink := &exportertest.SinkMetricsExporterOld{}
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: "prometheus_exec/end_to_end_test",
+		},
+		ScrapeInterval: 100 * time.Millisecond,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
+		},
+	}
+	r := new(zap.NewNop(), cfg, sink)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, componenterror.ErrAlreadyStarted, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	ports := func() map[string]bool {
+		seen := map[string]bool{}
+		for _, md := range sink.AllMetrics() {
+			if md.Node != nil {
+				seen[md.Node.Attributes["port"]] = true
+			}
+		}
+		return seen
+	}
+
+	// the exporter is scraped, then restarted on another port once it exits
+	assert.Eventually(t, func() bool {
+		return len(ports()) >= 2
+	}, 60*time.Second, 100*time.Millisecond, "the exporter must be scraped before and after a restart")
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
+}
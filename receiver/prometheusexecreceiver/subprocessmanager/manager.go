@@ -16,6 +16,8 @@ package subprocessmanager
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -34,6 +36,9 @@ type Process struct {
 	Command string
 	Port    int
 	Env     []EnvConfig
+
+	// runCount is the number of times Run started the process
+	runCount int
 }
 
 const (
@@ -45,10 +50,18 @@ const (
 	delayMultiplier float64 = 2.0
 	// initialDelay is the initial delay before a process is restarted
 	initialDelay time.Duration = 1 * time.Second
+	// uptimeReportInterval is the interval at which the uptime of the running process is recorded
+	uptimeReportInterval = 10 * time.Second
 )
 
-// Run will start the process and keep track of running time
-func (proc *Process) Run(logger *zap.Logger) (time.Duration, error) {
+// shutdownGracePeriod is the time given to the process group to exit after
+// SIGTERM before being sent SIGKILL, a variable so that it can be changed in tests
+var shutdownGracePeriod = 5 * time.Second
+
+// Run will start the process and keep track of running time. When ctx is cancelled, the process group is sent
+// SIGTERM then SIGKILL after a grace period, and ctx.Err() is returned once the process exited. The receiver
+// tag of ctx (see obsreport.ReceiverContext) is used to record the restart count, exit code and uptime metrics.
+func (proc *Process) Run(ctx context.Context, logger *zap.Logger) (time.Duration, error) {
 
 	var argsSlice []string
 
@@ -57,6 +70,9 @@ func (proc *Process) Run(logger *zap.Logger) (time.Duration, error) {
 	if err != nil {
 		return 0, fmt.Errorf("could not parse command error: %w", err)
 	}
+	if len(args) == 0 {
+		return 0, errors.New("empty command")
+	}
 	// Separate the executable from the flags for the Command object
 	if len(args) > 1 {
 		argsSlice = args[1:]
@@ -65,6 +81,7 @@ func (proc *Process) Run(logger *zap.Logger) (time.Duration, error) {
 	// Create the command object and attach current os environment + environment variables defined by user
 	childProcess := exec.Command(args[0], argsSlice...)
 	childProcess.Env = append(os.Environ(), formatEnvSlice(&proc.Env)...)
+	setProcessGroup(childProcess)
 
 	// Handle the subprocess standard and error outputs in goroutines
 	stdoutReader, stdoutErr := childProcess.StdoutPipe()
@@ -86,13 +103,69 @@ func (proc *Process) Run(logger *zap.Logger) (time.Duration, error) {
 		return 0, fmt.Errorf("process could not start: %w", errProcess)
 	}
 
-	errProcess = childProcess.Wait()
-	elapsed := time.Since(start)
-	if errProcess != nil {
-		return elapsed, fmt.Errorf("process error: %w", errProcess)
+	proc.runCount++
+	if proc.runCount > 1 {
+		recordRestart(ctx)
+	}
+	recordUptime(ctx, 0)
+
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- childProcess.Wait()
+	}()
+
+	ticker := time.NewTicker(uptimeReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case errProcess = <-waitCh:
+			elapsed := recordExit(ctx, childProcess, start)
+			if errProcess != nil {
+				return elapsed, fmt.Errorf("process error: %w", errProcess)
+			}
+			return elapsed, nil
+
+		case <-ticker.C:
+			recordUptime(ctx, time.Since(start))
+
+		case <-ctx.Done():
+			stopProcessGroup(childProcess, waitCh, logger)
+			elapsed := recordExit(ctx, childProcess, start)
+			return elapsed, ctx.Err()
+		}
+	}
+}
+
+// stopProcessGroup sends SIGTERM to the process group and escalates to SIGKILL if the process did not exit within
+// the grace period. It returns once the process exited.
+func stopProcessGroup(childProcess *exec.Cmd, waitCh <-chan error, logger *zap.Logger) {
+	if err := terminateProcessGroup(childProcess); err != nil {
+		logger.Debug("could not send SIGTERM to the subprocess group", zap.Error(err))
+	}
+
+	timer := time.NewTimer(shutdownGracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-waitCh:
+		return
+	case <-timer.C:
+	}
+
+	logger.Warn("subprocess did not exit within the grace period, killing it", zap.Duration("grace_period", shutdownGracePeriod))
+	if err := killProcessGroup(childProcess); err != nil {
+		logger.Debug("could not send SIGKILL to the subprocess group", zap.Error(err))
 	}
+	<-waitCh
+}
 
-	return elapsed, nil
+// recordExit records the exit code of the process, resets its uptime and returns the time it ran for
+func recordExit(ctx context.Context, childProcess *exec.Cmd, start time.Time) time.Duration {
+	elapsed := time.Since(start)
+	recordExitCode(ctx, childProcess.ProcessState.ExitCode())
+	recordUptime(ctx, 0)
+	return elapsed
 }
 
 // Log every line of the subprocesse's output using zap, until pipe is closed (EOF)
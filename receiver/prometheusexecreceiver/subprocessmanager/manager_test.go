@@ -15,10 +15,15 @@
 package subprocessmanager
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 )
 
@@ -200,7 +205,7 @@ func TestRun(t *testing.T) {
 	for _, test := range runTests {
 		t.Run(test.name, func(t *testing.T) {
 			logger, _ := zap.NewProduction()
-			got, err := test.process.Run(logger)
+			got, err := test.process.Run(context.Background(), logger)
 			if test.wantErr && err == nil {
 				t.Errorf("Run() got = %v, wantErr %v", got, test.wantErr)
 				return
@@ -211,3 +216,62 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRunCancel(t *testing.T) {
+	process := &Process{Command: "sleep 30"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	elapsed, err := process.Run(ctx, zap.NewNop())
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, elapsed < shutdownGracePeriod, "the process must exit on SIGTERM")
+}
+
+func TestRunCancelKillsAfterGracePeriod(t *testing.T) {
+	defer func(gracePeriod time.Duration) { shutdownGracePeriod = gracePeriod }(shutdownGracePeriod)
+	shutdownGracePeriod = 500 * time.Millisecond
+
+	// SIGTERM is ignored by the shell and by the sleep process it spawns
+	process := &Process{Command: `sh -c 'trap "" TERM; sleep 30; echo done'`}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	elapsed, err := process.Run(ctx, zap.NewNop())
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, elapsed >= shutdownGracePeriod, "the process must be killed after the grace period")
+	assert.True(t, elapsed < 10*time.Second)
+}
+
+func TestRunMetrics(t *testing.T) {
+	const receiverName = "prometheus_exec/metrics_test"
+	viewValue := func(v *view.View) float64 {
+		rows, err := view.RetrieveData(v.Name)
+		require.NoError(t, err)
+		for _, row := range rows {
+			if len(row.Tags) != 1 || row.Tags[0].Value != receiverName {
+				continue
+			}
+			switch data := row.Data.(type) {
+			case *view.SumData:
+				return data.Value
+			case *view.LastValueData:
+				return data.Value
+			}
+		}
+		return 0
+	}
+	restarts := viewValue(viewRestarts)
+
+	ctx := obsreport.ReceiverContext(context.Background(), receiverName, "", "")
+	process := &Process{Command: `sh -c 'exit 3'`}
+	_, err := process.Run(ctx, zap.NewNop())
+	require.Error(t, err)
+	_, err = process.Run(ctx, zap.NewNop())
+	require.Error(t, err)
+
+	assert.Equal(t, restarts+1, viewValue(viewRestarts))
+	assert.Equal(t, float64(3), viewValue(viewExitCode))
+	assert.Equal(t, float64(0), viewValue(viewUptime))
+}
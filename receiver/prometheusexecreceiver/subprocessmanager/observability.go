@@ -0,0 +1,79 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocessmanager
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+func init() {
+	view.Register(
+		viewRestarts,
+		viewExitCode,
+		viewUptime,
+	)
+}
+
+// tagKeyReceiver is the key of the receiver tag that obsreport.ReceiverContext
+// adds to the context passed to Run.
+var tagKeyReceiver, _ = tag.NewKey(obsreport.ReceiverKey)
+
+var (
+	mRestarts = stats.Int64("otelcol/subprocess/restarts", "Number of times the subprocess was restarted", "1")
+	mExitCode = stats.Int64("otelcol/subprocess/exit_code", "Exit code of the last run of the subprocess, -1 if it was killed by a signal", "1")
+	mUptime   = stats.Float64("otelcol/subprocess/uptime", "Time the running subprocess has been up for, 0 if it is not running", "s")
+)
+
+var viewRestarts = &view.View{
+	Name:        mRestarts.Name(),
+	Description: mRestarts.Description(),
+	Measure:     mRestarts,
+	TagKeys:     []tag.Key{tagKeyReceiver},
+	Aggregation: view.Sum(),
+}
+
+var viewExitCode = &view.View{
+	Name:        mExitCode.Name(),
+	Description: mExitCode.Description(),
+	Measure:     mExitCode,
+	TagKeys:     []tag.Key{tagKeyReceiver},
+	Aggregation: view.LastValue(),
+}
+
+var viewUptime = &view.View{
+	Name:        mUptime.Name(),
+	Description: mUptime.Description(),
+	Measure:     mUptime,
+	TagKeys:     []tag.Key{tagKeyReceiver},
+	Aggregation: view.LastValue(),
+}
+
+func recordRestart(ctx context.Context) {
+	stats.Record(ctx, mRestarts.M(1))
+}
+
+func recordExitCode(ctx context.Context, exitCode int) {
+	stats.Record(ctx, mExitCode.M(int64(exitCode)))
+}
+
+func recordUptime(ctx context.Context, uptime time.Duration) {
+	stats.Record(ctx, mUptime.M(uptime.Seconds()))
+}
@@ -12,30 +12,23 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package interval
+// +build windows
+
+package subprocessmanager
 
 import (
-	"testing"
-	"time"
+	"os/exec"
 )
 
-func TestScheduler(t *testing.T) {
-	f := &fakeRunnable{}
-	s := NewRunner(time.Second, f)
-	go func() {
-		_ = s.Start()
-	}()
-	s.Stop()
-	// getting here is success
-}
-
-type fakeRunnable struct {
-}
+// setProcessGroup is a no-op, process groups can't be signaled on Windows.
+func setProcessGroup(*exec.Cmd) {}
 
-func (t *fakeRunnable) Setup() error {
-	return nil
+// terminateProcessGroup kills the process since Windows has no SIGTERM.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
 }
 
-func (fakeRunnable) Run() error {
-	return nil
+// killProcessGroup kills the process.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
 }
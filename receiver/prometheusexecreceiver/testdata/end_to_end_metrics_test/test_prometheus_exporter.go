@@ -0,0 +1,48 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// lifetime bounds how long the exporter runs, so that it never outlives the tests
+// and so that the receiver has to restart it.
+const lifetime = 5 * time.Second
+
+// This program is a minimal Prometheus exporter listening on the port passed as
+// its first argument, used in the prometheus_exec receiver end to end tests
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: test_prometheus_exporter <port>")
+	}
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# HELP test_gauge0 This is my gauge\n")
+		fmt.Fprint(w, "# TYPE test_gauge0 gauge\n")
+		fmt.Fprint(w, "test_gauge0{label1=\"value1\",label2=\"value2\"} 10\n")
+	})
+
+	go func() {
+		time.Sleep(lifetime)
+		os.Exit(0)
+	}()
+
+	log.Fatal(http.ListenAndServe("localhost:"+os.Args[1], nil))
+}
@@ -15,13 +15,30 @@
 package redisreceiver
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/go-redis/redis/v7"
 )
 
-// Interface for a Redis client. Implementation can be faked for testing.
+// Interface for a Redis client. Implementation can be faked for testing. The
+// commands are cancelled when their context is done.
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
-	retrieveInfo() (string, error)
+	retrieveInfo(ctx context.Context) (string, error)
+	// retrieves the entries of the slow log, most recent first
+	retrieveSlowLog(ctx context.Context) ([]slowLogEntry, error)
+	// retrieves the latest latency events of the latency monitor
+	retrieveLatencyLatest(ctx context.Context) ([]latencyEvent, error)
+	// retrieves the nodes of the cluster, one line per node
+	retrieveClusterNodes(ctx context.Context) (string, error)
+	// retrieves the fields of the masters monitored by a Sentinel
+	retrieveSentinelMasters(ctx context.Context) ([]map[string]string, error)
+	// retrieves the fields of the replicas of a master monitored by a Sentinel
+	retrieveSentinelReplicas(ctx context.Context, master string) ([]map[string]string, error)
+	// closes the connections to the server
+	close() error
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
@@ -47,6 +64,98 @@ func (c *redisClient) delimiter() string {
 }
 
 // Retrieve Redis INFO. We retrieve all of the 'sections'.
-func (c *redisClient) retrieveInfo() (string, error) {
-	return c.client.Info().Result()
+func (c *redisClient) retrieveInfo(ctx context.Context) (string, error) {
+	// The default sections of INFO do not include commandstats.
+	return c.client.WithContext(ctx).Info("all").Result()
+}
+
+// Retrieve all of the entries of the slow log. SLOWLOG GET is not implemented
+// by the client so the reply is parsed here.
+func (c *redisClient) retrieveSlowLog(ctx context.Context) ([]slowLogEntry, error) {
+	// A negative count returns the whole slow log.
+	reply, err := c.client.WithContext(ctx).Do("slowlog", "get", -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseSlowLog(reply)
+}
+
+// Retrieve the latest latency events, only reported once the latency monitor
+// is enabled with the latency-monitor-threshold server configuration option.
+func (c *redisClient) retrieveLatencyLatest(ctx context.Context) ([]latencyEvent, error) {
+	reply, err := c.client.WithContext(ctx).Do("latency", "latest").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseLatencyLatest(reply)
+}
+
+// Retrieve the nodes of the cluster the server is part of.
+func (c *redisClient) retrieveClusterNodes(ctx context.Context) (string, error) {
+	return c.client.WithContext(ctx).ClusterNodes().Result()
+}
+
+// Retrieve the masters monitored by the server, a Sentinel.
+func (c *redisClient) retrieveSentinelMasters(ctx context.Context) ([]map[string]string, error) {
+	reply, err := c.client.WithContext(ctx).Do("sentinel", "masters").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseSentinelReply(reply)
+}
+
+// Retrieve the replicas of a master monitored by the server, a Sentinel.
+// SENTINEL replicas was added in Redis 5, older Sentinels only know its
+// SENTINEL slaves alias.
+func (c *redisClient) retrieveSentinelReplicas(ctx context.Context, master string) ([]map[string]string, error) {
+	client := c.client.WithContext(ctx)
+	reply, err := client.Do("sentinel", "replicas", master).Result()
+	if isUnknownSubcommand(err) {
+		reply, err = client.Do("sentinel", "slaves", master).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseSentinelReply(reply)
+}
+
+// Whether the error is the reply of the server to a subcommand it does not
+// implement, e.g. "ERR Unknown sentinel subcommand 'replicas'".
+func isUnknownSubcommand(err error) bool {
+	if _, ok := err.(redis.Error); !ok {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown") && strings.Contains(msg, "subcommand")
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
+
+// Turns the reply of SENTINEL masters or SENTINEL replicas, an array of flat
+// arrays of field names and values, into maps of fields.
+func parseSentinelReply(reply interface{}) ([]map[string]string, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected sentinel reply %v", reply)
+	}
+	nodes := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields)%2 != 0 {
+			return nil, fmt.Errorf("unexpected sentinel node %v", item)
+		}
+		node := make(map[string]string, len(fields)/2)
+		for i := 0; i < len(fields); i += 2 {
+			key, keyOk := fields[i].(string)
+			value, valueOk := fields[i+1].(string)
+			if !keyOk || !valueOk {
+				return nil, fmt.Errorf("unexpected sentinel node %v", item)
+			}
+			node[key] = value
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
 }
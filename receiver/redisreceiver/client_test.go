@@ -15,17 +15,34 @@
 package redisreceiver
 
 import (
+	"bufio"
+	"context"
 	"io/ioutil"
+	"net"
 	"path"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-redis/redis/v7"
 	"github.com/stretchr/testify/require"
 )
 
 var _ client = (*fakeClient)(nil)
 
-type fakeClient struct{}
+type fakeClient struct {
+	slowLog          []slowLogEntry
+	latencyEvents    []latencyEvent
+	clusterNodes     string
+	sentinelMasters  []map[string]string
+	sentinelReplicas map[string][]map[string]string
+	// err is returned by INFO and the discovery commands when set
+	err error
+	// block makes INFO wait until its context is done, as for a node that
+	// does not reply
+	block  bool
+	closed bool
+}
 
 func newFakeClient() *fakeClient {
 	return &fakeClient{}
@@ -35,10 +52,42 @@ func (c fakeClient) delimiter() string {
 	return "\n"
 }
 
-func (fakeClient) retrieveInfo() (string, error) {
+func (c fakeClient) retrieveInfo(ctx context.Context) (string, error) {
+	if c.block {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if c.err != nil {
+		return "", c.err
+	}
 	return readFile("info")
 }
 
+func (c fakeClient) retrieveSlowLog(context.Context) ([]slowLogEntry, error) {
+	return c.slowLog, nil
+}
+
+func (c fakeClient) retrieveLatencyLatest(context.Context) ([]latencyEvent, error) {
+	return c.latencyEvents, nil
+}
+
+func (c *fakeClient) retrieveClusterNodes(context.Context) (string, error) {
+	return c.clusterNodes, c.err
+}
+
+func (c *fakeClient) retrieveSentinelMasters(context.Context) ([]map[string]string, error) {
+	return c.sentinelMasters, c.err
+}
+
+func (c *fakeClient) retrieveSentinelReplicas(_ context.Context, master string) ([]map[string]string, error) {
+	return c.sentinelReplicas[master], c.err
+}
+
+func (c *fakeClient) close() error {
+	c.closed = true
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
@@ -49,7 +98,74 @@ func readFile(fname string) (string, error) {
 
 func TestRetrieveInfo(t *testing.T) {
 	g := fakeClient{}
-	res, err := g.retrieveInfo()
+	res, err := g.retrieveInfo(context.Background())
 	require.Nil(t, err)
 	require.True(t, strings.HasPrefix(res, "# Server"))
 }
+
+func TestRedisClientContext(t *testing.T) {
+	// The server accepts the connections but never replies.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	c := newRedisClient(&redis.Options{Addr: ln.Addr().String(), MaxRetries: -1})
+	defer c.close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.retrieveInfo(ctx)
+	require.Error(t, err)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestRetrieveSentinelReplicasFallback(t *testing.T) {
+	// The server is a Sentinel older than Redis 5, which does not implement
+	// SENTINEL replicas.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	commands := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			args, err := readCommand(r)
+			if err != nil {
+				return
+			}
+			cmd := strings.ToLower(strings.Join(args, " "))
+			commands <- cmd
+			switch cmd {
+			case "sentinel replicas mymaster":
+				conn.Write([]byte("-ERR Unknown sentinel subcommand 'replicas'\r\n"))
+			case "sentinel slaves mymaster":
+				conn.Write([]byte("*1\r\n*4\r\n$2\r\nip\r\n$8\r\n10.0.0.2\r\n$4\r\nport\r\n$4\r\n6379\r\n"))
+			default:
+				conn.Write([]byte("-ERR unexpected command\r\n"))
+			}
+		}
+	}()
+
+	c := newRedisClient(&redis.Options{Addr: ln.Addr().String(), MaxRetries: -1})
+	defer c.close()
+	replicas, err := c.retrieveSentinelReplicas(context.Background(), "mymaster")
+	require.NoError(t, err)
+	require.Equal(t, []map[string]string{{"ip": "10.0.0.2", "port": "6379"}}, replicas)
+	require.Equal(t, "sentinel replicas mymaster", <-commands)
+	require.Equal(t, "sentinel slaves mymaster", <-commands)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The prefix of the keys of the Commandstats section of the INFO command.
+const commandStatsPrefix = "cmdstat_"
+
+// Holds fields returned by the Commandstats section of the INFO command: e.g.
+// "cmdstat_get:calls=21,usec=175,usec_per_call=8.33"
+type commandStats struct {
+	command string
+	calls   int64
+	usec    int64
+}
+
+// Turns a commandstats value (the part after the colon
+// e.g. "calls=21,usec=175,usec_per_call=8.33") into a commandStats struct
+func parseCommandStatsString(command string, str string) (*commandStats, error) {
+	pairs := strings.Split(str, ",")
+	cs := commandStats{command: command}
+	var found bool
+	for _, pairStr := range pairs {
+		var field *int64
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(
+				"unexpected commandstats pair '%s'",
+				pairStr,
+			)
+		}
+		key := pair[0]
+		switch key {
+		case "calls":
+			field = &cs.calls
+		case "usec":
+			field = &cs.usec
+		}
+		if field != nil {
+			val, err := strconv.ParseInt(pair[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			*field = val
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no calls nor usec in commandstats '%s'", str)
+	}
+	return &cs, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	cs, err := parseCommandStatsString("get", "calls=21,usec=175,usec_per_call=8.33")
+	require.Nil(t, err)
+	require.Equal(t, "get", cs.command)
+	require.Equal(t, int64(21), cs.calls)
+	require.Equal(t, int64(175), cs.usec)
+
+	// Redis 6.2 adds rejected_calls and failed_calls.
+	cs, err = parseCommandStatsString("set", "calls=7,usec=70,usec_per_call=10.00,rejected_calls=1,failed_calls=0")
+	require.Nil(t, err)
+	require.Equal(t, int64(7), cs.calls)
+}
+
+func TestParseMalformedCommandStats(t *testing.T) {
+	tests := []struct{ name, commandStats string }{
+		{"missing value", "calls=1,usec="},
+		{"missing equals", "calls=1,usec"},
+		{"invalid value", "calls=x,usec=2"},
+		{"no usable data", "usec_per_call=1.00"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseCommandStatsString("get", test.commandStats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestBuildCommandStatsProtoMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, err := svc.info(context.Background())
+	require.Nil(t, err)
+
+	metrics, warnings := info.buildCommandStatsProtoMetrics(nil, getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 6, len(metrics))
+	// The metrics are sorted by command.
+	require.Equal(t, "redis/commands/calls", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[0].MetricDescriptor.Type)
+	require.Equal(t, "command", metrics[0].MetricDescriptor.LabelKeys[0].Key)
+	require.Equal(t, "get", metrics[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, int64(21), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/commands/cpu_time", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, "us", metrics[1].MetricDescriptor.Unit)
+	require.Equal(t, int64(175), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "info", metrics[2].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, "set", metrics[4].Timeseries[0].LabelValues[0].Value)
+
+	metrics, warnings = info.buildCommandStatsProtoMetrics(map[string]bool{"set": true}, getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 2, len(metrics))
+	require.Equal(t, "set", metrics[0].Timeseries[0].LabelValues[0].Value)
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 type config struct {
@@ -33,7 +34,50 @@ type config struct {
 
 	// TODO allow users to add additional resource key value pairs?
 
+	// Commands restricts the per-command metrics, built from the commandstats
+	// section of INFO, to these commands. All commands are kept when empty.
+	Commands []string `mapstructure:"commands"`
+
+	// SlowLog enables the per-command metrics of the commands logged in the
+	// slow log, polled with SLOWLOG GET.
+	SlowLog bool `mapstructure:"slowlog"`
+
+	// LatencyMonitor enables the metrics of the latency events, polled with
+	// LATENCY LATEST.
+	LatencyMonitor bool `mapstructure:"latency_monitor"`
+
+	// Optional username, used along with the password to authenticate as a
+	// Redis 6 ACL user.
+	Username string `mapstructure:"username"`
+
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the ACL
+	// user if a username is set.
 	Password string `mapstructure:"password"`
+
+	// TLS enables TLS on the connections to Redis when insecure is false or a
+	// CA file is set.
+	TLS configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Discovery, when its mode is set, scrapes all of the nodes of a Redis
+	// Cluster, or all of the masters monitored by Redis Sentinels and their
+	// replicas, instead of the endpoint alone.
+	Discovery discoveryConfig `mapstructure:"discovery"`
+}
+
+type discoveryConfig struct {
+	// Mode is either "cluster" or "sentinel". Discovery is disabled when
+	// empty.
+	Mode string `mapstructure:"mode"`
+
+	// The addresses of the nodes, or of the Sentinels, the topology is
+	// discovered from, in order. The endpoint is used when empty.
+	Seeds []string `mapstructure:"seeds"`
+
+	// The duration between topology refreshes.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// Optional password of the Sentinels, the password is only used to
+	// authenticate to the discovered nodes in sentinel mode.
+	SentinelPassword string `mapstructure:"sentinel_password"`
 }
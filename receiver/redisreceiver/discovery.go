@@ -0,0 +1,380 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
+)
+
+const (
+	// Discovers the nodes of a Redis Cluster with CLUSTER NODES.
+	discoveryModeCluster = "cluster"
+	// Discovers the masters monitored by Redis Sentinels, and their replicas,
+	// with SENTINEL masters and SENTINEL replicas.
+	discoveryModeSentinel = "sentinel"
+
+	roleMaster  = "master"
+	roleReplica = "replica"
+
+	// The resource labels of the metrics of a discovered node.
+	addressLabel   = "redis.address"
+	roleLabel      = "redis.role"
+	slotRangeLabel = "redis.cluster.slot_range"
+	masterIDLabel  = "redis.master_id"
+)
+
+var errNoSeeds = errors.New("no seed to discover the topology from")
+
+// A node discovered in a Redis Cluster or through Redis Sentinels.
+type redisNode struct {
+	addr string
+	role string
+	// the cluster node ID, or Sentinel run ID, of the master of the node, or
+	// of the node itself if it is a master
+	masterID string
+	// the slots served by the master of the node, e.g. "0-5460,5462", empty
+	// unless in a Redis Cluster
+	slotRange string
+}
+
+// Returns the resource labels of the metrics of the node.
+func (n *redisNode) labels() map[string]string {
+	labels := map[string]string{
+		addressLabel:  n.addr,
+		roleLabel:     n.role,
+		masterIDLabel: n.masterID,
+	}
+	if n.slotRange != "" {
+		labels[slotRangeLabel] = n.slotRange
+	}
+	return labels
+}
+
+// Turns the reply of CLUSTER NODES, one line per node, into redisNode
+// structs, e.g.
+// "e7d1eec 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460"
+// The nodes that are failing, or whose address is not known yet, are skipped.
+// The node the command was sent to may not know its own IP, in which case the
+// host of seed is used.
+func parseClusterNodes(str string, seed string) ([]redisNode, error) {
+	type clusterNode struct {
+		redisNode
+		id    string
+		slots []string
+	}
+	var nodes []clusterNode
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			return nil, fmt.Errorf("unexpected cluster node '%s'", line)
+		}
+		flags := strings.Split(fields[2], ",")
+		if hasAnyFlag(flags, "fail", "fail?", "handshake", "noaddr") {
+			continue
+		}
+
+		// The address is followed by the cluster bus port, and by the
+		// hostname since Redis 7.0.
+		addr := strings.SplitN(strings.SplitN(fields[1], ",", 2)[0], "@", 2)[0]
+		if strings.HasPrefix(addr, ":") && hasAnyFlag(flags, "myself") {
+			host, _, err := net.SplitHostPort(seed)
+			if err != nil {
+				return nil, err
+			}
+			addr = net.JoinHostPort(host, addr[1:])
+		}
+
+		n := clusterNode{id: fields[0]}
+		n.addr = addr
+		switch {
+		case hasAnyFlag(flags, "master"):
+			n.role = roleMaster
+			n.masterID = n.id
+		case hasAnyFlag(flags, "slave"):
+			n.role = roleReplica
+			n.masterID = fields[3]
+		default:
+			continue
+		}
+		for _, slot := range fields[8:] {
+			// Slots being imported or migrated, e.g. "[93-<-292f8b3]", are
+			// still served by the node.
+			if !strings.HasPrefix(slot, "[") {
+				n.slots = append(n.slots, slot)
+			}
+		}
+		nodes = append(nodes, n)
+	}
+
+	// Only masters list their slots, replicas serve the slots of their master.
+	slots := map[string]string{}
+	for _, n := range nodes {
+		if n.role == roleMaster {
+			slots[n.id] = strings.Join(n.slots, ",")
+		}
+	}
+	redisNodes := make([]redisNode, 0, len(nodes))
+	for _, n := range nodes {
+		n.slotRange = slots[n.masterID]
+		redisNodes = append(redisNodes, n.redisNode)
+	}
+	return redisNodes, nil
+}
+
+// Turns the fields of the masters and replicas returned by SENTINEL masters
+// and SENTINEL replicas into redisNode structs. The nodes considered down by
+// the Sentinel are skipped.
+func parseSentinelNode(fields map[string]string, role string, masterID string) (redisNode, bool) {
+	flags := strings.Split(fields["flags"], ",")
+	if hasAnyFlag(flags, "s_down", "o_down", "disconnected") {
+		return redisNode{}, false
+	}
+	return redisNode{
+		addr:     net.JoinHostPort(fields["ip"], fields["port"]),
+		role:     role,
+		masterID: masterID,
+	}, true
+}
+
+func hasAnyFlag(flags []string, wanted ...string) bool {
+	for _, flag := range flags {
+		for _, w := range wanted {
+			if flag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Discovers the nodes of a Redis Cluster from the first seed that answers.
+func discoverClusterNodes(ctx context.Context, seeds []string, newClient func(addr string) client) ([]redisNode, error) {
+	err := errNoSeeds
+	for _, seed := range seeds {
+		var nodes []redisNode
+		if nodes, err = discoverFromSeed(seed, newClient, func(c client) ([]redisNode, error) {
+			str, err := c.retrieveClusterNodes(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return parseClusterNodes(str, seed)
+		}); err == nil {
+			return nodes, nil
+		}
+	}
+	return nil, err
+}
+
+// Discovers the masters monitored by the first Sentinel of seeds that
+// answers, and their replicas.
+func discoverSentinelNodes(ctx context.Context, seeds []string, newClient func(addr string) client) ([]redisNode, error) {
+	err := errNoSeeds
+	for _, seed := range seeds {
+		var nodes []redisNode
+		if nodes, err = discoverFromSeed(seed, newClient, func(c client) ([]redisNode, error) {
+			masters, err := c.retrieveSentinelMasters(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var nodes []redisNode
+			for _, master := range masters {
+				n, ok := parseSentinelNode(master, roleMaster, master["runid"])
+				if !ok {
+					continue
+				}
+				nodes = append(nodes, n)
+				replicas, err := c.retrieveSentinelReplicas(ctx, master["name"])
+				if err != nil {
+					return nil, err
+				}
+				for _, replica := range replicas {
+					if n, ok := parseSentinelNode(replica, roleReplica, master["runid"]); ok {
+						nodes = append(nodes, n)
+					}
+				}
+			}
+			return nodes, nil
+		}); err == nil {
+			return nodes, nil
+		}
+	}
+	return nil, err
+}
+
+func discoverFromSeed(
+	seed string,
+	newClient func(addr string) client,
+	discover func(c client) ([]redisNode, error),
+) ([]redisNode, error) {
+	c := newClient(seed)
+	defer c.close()
+	nodes, err := discover(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the topology from %s: %w", seed, err)
+	}
+	return nodes, nil
+}
+
+var _ interval.Runnable = (*discoveryRunnable)(nil)
+
+// Runs intermittently, refreshing the topology of a Redis Cluster or of the
+// masters monitored by Redis Sentinels when it is due, and running a
+// redisRunnable for each of the nodes.
+type discoveryRunnable struct {
+	cfg             *config
+	metricsConsumer consumer.MetricsConsumerOld
+	logger          *zap.Logger
+	// discovers the current nodes
+	discover func(ctx context.Context) ([]redisNode, error)
+	// creates the client of a discovered node
+	newClient   func(addr string) client
+	lastRefresh time.Time
+	// the runnables of the nodes, by address
+	nodes map[string]*nodeRunnable
+}
+
+type nodeRunnable struct {
+	client   client
+	runnable *redisRunnable
+}
+
+func newDiscoveryRunnable(
+	cfg *config,
+	discover func(ctx context.Context) ([]redisNode, error),
+	newClient func(addr string) client,
+	metricsConsumer consumer.MetricsConsumerOld,
+	logger *zap.Logger,
+) *discoveryRunnable {
+	return &discoveryRunnable{
+		cfg:             cfg,
+		discover:        discover,
+		newClient:       newClient,
+		metricsConsumer: metricsConsumer,
+		logger:          logger,
+		nodes:           map[string]*nodeRunnable{},
+	}
+}
+
+// The topology is discovered on the first run, so that the receiver starts
+// even though no seed is reachable yet.
+func (r *discoveryRunnable) Setup() error {
+	return nil
+}
+
+// Run refreshes the topology when it is due, then runs the redisRunnable of
+// each of the nodes. The known nodes keep being scraped when the topology
+// could not be refreshed. The nodes are scraped concurrently so that a slow or
+// unreachable node neither delays nor, once the run times out, cancels the
+// scrape of the others.
+func (r *discoveryRunnable) Run(ctx context.Context) error {
+	now := time.Now()
+	if r.lastRefresh.IsZero() || now.Sub(r.lastRefresh) >= r.cfg.Discovery.RefreshInterval {
+		if err := r.refresh(ctx); err != nil {
+			r.logger.Warn("failed to refresh the redis topology", zap.Error(err))
+		} else {
+			r.lastRefresh = now
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addrs := make([]string, 0, len(r.nodes))
+	for addr := range r.nodes {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	nodeErrs := make([]error, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string, node *nodeRunnable) {
+			defer wg.Done()
+			if err := node.runnable.Run(ctx); err != nil {
+				nodeErrs[i] = fmt.Errorf("failed to scrape %s: %w", addr, err)
+			}
+		}(i, addr, r.nodes[addr])
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range nodeErrs {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+// Creates the runnables of the new nodes, updates the labels of the known
+// ones since their role may have changed after a failover, and closes the
+// clients of the nodes that are gone.
+func (r *discoveryRunnable) refresh(ctx context.Context) error {
+	discovered, err := r.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool, len(discovered))
+	for i := range discovered {
+		n := &discovered[i]
+		current[n.addr] = true
+		node, ok := r.nodes[n.addr]
+		if !ok {
+			c := r.newClient(n.addr)
+			runnable := newRedisRunnable(c, r.cfg, r.metricsConsumer, r.logger)
+			if err := runnable.Setup(); err != nil {
+				c.close()
+				return err
+			}
+			node = &nodeRunnable{client: c, runnable: runnable}
+			r.nodes[n.addr] = node
+		}
+		node.runnable.resourceLabels = n.labels()
+	}
+
+	for addr, node := range r.nodes {
+		if !current[addr] {
+			node.client.close()
+			delete(r.nodes, addr)
+		}
+	}
+	return nil
+}
+
+// Closes the clients of all of the nodes, once the runner is stopped.
+func (r *discoveryRunnable) close() {
+	for addr, node := range r.nodes {
+		node.client.close()
+		delete(r.nodes, addr)
+	}
+}
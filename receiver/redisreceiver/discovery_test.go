@@ -0,0 +1,230 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+const clusterNodes = `07c37df 127.0.0.1:30004@31004 slave e7d1eec 0 1426238317239 4 connected
+67ed2db 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922
+292f8b3 127.0.0.1:30003@31003,redis-3 master - 0 1426238318243 3 connected 10923-16383 [93-<-67ed2db]
+6ec2392 127.0.0.1:30005@31005 slave 67ed2db 0 1426238316232 5 connected
+824fe11 127.0.0.1:30006@31006 master,fail - 1426238316232 1426238316232 6 disconnected
+e7d1eec :30001@31001 myself,master - 0 0 1 connected 0-5459 5461
+`
+
+func TestParseClusterNodes(t *testing.T) {
+	nodes, err := parseClusterNodes(clusterNodes, "10.0.0.1:30001")
+	require.NoError(t, err)
+	assert.Equal(t, []redisNode{
+		{addr: "127.0.0.1:30004", role: roleReplica, masterID: "e7d1eec", slotRange: "0-5459,5461"},
+		{addr: "127.0.0.1:30002", role: roleMaster, masterID: "67ed2db", slotRange: "5461-10922"},
+		{addr: "127.0.0.1:30003", role: roleMaster, masterID: "292f8b3", slotRange: "10923-16383"},
+		{addr: "127.0.0.1:30005", role: roleReplica, masterID: "67ed2db", slotRange: "5461-10922"},
+		{addr: "10.0.0.1:30001", role: roleMaster, masterID: "e7d1eec", slotRange: "0-5459,5461"},
+	}, nodes)
+
+	_, err = parseClusterNodes("07c37df 127.0.0.1:30004@31004 slave", "10.0.0.1:30001")
+	assert.Error(t, err)
+}
+
+func TestDiscoverClusterNodes(t *testing.T) {
+	clients := map[string]*fakeClient{
+		"10.0.0.1:30001": {err: errors.New("connection refused")},
+		"10.0.0.2:30001": {clusterNodes: "e7d1eec 10.0.0.2:30001@31001 myself,master - 0 0 1 connected 0-16383\n"},
+	}
+	nodes, err := discoverClusterNodes(context.Background(), []string{"10.0.0.1:30001", "10.0.0.2:30001"}, func(addr string) client {
+		return clients[addr]
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []redisNode{
+		{addr: "10.0.0.2:30001", role: roleMaster, masterID: "e7d1eec", slotRange: "0-16383"},
+	}, nodes)
+	for _, c := range clients {
+		assert.True(t, c.closed)
+	}
+
+	_, err = discoverClusterNodes(context.Background(), []string{"10.0.0.1:30001"}, func(addr string) client {
+		return clients[addr]
+	})
+	assert.EqualError(t, err, "failed to discover the topology from 10.0.0.1:30001: connection refused")
+
+	_, err = discoverClusterNodes(context.Background(), nil, nil)
+	assert.Equal(t, errNoSeeds, err)
+}
+
+func TestDiscoverSentinelNodes(t *testing.T) {
+	c := &fakeClient{
+		sentinelMasters: []map[string]string{
+			{"name": "mymaster", "ip": "10.0.0.1", "port": "6379", "runid": "953ae6a", "flags": "master"},
+			{"name": "down", "ip": "10.0.0.9", "port": "6379", "runid": "ee4a6b8", "flags": "master,s_down,o_down"},
+		},
+		sentinelReplicas: map[string][]map[string]string{
+			"mymaster": {
+				{"name": "10.0.0.2:6379", "ip": "10.0.0.2", "port": "6379", "runid": "0b3e4ac", "flags": "slave"},
+				{"name": "10.0.0.3:6379", "ip": "10.0.0.3", "port": "6379", "runid": "", "flags": "slave,s_down,disconnected"},
+			},
+		},
+	}
+	nodes, err := discoverSentinelNodes(context.Background(), []string{"10.0.0.1:26379"}, func(string) client { return c })
+	require.NoError(t, err)
+	assert.Equal(t, []redisNode{
+		{addr: "10.0.0.1:6379", role: roleMaster, masterID: "953ae6a"},
+		{addr: "10.0.0.2:6379", role: roleReplica, masterID: "953ae6a"},
+	}, nodes)
+}
+
+func TestParseSentinelReply(t *testing.T) {
+	nodes, err := parseSentinelReply([]interface{}{
+		[]interface{}{"name", "mymaster", "ip", "10.0.0.1", "port", "6379"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"name": "mymaster", "ip": "10.0.0.1", "port": "6379"}}, nodes)
+
+	for _, reply := range []interface{}{
+		"OK",
+		[]interface{}{"name"},
+		[]interface{}{[]interface{}{"name", "mymaster", "ip"}},
+		[]interface{}{[]interface{}{"port", int64(6379)}},
+	} {
+		_, err := parseSentinelReply(reply)
+		assert.Error(t, err, "%v", reply)
+	}
+}
+
+func TestDiscoveryRunnable(t *testing.T) {
+	topology := []redisNode{
+		{addr: "10.0.0.1:6379", role: roleMaster, masterID: "953ae6a"},
+		{addr: "10.0.0.2:6379", role: roleReplica, masterID: "953ae6a"},
+	}
+	var discoveryErr error
+	clients := map[string]*fakeClient{}
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	cfg := &config{ServiceName: "cache", Discovery: discoveryConfig{Mode: discoveryModeSentinel}}
+	r := newDiscoveryRunnable(cfg, func(context.Context) ([]redisNode, error) {
+		return topology, discoveryErr
+	}, func(addr string) client {
+		c := newFakeClient()
+		clients[addr] = c
+		return c
+	}, consumer, zap.NewNop())
+	require.NoError(t, r.Setup())
+
+	require.NoError(t, r.Run(context.Background()))
+	require.Len(t, consumer.AllMetrics(), 2)
+	// The nodes are scraped concurrently, in any order.
+	mds := metricsByAddress(consumer)
+	assert.Equal(t, map[string]string{
+		"service.name":    "cache",
+		"redis.address":   "10.0.0.1:6379",
+		"redis.role":      "master",
+		"redis.master_id": "953ae6a",
+	}, mds["10.0.0.1:6379"].Resource.Labels)
+	assert.Equal(t, "replica", mds["10.0.0.2:6379"].Resource.Labels["redis.role"])
+
+	// After a failover the replica is promoted and the old master is gone.
+	topology = []redisNode{{addr: "10.0.0.2:6379", role: roleMaster, masterID: "0b3e4ac"}}
+	r.lastRefresh = time.Time{}
+	require.NoError(t, r.Run(context.Background()))
+	require.Len(t, consumer.AllMetrics(), 3)
+	assert.Equal(t, "master", consumer.AllMetrics()[2].Resource.Labels["redis.role"])
+	assert.Equal(t, "0b3e4ac", consumer.AllMetrics()[2].Resource.Labels["redis.master_id"])
+	assert.True(t, clients["10.0.0.1:6379"].closed)
+	assert.False(t, clients["10.0.0.2:6379"].closed)
+
+	// The known nodes keep being scraped when the topology is not refreshed.
+	discoveryErr = errors.New("connection refused")
+	r.lastRefresh = time.Time{}
+	require.NoError(t, r.Run(context.Background()))
+	require.Len(t, consumer.AllMetrics(), 4)
+	assert.Equal(t, "10.0.0.2:6379", consumer.AllMetrics()[3].Resource.Labels["redis.address"])
+
+	r.close()
+	assert.True(t, clients["10.0.0.2:6379"].closed)
+}
+
+func TestDiscoveryRunnableErrors(t *testing.T) {
+	topology := []redisNode{
+		{addr: "10.0.0.1:6379", role: roleMaster, masterID: "953ae6a"},
+		{addr: "10.0.0.2:6379", role: roleReplica, masterID: "953ae6a"},
+	}
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	cfg := &config{Discovery: discoveryConfig{Mode: discoveryModeSentinel}}
+	r := newDiscoveryRunnable(cfg, func(context.Context) ([]redisNode, error) {
+		return topology, nil
+	}, func(addr string) client {
+		c := newFakeClient()
+		if addr == "10.0.0.1:6379" {
+			c.err = errors.New("connection refused")
+		}
+		return c
+	}, consumer, zap.NewNop())
+	require.NoError(t, r.Setup())
+
+	// The other nodes are scraped when one fails.
+	err := r.Run(context.Background())
+	assert.EqualError(t, err, "failed to scrape 10.0.0.1:6379: connection refused")
+	require.Len(t, consumer.AllMetrics(), 1)
+	assert.Equal(t, "10.0.0.2:6379", consumer.AllMetrics()[0].Resource.Labels["redis.address"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, r.Run(ctx))
+	assert.Len(t, consumer.AllMetrics(), 1)
+}
+
+func TestDiscoveryRunnableSlowNode(t *testing.T) {
+	topology := []redisNode{
+		{addr: "10.0.0.1:6379", role: roleMaster, masterID: "953ae6a"},
+		{addr: "10.0.0.2:6379", role: roleReplica, masterID: "953ae6a"},
+	}
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	cfg := &config{Discovery: discoveryConfig{Mode: discoveryModeSentinel}}
+	r := newDiscoveryRunnable(cfg, func(context.Context) ([]redisNode, error) {
+		return topology, nil
+	}, func(addr string) client {
+		c := newFakeClient()
+		// The first node, in address order, never replies.
+		c.block = addr == "10.0.0.1:6379"
+		return c
+	}, consumer, zap.NewNop())
+	require.NoError(t, r.Setup())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := r.Run(ctx)
+	assert.EqualError(t, err, "failed to scrape 10.0.0.1:6379: context deadline exceeded")
+	require.Len(t, consumer.AllMetrics(), 1)
+	assert.Equal(t, "10.0.0.2:6379", consumer.AllMetrics()[0].Resource.Labels["redis.address"])
+}
+
+// Returns the metrics received from each of the nodes, by address.
+func metricsByAddress(consumer *exportertest.SinkMetricsExporterOld) map[string]consumerdata.MetricsData {
+	mds := map[string]consumerdata.MetricsData{}
+	for _, md := range consumer.AllMetrics() {
+		mds[md.Resource.Labels[addressLabel]] = md
+	}
+	return mds
+}
@@ -16,11 +16,13 @@ package redisreceiver
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 )
@@ -46,7 +48,15 @@ func (f *Factory) Type() configmodels.Type {
 
 // CreateDefaultConfig creates a default config.
 func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
-	return &config{CollectionInterval: 10 * time.Second}
+	return &config{
+		CollectionInterval: 10 * time.Second,
+		TLS: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		Discovery: discoveryConfig{
+			RefreshInterval: time.Minute,
+		},
+	}
 }
 
 // CreateTraceReceiver creates a trace Receiver. Not supported for now.
@@ -67,5 +77,12 @@ func (f *Factory) CreateMetricsReceiver(
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
-	return newRedisReceiver(logger, cfg.(*config), consumer), nil
+	rCfg := cfg.(*config)
+	switch rCfg.Discovery.Mode {
+	case "", discoveryModeCluster, discoveryModeSentinel:
+	default:
+		return nil, fmt.Errorf("invalid discovery mode %q, must be %q or %q",
+			rCfg.Discovery.Mode, discoveryModeCluster, discoveryModeSentinel)
+	}
+	return newRedisReceiver(logger, rCfg, consumer), nil
 }
@@ -17,7 +17,9 @@ package redisreceiver
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 )
@@ -77,6 +79,39 @@ func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
 	return protoMetrics, warnings
 }
 
+// Builds proto metrics from the 'commandstats' metrics in Redis INFO:
+// e.g. "cmdstat_get:calls=21,usec=175,usec_per_call=8.33". Only the commands
+// in the filter are kept, unless it is nil. Returns proto metrics and parsing
+// errors, to be treated as warnings, if there were any.
+func (i info) buildCommandStatsProtoMetrics(filter map[string]bool, t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	var commands []string
+	for key := range i {
+		if !strings.HasPrefix(key, commandStatsPrefix) {
+			continue
+		}
+		command := strings.TrimPrefix(key, commandStatsPrefix)
+		if filter != nil && !filter[command] {
+			continue
+		}
+		commands = append(commands, command)
+	}
+	// Sort the commands to get the metrics in a consistent order.
+	sort.Strings(commands)
+
+	for _, command := range commands {
+		cs, parsingError := parseCommandStatsString(command, i[commandStatsPrefix+command])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildCommandStatsMetrics(cs, t)...)
+	}
+	return protoMetrics, warnings
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
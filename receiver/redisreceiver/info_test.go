@@ -15,6 +15,7 @@
 package redisreceiver
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,7 +23,7 @@ import (
 
 func TestGetUptime(t *testing.T) {
 	svc := newRedisSvc(newFakeClient())
-	info, _ := svc.info()
+	info, _ := svc.info(context.Background())
 	uptime, err := info.getUptimeInSeconds()
 	require.Nil(t, err)
 	require.Equal(t, 104946, uptime)
@@ -0,0 +1,74 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// A latency event returned by the LATENCY LATEST command.
+type latencyEvent struct {
+	name string
+	// the latency of the latest spike of the event in milliseconds
+	latest int64
+	// the maximum latency of the event in milliseconds
+	max int64
+}
+
+// Turns the reply of LATENCY LATEST, an array of events each made of a name,
+// the unix timestamp of the latest spike, the latency of the latest spike and
+// the maximum latency, into latencyEvent structs.
+func parseLatencyLatest(reply interface{}) ([]latencyEvent, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected latency reply %v", reply)
+	}
+	events := make([]latencyEvent, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected latency event %v", item)
+		}
+		name, ok := fields[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected latency event name %v", fields[0])
+		}
+		latest, ok := fields[2].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected latency of event %s: %v", name, fields[2])
+		}
+		max, ok := fields[3].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected maximum latency of event %s: %v", name, fields[3])
+		}
+		events = append(events, latencyEvent{name: name, latest: latest, max: max})
+	}
+	return events, nil
+}
+
+// Builds the latency metrics of the events, sorted by event name.
+func buildLatencyProtoMetrics(events []latencyEvent, t *timeBundle) []*metricspb.Metric {
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].name < events[j].name
+	})
+	var metrics []*metricspb.Metric
+	for i := range events {
+		metrics = append(metrics, buildLatencyMetrics(&events[i], t)...)
+	}
+	return metrics
+}
@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyLatest(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{"fork", int64(1405067976), int64(93), int64(93)},
+		[]interface{}{"command", int64(1405067822), int64(12), int64(305)},
+	}
+	events, err := parseLatencyLatest(reply)
+	require.NoError(t, err)
+	assert.Equal(t, []latencyEvent{
+		{name: "fork", latest: 93, max: 93},
+		{name: "command", latest: 12, max: 305},
+	}, events)
+
+	metrics := buildLatencyProtoMetrics(events, newTimeBundle(time.Now(), 100))
+	require.Len(t, metrics, 4)
+	assert.Equal(t, "redis/latency/latest", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, "command", metrics[0].Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(12), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "redis/latency/max", metrics[1].MetricDescriptor.Name)
+	assert.Equal(t, int64(305), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "fork", metrics[3].Timeseries[0].LabelValues[0].Value)
+}
+
+func TestParseLatencyLatestInvalid(t *testing.T) {
+	for _, reply := range []interface{}{
+		"OK",
+		[]interface{}{"fork"},
+		[]interface{}{[]interface{}{"fork", int64(1405067976), int64(93)}},
+		[]interface{}{[]interface{}{int64(1), int64(1405067976), int64(93), int64(93)}},
+		[]interface{}{[]interface{}{"fork", int64(1405067976), "93", int64(93)}},
+		[]interface{}{[]interface{}{"fork", int64(1405067976), int64(93), "93"}},
+	} {
+		_, err := parseLatencyLatest(reply)
+		assert.Error(t, err, "%v", reply)
+	}
+}
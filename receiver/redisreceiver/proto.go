@@ -24,11 +24,15 @@ import (
 
 // Helper functions that produce protobuf
 
-func newMetricsData(protoMetrics []*metricspb.Metric, serviceName string) *consumerdata.MetricsData {
+func newMetricsData(protoMetrics []*metricspb.Metric, serviceName string, resourceLabels map[string]string) *consumerdata.MetricsData {
+	labels := map[string]string{"service.name": serviceName}
+	for k, v := range resourceLabels {
+		labels[k] = v
+	}
 	return &consumerdata.MetricsData{
 		Resource: &resourcepb.Resource{
 			Type:   typeStr,
-			Labels: map[string]string{"service.name": serviceName},
+			Labels: labels,
 		},
 		Metrics: protoMetrics,
 	}
@@ -73,6 +77,97 @@ func buildKeyspaceTTLMetric(k *keyspace, t *timeBundle) *metricspb.Metric {
 	return newProtoMetric(m, pt, t)
 }
 
+func buildCommandStatsMetrics(cs *commandStats, t *timeBundle) []*metricspb.Metric {
+	return []*metricspb.Metric{
+		buildCommandCallsMetric(cs, t),
+		buildCommandTimeMetric(cs, t),
+	}
+}
+
+func buildCommandCallsMetric(cs *commandStats, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/commands/calls",
+		labels: map[string]string{"command": cs.command},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		desc:   "Number of calls of the command",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: cs.calls}}
+	return newProtoMetric(m, pt, t)
+}
+
+func buildCommandTimeMetric(cs *commandStats, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/commands/cpu_time",
+		units:  "us",
+		labels: map[string]string{"command": cs.command},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		desc:   "CPU time consumed by the calls of the command",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: cs.usec}}
+	return newProtoMetric(m, pt, t)
+}
+
+func buildSlowLogMetrics(command string, s *slowLogStats, t *timeBundle) []*metricspb.Metric {
+	return []*metricspb.Metric{
+		buildSlowLogCountMetric(command, s, t),
+		buildSlowLogDurationMetric(command, s, t),
+	}
+}
+
+func buildSlowLogCountMetric(command string, s *slowLogStats, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/slowlog/count",
+		labels: map[string]string{"command": command},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		desc:   "Number of calls of the command logged in the slow log",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: s.count}}
+	return newProtoMetric(m, pt, t)
+}
+
+func buildSlowLogDurationMetric(command string, s *slowLogStats, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/slowlog/duration",
+		units:  "us",
+		labels: map[string]string{"command": command},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		desc:   "Execution time of the calls of the command logged in the slow log",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: s.duration}}
+	return newProtoMetric(m, pt, t)
+}
+
+func buildLatencyMetrics(e *latencyEvent, t *timeBundle) []*metricspb.Metric {
+	return []*metricspb.Metric{
+		buildLatencyLatestMetric(e, t),
+		buildLatencyMaxMetric(e, t),
+	}
+}
+
+func buildLatencyLatestMetric(e *latencyEvent, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/latency/latest",
+		units:  "ms",
+		labels: map[string]string{"event": e.name},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		desc:   "Latency of the latest spike of the event",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: e.latest}}
+	return newProtoMetric(m, pt, t)
+}
+
+func buildLatencyMaxMetric(e *latencyEvent, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/latency/max",
+		units:  "ms",
+		labels: map[string]string{"event": e.name},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		desc:   "Maximum latency of the event",
+	}
+	pt := &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: e.max}}
+	return newProtoMetric(m, pt, t)
+}
+
 // Create new protobuf Metric.
 // Arguments:
 //   * redisMetric -- the fixed metadata to build the protobuf metric
@@ -15,6 +15,7 @@
 package redisreceiver
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -87,7 +88,7 @@ func TestAllMetrics(t *testing.T) {
 
 func TestKeyspaceMetrics(t *testing.T) {
 	svc := newRedisSvc(newFakeClient())
-	info, _ := svc.info()
+	info, _ := svc.info(context.Background())
 	m, err := info.buildKeyspaceProtoMetrics(getDefaultTimeBundle())
 	require.Nil(t, err)
 
@@ -137,7 +138,7 @@ func TestNewProtoMetric(t *testing.T) {
 
 func fetchMetrics(redisMetrics []*redisMetric) ([]*metricspb.Metric, []error, error) {
 	svc := newRedisSvc(newFakeClient())
-	info, err := svc.info()
+	info, err := svc.info(context.Background())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -161,12 +162,12 @@ func getMetricData(t *testing.T, metric *redisMetric, serverName string) *consum
 func getMetricDataErr(metric *redisMetric, serverName string) (*consumerdata.MetricsData, []error, error) {
 	redisMetrics := []*redisMetric{metric}
 	svc := newRedisSvc(newFakeClient())
-	info, err := svc.info()
+	info, err := svc.info(context.Background())
 	if err != nil {
 		return nil, nil, err
 	}
 	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultTimeBundle())
-	md := newMetricsData(protoMetrics, serverName)
+	md := newMetricsData(protoMetrics, serverName, nil)
 	return md, warnings, nil
 }
 
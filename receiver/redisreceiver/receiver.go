@@ -22,7 +22,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 )
 
 type redisReceiver struct {
@@ -30,6 +30,10 @@ type redisReceiver struct {
 	config         *config
 	consumer       consumer.MetricsConsumerOld
 	intervalRunner *interval.Runner
+	// client is nil when discovery is enabled.
+	client client
+	// discoveryRunnable is nil unless discovery is enabled.
+	discoveryRunnable *discoveryRunnable
 }
 
 func newRedisReceiver(
@@ -46,23 +50,68 @@ func newRedisReceiver(
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
-	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, c, r.config.ServiceName, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
+	tlsConfig, err := r.config.TLS.LoadTLSConfig()
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		if err := r.intervalRunner.Start(); err != nil {
-			host.ReportFatalError(err)
-		}
-	}()
+	newClient := func(addr string, username string, password string) client {
+		return newRedisClient(&redis.Options{
+			Addr:      addr,
+			Username:  username,
+			Password:  password,
+			TLSConfig: tlsConfig,
+		})
+	}
+	newNodeClient := func(addr string) client {
+		return newClient(addr, r.config.Username, r.config.Password)
+	}
 
-	return nil
+	var runnable interval.Runnable
+	if r.config.Discovery.Mode == "" {
+		r.client = newNodeClient(r.config.Endpoint)
+		runnable = newRedisRunnable(r.client, r.config, r.consumer, r.logger)
+	} else {
+		seeds := r.config.Discovery.Seeds
+		if len(seeds) == 0 {
+			seeds = []string{r.config.Endpoint}
+		}
+		var discover func(ctx context.Context) ([]redisNode, error)
+		switch r.config.Discovery.Mode {
+		case discoveryModeCluster:
+			discover = func(ctx context.Context) ([]redisNode, error) {
+				return discoverClusterNodes(ctx, seeds, newNodeClient)
+			}
+		case discoveryModeSentinel:
+			discover = func(ctx context.Context) ([]redisNode, error) {
+				return discoverSentinelNodes(ctx, seeds, func(addr string) client {
+					return newClient(addr, "", r.config.Discovery.SentinelPassword)
+				})
+			}
+		}
+		r.discoveryRunnable = newDiscoveryRunnable(r.config, discover, newNodeClient, r.consumer, r.logger)
+		runnable = r.discoveryRunnable
+	}
+	r.intervalRunner = interval.NewRunner(
+		r.config.CollectionInterval,
+		[]interval.Runnable{runnable},
+		interval.WithErrorHandler(func(err error) {
+			r.logger.Error("failed to scrape redis", zap.Error(err))
+		}),
+	)
+	return r.intervalRunner.Start()
 }
 
+// Stop the interval runner, waiting for the scrape in progress, and close the
+// connections to Redis.
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
+	if r.intervalRunner == nil {
+		return nil
+	}
 	r.intervalRunner.Stop()
-	return nil
+	if r.discoveryRunnable != nil {
+		r.discoveryRunnable.close()
+		return nil
+	}
+	return r.client.close()
 }
@@ -0,0 +1,294 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestReceiverTLSAndACL(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	srv := newFakeRedisServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+	defer srv.close()
+
+	f := Factory{}
+	cfg := f.CreateDefaultConfig().(*config)
+	cfg.Endpoint = srv.addr()
+	// The interval is also the timeout of a scrape, which takes a few TLS
+	// handshakes.
+	cfg.CollectionInterval = time.Second
+	cfg.Username = "otel"
+	cfg.Password = "secret"
+	cfg.TLS.Insecure = false
+	cfg.TLS.CAFile = certFile
+	cfg.TLS.ServerName = "localhost"
+	cfg.SlowLog = true
+	cfg.LatencyMonitor = true
+
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	rcvr, err := f.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, consumer)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	require.Eventually(t, func() bool {
+		return len(consumer.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond, "failed to receive any metrics")
+	assert.Equal(t, []string{"otel", "secret"}, srv.authArgs())
+
+	var latency []string
+	for _, m := range consumer.AllMetrics()[0].Metrics {
+		if m.MetricDescriptor.Name == "redis/latency/max" {
+			latency = append(latency, m.Timeseries[0].LabelValues[0].Value)
+		}
+	}
+	assert.Equal(t, []string{"command"}, latency)
+}
+
+func TestReceiverInvalidTLS(t *testing.T) {
+	f := Factory{}
+	cfg := f.CreateDefaultConfig().(*config)
+	cfg.Endpoint = "localhost:6379"
+	cfg.TLS.Insecure = false
+	cfg.TLS.CAFile = "./testdata/missing.crt"
+
+	rcvr, err := f.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, &exportertest.SinkMetricsExporterOld{})
+	require.NoError(t, err)
+	assert.Error(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestReceiverClusterDiscovery(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+
+	srv := newFakeRedisServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+	defer srv.close()
+
+	f := Factory{}
+	cfg := f.CreateDefaultConfig().(*config)
+	// The interval is also the timeout of a scrape, which takes a few TLS
+	// handshakes.
+	cfg.CollectionInterval = time.Second
+	cfg.TLS.Insecure = false
+	cfg.TLS.CAFile = certFile
+	cfg.TLS.ServerName = "localhost"
+	cfg.Discovery.Mode = discoveryModeCluster
+	cfg.Discovery.Seeds = []string{srv.addr()}
+
+	consumer := &exportertest.SinkMetricsExporterOld{}
+	rcvr, err := f.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, consumer)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	require.Eventually(t, func() bool {
+		return len(consumer.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond, "failed to receive any metrics")
+	assert.Equal(t, map[string]string{
+		"service.name":             "",
+		"redis.address":            srv.addr(),
+		"redis.role":               "master",
+		"redis.master_id":          "e7d1eec",
+		"redis.cluster.slot_range": "0-16383",
+	}, consumer.AllMetrics()[0].Resource.Labels)
+}
+
+func TestReceiverInvalidDiscoveryMode(t *testing.T) {
+	f := Factory{}
+	cfg := f.CreateDefaultConfig().(*config)
+	cfg.Discovery.Mode = "replication"
+
+	_, err := f.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, &exportertest.SinkMetricsExporterOld{})
+	assert.Error(t, err)
+}
+
+// fakeRedisServer is a stand-in for Redis over TLS: it accepts AUTH, answers
+// INFO with testdata/info.txt, SLOWLOG GET and LATENCY LATEST with a single
+// entry, and CLUSTER NODES with itself as the only node.
+type fakeRedisServer struct {
+	t  *testing.T
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu   sync.Mutex
+	auth []string
+}
+
+func newFakeRedisServer(t *testing.T, tlsConfig *tls.Config) *fakeRedisServer {
+	ln, err := tls.Listen("tcp", "localhost:0", tlsConfig)
+	require.NoError(t, err)
+	s := &fakeRedisServer{t: t, ln: ln}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+func (s *fakeRedisServer) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *fakeRedisServer) close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *fakeRedisServer) authArgs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auth
+}
+
+func (s *fakeRedisServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedisServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = args[1:]
+			s.mu.Unlock()
+			reply = "+OK\r\n"
+		case "INFO":
+			info, err := readFile("info")
+			if err != nil {
+				reply = fmt.Sprintf("-ERR %v\r\n", err)
+				break
+			}
+			info = strings.ReplaceAll(info, "\n", "\r\n")
+			reply = fmt.Sprintf("$%d\r\n%s\r\n", len(info), info)
+		case "SLOWLOG":
+			reply = "*1\r\n*4\r\n:1\r\n:1309448221\r\n:15\r\n*2\r\n$3\r\nget\r\n$3\r\nfoo\r\n"
+		case "CLUSTER":
+			nodes := fmt.Sprintf("e7d1eec %s@1 myself,master - 0 0 1 connected 0-16383\n", s.addr())
+			reply = fmt.Sprintf("$%d\r\n%s\r\n", len(nodes), nodes)
+		case "LATENCY":
+			reply = "*1\r\n*4\r\n$7\r\ncommand\r\n:1405067822\r\n:12\r\n:305\r\n"
+		default:
+			reply = fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent by a client as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// writeTestCertificate writes a self-signed certificate for localhost, and its
+// key, to a temporary directory.
+func writeTestCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "redisreceiver")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
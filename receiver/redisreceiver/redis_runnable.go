@@ -16,13 +16,14 @@ package redisreceiver
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 )
 
 var _ interval.Runnable = (*redisRunnable)(nil)
@@ -30,25 +31,45 @@ var _ interval.Runnable = (*redisRunnable)(nil)
 // Runs intermittently, fetching info from Redis, creating metrics/datapoints,
 // and feeding them to a metricsConsumer.
 type redisRunnable struct {
-	ctx             context.Context
 	metricsConsumer consumer.MetricsConsumerOld
 	redisSvc        *redisSvc
 	redisMetrics    []*redisMetric
 	logger          *zap.Logger
 	timeBundle      *timeBundle
 	serviceName     string
+	// resourceLabels are added to the "service.name" Resource label, they
+	// identify the node when the nodes are discovered.
+	resourceLabels map[string]string
+	// commandFilter restricts the commandstats metrics to its commands, all
+	// commands are kept when it is nil.
+	commandFilter map[string]bool
+	// slowLog is nil unless the slow log metrics are enabled.
+	slowLog        *slowLogTracker
+	latencyMonitor bool
 }
 
 func newRedisRunnable(
-	ctx context.Context,
 	client client,
-	serviceName string,
+	cfg *config,
 	metricsConsumer consumer.MetricsConsumerOld,
 	logger *zap.Logger,
 ) *redisRunnable {
+	var commandFilter map[string]bool
+	if len(cfg.Commands) > 0 {
+		commandFilter = make(map[string]bool, len(cfg.Commands))
+		for _, command := range cfg.Commands {
+			commandFilter[strings.ToLower(command)] = true
+		}
+	}
+	var slowLog *slowLogTracker
+	if cfg.SlowLog {
+		slowLog = newSlowLogTracker()
+	}
 	return &redisRunnable{
-		ctx:             ctx,
-		serviceName:     serviceName,
+		serviceName:     cfg.ServiceName,
+		commandFilter:   commandFilter,
+		slowLog:         slowLog,
+		latencyMonitor:  cfg.LatencyMonitor,
 		redisSvc:        newRedisSvc(client),
 		metricsConsumer: metricsConsumer,
 		logger:          logger,
@@ -66,22 +87,24 @@ func (r *redisRunnable) Setup() error {
 // the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
 // defined at startup time. Then builds 'keyspace' metrics if there are any
 // keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
-func (r *redisRunnable) Run() error {
+// active Redis database, of which there can be 16. Then builds the
+// 'commandstats' metrics of the commands called since the server start.
+// Finally builds the slow log and latency metrics when they are enabled.
+func (r *redisRunnable) Run(ctx context.Context) error {
 	const dataFormat = "redis"
 	const transport = "http" // todo verify this
-	ctx := obsreport.StartMetricsReceiveOp(r.ctx, dataFormat, transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
 
-	inf, err := r.redisSvc.info()
+	inf, err := r.redisSvc.info(ctx)
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
-		return nil
+		return err
 	}
 
 	uptime, err := inf.getUptimeInSeconds()
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
-		return nil
+		return err
 	}
 
 	if r.timeBundle == nil {
@@ -107,11 +130,39 @@ func (r *redisRunnable) Run() error {
 		)
 	}
 
-	md := newMetricsData(metrics, r.serviceName)
+	commandStatsMetrics, warnings := inf.buildCommandStatsProtoMetrics(r.commandFilter, r.timeBundle)
+	metrics = append(metrics, commandStatsMetrics...)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing commandstats string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
 
-	err = r.metricsConsumer.ConsumeMetricsData(r.ctx, *md)
+	if r.slowLog != nil {
+		entries, err := r.redisSvc.client.retrieveSlowLog(ctx)
+		if err != nil {
+			r.logger.Warn("failed to retrieve the slow log", zap.Error(err))
+		} else {
+			r.slowLog.update(entries, time.Now())
+			metrics = append(metrics, r.slowLog.buildProtoMetrics(r.timeBundle)...)
+		}
+	}
+
+	if r.latencyMonitor {
+		events, err := r.redisSvc.client.retrieveLatencyLatest(ctx)
+		if err != nil {
+			r.logger.Warn("failed to retrieve the latency events", zap.Error(err))
+		} else {
+			metrics = append(metrics, buildLatencyProtoMetrics(events, r.timeBundle)...)
+		}
+	}
+
+	md := newMetricsData(metrics, r.serviceName, r.resourceLabels)
+
+	err = r.metricsConsumer.ConsumeMetricsData(ctx, *md)
 	numTimeSeries, numPoints := obsreport.CountMetricPoints(*md)
 	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
 
-	return nil
+	return err
 }
@@ -16,6 +16,7 @@ package redisreceiver
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,13 +27,62 @@ import (
 func TestRedisRunnable(t *testing.T) {
 	consumer := &fakeMetricsConsumer{}
 	logger, _ := zap.NewDevelopment()
-	runner := newRedisRunnable(context.Background(), newFakeClient(), "", consumer, logger)
+	runner := newRedisRunnable(newFakeClient(), &config{}, consumer, logger)
 	err := runner.Setup()
 	require.Nil(t, err)
-	err = runner.Run()
+	err = runner.Run(context.Background())
 	require.Nil(t, err)
 	// + 6 because there are two keyspace entries each of which has three metrics
-	require.Equal(t, len(getDefaultRedisMetrics())+6, len(consumer.md.Metrics))
+	// + 6 because there are three commandstats entries each of which has two metrics
+	require.Equal(t, len(getDefaultRedisMetrics())+6+6, len(consumer.md.Metrics))
+}
+
+func TestRedisRunnableCommandFilter(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	logger, _ := zap.NewDevelopment()
+	runner := newRedisRunnable(newFakeClient(), &config{Commands: []string{"GET", "del"}}, consumer, logger)
+	require.Nil(t, runner.Setup())
+	require.Nil(t, runner.Run(context.Background()))
+
+	var commands []string
+	for _, m := range consumer.md.Metrics {
+		if m.MetricDescriptor.Name == "redis/commands/calls" {
+			commands = append(commands, m.Timeseries[0].LabelValues[0].Value)
+		}
+	}
+	require.Equal(t, []string{"get"}, commands)
+}
+
+func TestRedisRunnableSlowLogAndLatency(t *testing.T) {
+	consumer := &fakeMetricsConsumer{}
+	client := newFakeClient()
+	client.slowLog = []slowLogEntry{{id: 1, duration: 10, command: "get"}}
+	client.latencyEvents = []latencyEvent{{name: "command", latest: 12, max: 305}}
+	runner := newRedisRunnable(client, &config{SlowLog: true, LatencyMonitor: true}, consumer, zap.NewNop())
+	require.Nil(t, runner.Setup())
+	require.Nil(t, runner.Run(context.Background()))
+	// The entries logged before the first run are not counted.
+	require.Equal(t, []string{"redis/latency/latest", "redis/latency/max"}, slowLogAndLatencyMetrics(consumer.md))
+
+	client.slowLog = append([]slowLogEntry{{id: 2, duration: 20, command: "set"}}, client.slowLog...)
+	require.Nil(t, runner.Run(context.Background()))
+	require.Equal(t, []string{
+		"redis/slowlog/count",
+		"redis/slowlog/duration",
+		"redis/latency/latest",
+		"redis/latency/max",
+	}, slowLogAndLatencyMetrics(consumer.md))
+}
+
+func slowLogAndLatencyMetrics(md consumerdata.MetricsData) []string {
+	var names []string
+	for _, m := range md.Metrics {
+		name := m.MetricDescriptor.Name
+		if strings.HasPrefix(name, "redis/slowlog/") || strings.HasPrefix(name, "redis/latency/") {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 type fakeMetricsConsumer struct {
@@ -14,7 +14,10 @@
 
 package redisreceiver
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 // Wraps a client, parses the Redis info command, returning a string-string map
 // containing all of the key value pairs returned by INFO. Takes a line delimiter
@@ -34,8 +37,8 @@ func newRedisSvc(client client) *redisSvc {
 }
 
 // Calls the Redis INFO command on the client and returns an `info` map.
-func (p *redisSvc) info() (info, error) {
-	str, err := p.client.retrieveInfo()
+func (p *redisSvc) info(ctx context.Context) (info, error) {
+	str, err := p.client.retrieveInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
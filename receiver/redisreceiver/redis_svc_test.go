@@ -15,19 +15,20 @@
 package redisreceiver
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func newFakeAPIParser() *redisSvc {
-	return newRedisSvc(fakeClient{})
+	return newRedisSvc(newFakeClient())
 }
 
 func TestParser(t *testing.T) {
 	s := newFakeAPIParser()
-	info, err := s.info()
+	info, err := s.info(context.Background())
 	require.Nil(t, err)
-	require.Equal(t, 123, len(info))
+	require.Equal(t, 126, len(info))
 	require.Equal(t, "1.24", info["allocator_frag_ratio"]) // spot check
 }
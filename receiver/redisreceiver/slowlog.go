@@ -0,0 +1,157 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// An entry of the slow log returned by the SLOWLOG GET command.
+type slowLogEntry struct {
+	id int64
+	// the execution time of the command in microseconds
+	duration int64
+	// the lowercased name of the command
+	command string
+}
+
+// Turns the reply of SLOWLOG GET, an array of entries each made of an id, a
+// unix timestamp, a duration and the arguments of the command, into
+// slowLogEntry structs. The entries following the arguments, the address and
+// name of the client since Redis 4.0, are ignored.
+func parseSlowLog(reply interface{}) ([]slowLogEntry, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected slowlog reply %v", reply)
+	}
+	entries := make([]slowLogEntry, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected slowlog entry %v", item)
+		}
+		id, ok := fields[0].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected slowlog entry id %v", fields[0])
+		}
+		duration, ok := fields[2].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected slowlog entry duration %v", fields[2])
+		}
+		args, ok := fields[3].([]interface{})
+		if !ok || len(args) == 0 {
+			return nil, fmt.Errorf("unexpected slowlog entry arguments %v", fields[3])
+		}
+		command, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected slowlog entry command %v", args[0])
+		}
+		entries = append(entries, slowLogEntry{
+			id:       id,
+			duration: duration,
+			command:  strings.ToLower(command),
+		})
+	}
+	return entries, nil
+}
+
+// Accumulates the number and the duration of the slow commands, per command,
+// from the successive SLOWLOG GET replies. The id of the last entry seen is
+// kept so that the entries still in the slow log on the next poll are not
+// counted twice.
+type slowLogTracker struct {
+	// the time the tracking started, the start of the cumulative metrics
+	start time.Time
+	// the id of the most recent entry seen, -1 before the first poll
+	lastID int64
+	stats  map[string]*slowLogStats
+}
+
+type slowLogStats struct {
+	count    int64
+	duration int64
+}
+
+func newSlowLogTracker() *slowLogTracker {
+	return &slowLogTracker{
+		lastID: -1,
+		stats:  map[string]*slowLogStats{},
+	}
+}
+
+// Accumulates the entries more recent than the last one seen. The entries
+// already in the slow log on the first poll are only used to find the last
+// id: they happened before the tracking started. If the most recent id is
+// lower than the last one seen the server is assumed to have restarted, and
+// all of the entries are new.
+func (s *slowLogTracker) update(entries []slowLogEntry, now time.Time) {
+	var maxID int64 = -1
+	for _, e := range entries {
+		if e.id > maxID {
+			maxID = e.id
+		}
+	}
+
+	if s.start.IsZero() {
+		s.start = now
+		s.lastID = maxID
+		return
+	}
+	if maxID < s.lastID {
+		s.lastID = -1
+	}
+
+	for _, e := range entries {
+		if e.id <= s.lastID {
+			continue
+		}
+		stats, ok := s.stats[e.command]
+		if !ok {
+			stats = &slowLogStats{}
+			s.stats[e.command] = stats
+		}
+		stats.count++
+		stats.duration += e.duration
+	}
+	if maxID > s.lastID {
+		s.lastID = maxID
+	}
+}
+
+// Builds the slow log metrics of the commands seen since the tracking
+// started, sorted by command.
+func (s *slowLogTracker) buildProtoMetrics(t *timeBundle) []*metricspb.Metric {
+	commands := make([]string, 0, len(s.stats))
+	for command := range s.stats {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	start, _ := ptypes.TimestampProto(s.start)
+	var metrics []*metricspb.Metric
+	for _, command := range commands {
+		for _, m := range buildSlowLogMetrics(command, s.stats[command], t) {
+			m.Timeseries[0].StartTimestamp = start
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,97 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSlowLog(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{int64(14), int64(1309448221), int64(15), []interface{}{"GET", "foo"}, "127.0.0.1:58217", "worker"},
+		[]interface{}{int64(13), int64(1309448128), int64(30), []interface{}{"slowlog", "get", "100"}},
+	}
+	entries, err := parseSlowLog(reply)
+	require.NoError(t, err)
+	assert.Equal(t, []slowLogEntry{
+		{id: 14, duration: 15, command: "get"},
+		{id: 13, duration: 30, command: "slowlog"},
+	}, entries)
+}
+
+func TestParseSlowLogInvalid(t *testing.T) {
+	for _, reply := range []interface{}{
+		"OK",
+		[]interface{}{"OK"},
+		[]interface{}{[]interface{}{int64(1), int64(1309448221), int64(15)}},
+		[]interface{}{[]interface{}{"1", int64(1309448221), int64(15), []interface{}{"get"}}},
+		[]interface{}{[]interface{}{int64(1), int64(1309448221), "15", []interface{}{"get"}}},
+		[]interface{}{[]interface{}{int64(1), int64(1309448221), int64(15), []interface{}{}}},
+	} {
+		_, err := parseSlowLog(reply)
+		assert.Error(t, err, "%v", reply)
+	}
+}
+
+func TestSlowLogTracker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	tracker := newSlowLogTracker()
+
+	// The entries of the first poll happened before the tracking started.
+	tracker.update([]slowLogEntry{
+		{id: 1, duration: 10, command: "get"},
+		{id: 0, duration: 20, command: "get"},
+	}, start)
+	assert.Empty(t, tracker.stats)
+
+	tracker.update([]slowLogEntry{
+		{id: 3, duration: 30, command: "set"},
+		{id: 2, duration: 40, command: "get"},
+		{id: 1, duration: 10, command: "get"},
+	}, start.Add(time.Second))
+	tracker.update([]slowLogEntry{
+		{id: 4, duration: 50, command: "get"},
+		{id: 3, duration: 30, command: "set"},
+		{id: 2, duration: 40, command: "get"},
+	}, start.Add(2*time.Second))
+	assert.Equal(t, map[string]*slowLogStats{
+		"get": {count: 2, duration: 90},
+		"set": {count: 1, duration: 30},
+	}, tracker.stats)
+
+	// A lower id means that the server restarted.
+	tracker.update([]slowLogEntry{
+		{id: 0, duration: 5, command: "set"},
+	}, start.Add(3*time.Second))
+	assert.Equal(t, map[string]*slowLogStats{
+		"get": {count: 2, duration: 90},
+		"set": {count: 2, duration: 35},
+	}, tracker.stats)
+	assert.Equal(t, int64(0), tracker.lastID)
+
+	metrics := tracker.buildProtoMetrics(newTimeBundle(start.Add(3*time.Second), 100))
+	require.Len(t, metrics, 4)
+	assert.Equal(t, "redis/slowlog/count", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, "get", metrics[0].Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(2), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, start.Unix(), metrics[0].Timeseries[0].StartTimestamp.Seconds)
+	assert.Equal(t, "redis/slowlog/duration", metrics[3].MetricDescriptor.Name)
+	assert.Equal(t, "set", metrics[3].Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(35), metrics[3].Timeseries[0].Points[0].GetInt64Value())
+}
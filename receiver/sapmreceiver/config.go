@@ -27,4 +27,8 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// JaegerThriftHTTP enables the reception of Jaeger Thrift batches posted to
+	// /api/traces, on the same server as SAPM.
+	JaegerThriftHTTP bool `mapstructure:"jaeger_thrift_http"`
 }
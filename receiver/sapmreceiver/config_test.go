@@ -42,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 
 	// The receiver `sapm/disabled` doesn't count because disabled receivers
 	// are excluded from the final list.
-	assert.Equal(t, len(cfg.Receivers), 4)
+	assert.Equal(t, len(cfg.Receivers), 5)
 
 	r0 := cfg.Receivers["sapm"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -91,4 +91,17 @@ func TestLoadConfig(t *testing.T) {
 				AccessTokenPassthrough: true,
 			},
 		})
+
+	r4 := cfg.Receivers["sapm/jaeger"].(*Config)
+	assert.Equal(t, r4,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "sapm/jaeger",
+			},
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: ":7276",
+			},
+			JaegerThriftHTTP: true,
+		})
 }
@@ -4,6 +4,7 @@ go 1.14
 
 require (
 	github.com/Azure/go-autorest/autorest/adal v0.9.0 // indirect
+	github.com/apache/thrift v0.13.0
 	github.com/gorilla/mux v1.7.4
 	github.com/jaegertracing/jaeger v1.18.2-0.20200707061226-97d2319ff2be
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
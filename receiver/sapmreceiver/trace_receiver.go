@@ -20,16 +20,20 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/gorilla/mux"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"github.com/signalfx/sapm-proto/sapmprotocol"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
 	"go.uber.org/zap"
@@ -37,6 +41,17 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
 )
 
+const (
+	// jaegerThriftHTTPEndpoint is the path Jaeger clients post Thrift batches to.
+	jaegerThriftHTTPEndpoint = "/api/traces"
+)
+
+// acceptedThriftFormats are the content types of Jaeger Thrift batches.
+var acceptedThriftFormats = map[string]struct{}{
+	"application/x-thrift":                 {},
+	"application/vnd.apache.thrift.binary": {},
+}
+
 var gzipWriterPool = &sync.Pool{
 	New: func() interface{} {
 		return gzip.NewWriter(ioutil.Discard)
@@ -78,28 +93,90 @@ func (sr *sapmReceiver) handleRequest(ctx context.Context, req *http.Request) er
 	ctx = obsreport.StartTraceDataReceiveOp(ctx, sr.config.Name(), transport)
 
 	td := jaegertranslator.ProtoBatchesToInternalTraces(sapm.Batches)
+	sr.addAccessToken(td, req)
 
-	if sr.config.AccessTokenPassthrough {
-		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
-			rSpans := td.ResourceSpans()
-			for i := 0; i < rSpans.Len(); i++ {
-				rSpan := rSpans.At(i)
-				if !rSpan.IsNil() {
-					attrs := rSpan.Resource().Attributes()
-					attrs.UpsertString(splunk.SFxAccessTokenLabel, accessToken)
-				}
-			}
+	// pass the trace data to the next consumer
+	err = sr.nextConsumer.ConsumeTraces(ctx, td)
+	if err != nil {
+		err = fmt.Errorf("error passing trace data to next consumer: %v", err.Error())
+	}
+
+	obsreport.EndTraceDataReceiveOp(ctx, "protobuf", td.SpanCount(), err)
+	return err
+}
+
+// addAccessToken adds the access token of the request to the resources of the
+// traces if the access token passthrough is enabled
+func (sr *sapmReceiver) addAccessToken(td pdata.Traces, req *http.Request) {
+	if !sr.config.AccessTokenPassthrough {
+		return
+	}
+	accessToken := req.Header.Get(splunk.SFxAccessTokenHeader)
+	if accessToken == "" {
+		return
+	}
+	rSpans := td.ResourceSpans()
+	for i := 0; i < rSpans.Len(); i++ {
+		rSpan := rSpans.At(i)
+		if !rSpan.IsNil() {
+			attrs := rSpan.Resource().Attributes()
+			attrs.UpsertString(splunk.SFxAccessTokenLabel, accessToken)
 		}
 	}
+}
+
+// decodeThriftBatch reads the Jaeger Thrift batch of the request
+func decodeThriftBatch(req *http.Request) (*jaeger.Batch, error) {
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse content type: %v", err)
+	}
+	if _, ok := acceptedThriftFormats[contentType]; !ok {
+		return nil, fmt.Errorf("unsupported content type: %v", contentType)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read body: %v", err)
+	}
+
+	batch := &jaeger.Batch{}
+	if err = thrift.NewTDeserializer().Read(batch, bodyBytes); err != nil {
+		return nil, fmt.Errorf("cannot deserialize Jaeger Thrift batch: %v", err)
+	}
+	return batch, nil
+}
+
+// handleJaegerThriftRequest handles Jaeger Thrift batches posted over http the
+// same way as the Jaeger collector does
+func (sr *sapmReceiver) handleJaegerThriftRequest(rw http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if sr.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), sr.config.Name(), transport, "")
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, sr.config.Name(), transport)
+
+	batch, err := decodeThriftBatch(req)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		obsreport.EndTraceDataReceiveOp(ctx, "thrift", 0, err)
+		return
+	}
+
+	td := jaegertranslator.ThriftBatchToInternalTraces(batch)
+	sr.addAccessToken(td, req)
 
 	// pass the trace data to the next consumer
 	err = sr.nextConsumer.ConsumeTraces(ctx, td)
 	if err != nil {
 		err = fmt.Errorf("error passing trace data to next consumer: %v", err.Error())
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	} else {
+		rw.WriteHeader(http.StatusAccepted)
 	}
 
-	obsreport.EndTraceDataReceiveOp(ctx, "protobuf", td.SpanCount(), err)
-	return err
+	obsreport.EndTraceDataReceiveOp(ctx, "thrift", td.SpanCount(), err)
 }
 
 // HTTPHandlerFunction returns an http.HandlerFunc that handles SAPM requests
@@ -182,6 +259,9 @@ func (sr *sapmReceiver) Start(_ context.Context, host component.Host) error {
 		// use gorilla mux to create a router/handler
 		nr := mux.NewRouter()
 		nr.HandleFunc(sapmprotocol.TraceEndpointV2, sr.HTTPHandlerFunc)
+		if sr.config.JaegerThriftHTTP {
+			nr.HandleFunc(jaegerThriftHTTPEndpoint, sr.handleJaegerThriftRequest).Methods(http.MethodPost)
+		}
 
 		// create a server with the handler
 		sr.server = sr.config.HTTPServerSettings.ToServer(nr)
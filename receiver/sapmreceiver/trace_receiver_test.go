@@ -27,7 +27,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/jaegertracing/jaeger/model"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	otlptrace "github.com/open-telemetry/opentelemetry-proto/gen/go/trace/v1"
 	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"github.com/signalfx/sapm-proto/sapmprotocol"
@@ -391,3 +393,115 @@ func TestAccessTokenPassthrough(t *testing.T) {
 		})
 	}
 }
+
+// sendJaegerThrift acts as a Jaeger client posting a Thrift batch to the receiver.
+func sendJaegerThrift(endpoint string, batch *jaeger.Batch, contentType string, token string) (*http.Response, error) {
+	reqBytes, err := thrift.NewTSerializer().Write(context.Background(), batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Jaeger Thrift batch %v", err.Error())
+	}
+
+	url := fmt.Sprintf("http://%s%s", endpoint, jaegerThriftHTTPEndpoint)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(reqBytes))
+	req.Header.Set("Content-Type", contentType)
+	if token != "" {
+		req.Header.Set("x-sf-token", token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return resp, fmt.Errorf("failed to send request to receiver %v", err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+func TestJaegerThriftHTTPReception(t *testing.T) {
+	batch := &jaeger.Batch{
+		Process: &jaeger.Process{ServiceName: "issaTest"},
+		Spans: []*jaeger.Span{
+			{
+				TraceIdLow:    0x0102030405060708,
+				TraceIdHigh:   0x1112131415161718,
+				SpanId:        0x2122232425262728,
+				OperationName: "DBSearch",
+				StartTime:     1542158650536343,
+				Duration:      2000000,
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		jaegerThriftHTTP bool
+		contentType      string
+		wantStatus       int
+		wantSpans        int
+	}{
+		{
+			name:             "enabled",
+			jaegerThriftHTTP: true,
+			contentType:      "application/x-thrift",
+			wantStatus:       http.StatusAccepted,
+			wantSpans:        1,
+		},
+		{
+			name:             "binary thrift content type",
+			jaegerThriftHTTP: true,
+			contentType:      "application/vnd.apache.thrift.binary",
+			wantStatus:       http.StatusAccepted,
+			wantSpans:        1,
+		},
+		{
+			name:             "invalid content type",
+			jaegerThriftHTTP: true,
+			contentType:      "application/json",
+			wantStatus:       http.StatusBadRequest,
+		},
+		{
+			name:        "disabled",
+			contentType: "application/x-thrift",
+			wantStatus:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				HTTPServerSettings: confighttp.HTTPServerSettings{
+					Endpoint: defaultEndpoint,
+				},
+				AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
+					AccessTokenPassthrough: true,
+				},
+				JaegerThriftHTTP: tt.jaegerThriftHTTP,
+			}
+
+			sink := new(exportertest.SinkTraceExporter)
+			sr := setupReceiver(t, config, sink)
+			defer sr.Shutdown(context.Background())
+
+			resp, err := sendJaegerThrift(config.Endpoint, batch, tt.contentType, "MyAccessToken")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			got := sink.AllTraces()
+			if tt.wantSpans == 0 {
+				assert.Len(t, got, 0)
+				return
+			}
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.wantSpans, got[0].SpanCount())
+
+			rs := got[0].ResourceSpans().At(0)
+			serviceName, ok := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+			assert.True(t, ok)
+			assert.Equal(t, "issaTest", serviceName.StringVal())
+			accessToken, ok := rs.Resource().Attributes().Get(splunk.SFxAccessTokenLabel)
+			assert.True(t, ok)
+			assert.Equal(t, "MyAccessToken", accessToken.StringVal())
+
+			span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+			assert.Equal(t, "DBSearch", span.Name())
+		})
+	}
+}
@@ -27,4 +27,8 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// List of exporters to which the events received on "/v2/event" should be
+	// forwarded to. The exporters must implement EventExporter.
+	EventExporters []string `mapstructure:"event_exporters"`
 }
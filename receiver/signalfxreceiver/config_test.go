@@ -60,6 +60,7 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: true,
 			},
+			EventExporters: []string{"exampleexporter"},
 		})
 
 	r2 := cfg.Receivers["signalfx/tls"].(*Config)
@@ -0,0 +1,141 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.uber.org/zap"
+)
+
+// EventExporter provides an interface to implement ConsumeSignalFxEvents in
+// exporters that support SignalFx events, e.g. the SignalFx exporter.
+type EventExporter interface {
+	// ConsumeSignalFxEvents is invoked with the events received on "/v2/event".
+	// The access token is the one of the request when access token passthrough
+	// is enabled, otherwise it is empty.
+	ConsumeSignalFxEvents(ctx context.Context, events []*sfxpb.Event, accessToken string) error
+}
+
+type eventConsumer func(ctx context.Context, events []*sfxpb.Event, accessToken string) error
+
+// setupEventExporters returns the consumers of the events for the exporters
+// listed in the config, sorted by exporter name.
+func setupEventExporters(
+	logger *zap.Logger,
+	exporters map[configmodels.Exporter]component.Exporter,
+	eventExportersFromConfig []string,
+) ([]eventConsumer, error) {
+
+	configuredExporters := make(map[string]bool, len(exporters))
+	for cfg := range exporters {
+		configuredExporters[cfg.Name()] = true
+	}
+	eventExportersSet := make(map[string]bool, len(eventExportersFromConfig))
+	for _, name := range eventExportersFromConfig {
+		if !configuredExporters[name] {
+			return nil, fmt.Errorf("failed to configure event_exporters: %s exporter is not in collector config", name)
+		}
+		eventExportersSet[name] = true
+	}
+
+	var cfgs []configmodels.Exporter
+	for cfg := range exporters {
+		if eventExportersSet[cfg.Name()] {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	sort.Slice(cfgs, func(i, j int) bool {
+		return cfgs[i].Name() < cfgs[j].Name()
+	})
+
+	var out []eventConsumer
+	for _, cfg := range cfgs {
+		ee, ok := exporters[cfg].(EventExporter)
+		if !ok {
+			return nil, fmt.Errorf("%s exporter does not implement EventExporter", cfg.Name())
+		}
+		out = append(out, ee.ConsumeSignalFxEvents)
+		logger.Info("Configured SignalFx EventExporter",
+			zap.String("exporter_name", cfg.Name()),
+		)
+	}
+
+	return out, nil
+}
+
+// signalFxV2Events returns the valid events with their dimensions and
+// properties cleaned up: dimensions without key or value and properties
+// without key or with a value not set to exactly one type are dropped.
+func signalFxV2Events(logger *zap.Logger, sfxEvents []*sfxpb.Event) []*sfxpb.Event {
+	events := make([]*sfxpb.Event, 0, len(sfxEvents))
+	for _, sfxEvent := range sfxEvents {
+		if sfxEvent == nil {
+			continue
+		}
+		if sfxEvent.EventType == "" {
+			logger.Debug("SignalFx event without event type dropped")
+			continue
+		}
+
+		event := &sfxpb.Event{
+			EventType: sfxEvent.EventType,
+			Category:  sfxEvent.Category,
+			Timestamp: sfxEvent.Timestamp,
+		}
+		for _, dim := range sfxEvent.Dimensions {
+			if dim == nil || dim.Key == "" || dim.Value == "" {
+				continue
+			}
+			event.Dimensions = append(event.Dimensions, dim)
+		}
+		for _, prop := range sfxEvent.Properties {
+			if prop == nil || prop.Key == "" || !isValidPropertyValue(prop.Value) {
+				logger.Debug("SignalFx event property dropped",
+					zap.String("event_type", sfxEvent.EventType),
+					zap.String("property", prop.GetKey()))
+				continue
+			}
+			event.Properties = append(event.Properties, prop)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func isValidPropertyValue(v *sfxpb.PropertyValue) bool {
+	if v == nil {
+		return false
+	}
+	set := 0
+	if v.StrValue != nil {
+		set++
+	}
+	if v.DoubleValue != nil {
+		set++
+	}
+	if v.IntValue != nil {
+		set++
+	}
+	if v.BoolValue != nil {
+		set++
+	}
+	return set == 1
+}
@@ -0,0 +1,164 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"context"
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+// mockEventExporter is a metrics exporter that also consumes events.
+type mockEventExporter struct {
+	exportertest.SinkMetricsExporterOld
+	events       []*sfxpb.Event
+	accessTokens []string
+	err          error
+}
+
+func (m *mockEventExporter) ConsumeSignalFxEvents(_ context.Context, events []*sfxpb.Event, accessToken string) error {
+	m.events = append(m.events, events...)
+	m.accessTokens = append(m.accessTokens, accessToken)
+	return m.err
+}
+
+// mockHost is a host exposing the given metrics exporters.
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+		configmodels.MetricsDataType: h.exporters,
+	}
+}
+
+func TestSetupEventExporters(t *testing.T) {
+	exporters := map[configmodels.Exporter]component.Exporter{
+		&configmodels.ExporterSettings{NameVal: "signalfx"}: &mockEventExporter{},
+		&configmodels.ExporterSettings{NameVal: "other"}:    &exportertest.SinkMetricsExporterOld{},
+	}
+	tests := []struct {
+		name          string
+		fromConfig    []string
+		wantConsumers int
+		wantErr       bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:          "event_exporter",
+			fromConfig:    []string{"signalfx"},
+			wantConsumers: 1,
+		},
+		{
+			name:       "not_an_event_exporter",
+			fromConfig: []string{"signalfx", "other"},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown_exporter",
+			fromConfig: []string{"missing"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumers, err := setupEventExporters(zap.NewNop(), exporters, tt.fromConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, consumers, tt.wantConsumers)
+		})
+	}
+}
+
+func TestSetupEventExportersOrder(t *testing.T) {
+	names := []string{"signalfx/c", "signalfx/a", "signalfx/b"}
+	exporters := make(map[configmodels.Exporter]component.Exporter, len(names))
+	exps := make(map[string]*mockEventExporter, len(names))
+	for _, name := range names {
+		exps[name] = &mockEventExporter{}
+		exporters[&configmodels.ExporterSettings{NameVal: name}] = exps[name]
+	}
+
+	consumers, err := setupEventExporters(zap.NewNop(), exporters, names)
+	require.NoError(t, err)
+	require.Len(t, consumers, len(names))
+	for i, name := range []string{"signalfx/a", "signalfx/b", "signalfx/c"} {
+		require.NoError(t, consumers[i](context.Background(), nil, ""))
+		assert.Len(t, exps[name].accessTokens, 1, name)
+	}
+}
+
+func TestSignalFxV2Events(t *testing.T) {
+	str := "v"
+	i := int64(3)
+	category := sfxpb.EventCategory_ALERT
+	in := []*sfxpb.Event{
+		nil,
+		{
+			Dimensions: []*sfxpb.Dimension{{Key: "k", Value: "v"}},
+		},
+		{
+			EventType: "deploy",
+			Category:  &category,
+			Timestamp: 1574092046000,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "k", Value: "v"},
+				{Key: "", Value: "v"},
+				{Key: "empty", Value: ""},
+				nil,
+			},
+			Properties: []*sfxpb.Property{
+				{Key: "str", Value: &sfxpb.PropertyValue{StrValue: &str}},
+				{Key: "int", Value: &sfxpb.PropertyValue{IntValue: &i}},
+				{Key: "both", Value: &sfxpb.PropertyValue{StrValue: &str, IntValue: &i}},
+				{Key: "none", Value: &sfxpb.PropertyValue{}},
+				{Key: "nil"},
+				{Key: "", Value: &sfxpb.PropertyValue{StrValue: &str}},
+				nil,
+			},
+		},
+	}
+
+	want := []*sfxpb.Event{
+		{
+			EventType: "deploy",
+			Category:  &category,
+			Timestamp: 1574092046000,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "k", Value: "v"},
+			},
+			Properties: []*sfxpb.Property{
+				{Key: "str", Value: &sfxpb.PropertyValue{StrValue: &str}},
+				{Key: "int", Value: &sfxpb.PropertyValue{IntValue: &i}},
+			},
+		},
+	}
+	assert.Equal(t, want, signalFxV2Events(zap.NewNop(), in))
+}
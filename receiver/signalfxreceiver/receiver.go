@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
@@ -33,6 +34,7 @@ import (
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/conventions"
@@ -44,34 +46,42 @@ import (
 const (
 	defaultServerTimeout = 20 * time.Second
 
-	responseOK                 = "OK"
-	responseInvalidMethod      = "Only \"POST\" method is supported"
-	responseInvalidContentType = "\"Content-Type\" must be \"application/x-protobuf\""
-	responseInvalidEncoding    = "\"Content-Encoding\" must be \"gzip\" or empty"
-	responseErrGzipReader      = "Error on gzip body"
-	responseErrReadBody        = "Failed to read message body"
-	responseErrUnmarshalBody   = "Failed to unmarshal message body"
-	responseErrNextConsumer    = "Internal Server Error"
+	responseOK                          = "OK"
+	responseInvalidMethod               = "Only \"POST\" method is supported"
+	responseInvalidContentType          = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidContentTypeDatapoint = "\"Content-Type\" must be \"application/x-protobuf\" or \"application/json\""
+	responseInvalidContentTypeJSON      = "\"Content-Type\" must be \"application/json\""
+	responseInvalidEncoding             = "\"Content-Encoding\" must be \"gzip\" or empty"
+	responseErrGzipReader               = "Error on gzip body"
+	responseErrReadBody                 = "Failed to read message body"
+	responseErrUnmarshalBody            = "Failed to unmarshal message body"
+	responseErrNextConsumer             = "Internal Server Error"
+	responseNoEventExporters            = "No \"event_exporters\" configured, events are not supported"
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
 )
 
 var (
-	errNilNextConsumer = errors.New("nil nextConsumer")
-	errEmptyEndpoint   = errors.New("empty endpoint")
-
-	okRespBody               = initJSONResponse(responseOK)
-	invalidMethodRespBody    = initJSONResponse(responseInvalidMethod)
-	invalidContentRespBody   = initJSONResponse(responseInvalidContentType)
-	invalidEncodingRespBody  = initJSONResponse(responseInvalidEncoding)
-	errGzipReaderRespBody    = initJSONResponse(responseErrGzipReader)
-	errReadBodyRespBody      = initJSONResponse(responseErrReadBody)
-	errUnmarshalBodyRespBody = initJSONResponse(responseErrUnmarshalBody)
-	errNextConsumerRespBody  = initJSONResponse(responseErrNextConsumer)
+	errNilNextConsumer  = errors.New("nil nextConsumer")
+	errEmptyEndpoint    = errors.New("empty endpoint")
+	errNoEventExporters = errors.New("no event exporters configured")
+
+	okRespBody                      = initJSONResponse(responseOK)
+	invalidMethodRespBody           = initJSONResponse(responseInvalidMethod)
+	invalidContentRespBody          = initJSONResponse(responseInvalidContentType)
+	invalidDatapointContentRespBody = initJSONResponse(responseInvalidContentTypeDatapoint)
+	invalidJSONContentRespBody      = initJSONResponse(responseInvalidContentTypeJSON)
+	invalidEncodingRespBody         = initJSONResponse(responseInvalidEncoding)
+	errGzipReaderRespBody           = initJSONResponse(responseErrGzipReader)
+	errReadBodyRespBody             = initJSONResponse(responseErrReadBody)
+	errUnmarshalBodyRespBody        = initJSONResponse(responseErrUnmarshalBody)
+	errNextConsumerRespBody         = initJSONResponse(responseErrNextConsumer)
+	noEventExportersRespBody        = initJSONResponse(responseNoEventExporters)
 )
 
 // sfxReceiver implements the component.MetricsReceiver for SignalFx metric protocol.
@@ -81,6 +91,9 @@ type sfxReceiver struct {
 	config       *Config
 	nextConsumer consumer.MetricsConsumerOld
 	server       *http.Server
+	// eventConsumers are the functions consuming the events received on
+	// "/v2/event", set up on start from the configured event exporters.
+	eventConsumers []eventConsumer
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -121,7 +134,11 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 
 	err := componenterror.ErrAlreadyStarted
 	r.startOnce.Do(func() {
-		err = nil
+		r.eventConsumers, err = setupEventExporters(
+			r.logger, host.GetExporters()[configmodels.MetricsDataType], r.config.EventExporters)
+		if err != nil {
+			return
+		}
 
 		var ln net.Listener
 		// set up the listener
@@ -133,6 +150,8 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 
 		mx := mux.NewRouter()
 		mx.HandleFunc("/v2/datapoint", r.handleReq)
+		mx.HandleFunc("/v1/datapoint", r.handleV1Req)
+		mx.HandleFunc("/v2/event", r.handleEventReq)
 
 		r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -165,58 +184,76 @@ func (r *sfxReceiver) Shutdown(context.Context) error {
 }
 
 func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
-	if r.config.TLSSetting != nil {
-		transport = "https"
-	}
-	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
-	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
-
-	if req.Method != http.MethodPost {
-		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return
-	}
+	ctx := r.startMetricsReceiveOp(req)
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
+	body, contentType, ok := r.readBody(
+		ctx, resp, req, invalidDatapointContentRespBody, protobufContentType, jsonContentType)
+	if !ok {
 		return
 	}
 
-	encoding := req.Header.Get(httpContentEncodingHeader)
-	if encoding != "" && encoding != gzipEncoding {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return
-	}
-
-	bodyReader := req.Body
-	if encoding == gzipEncoding {
+	var datapoints []*sfxpb.DataPoint
+	if contentType == jsonContentType {
 		var err error
-		bodyReader, err = gzip.NewReader(bodyReader)
-		if err != nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
+		if datapoints, err = decodeJSONDatapointsV2(body); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+			return
+		}
+	} else {
+		msg := &sfxpb.DataPointUploadMessage{}
+		if err := msg.Unmarshal(body); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 			return
 		}
+		datapoints = msg.Datapoints
 	}
 
-	body, err := ioutil.ReadAll(bodyReader)
-	if err != nil {
-		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
+	r.consumeDatapoints(ctx, resp, req, datapoints)
+}
+
+// handleV1Req handles the data points sent in the legacy JSON format of
+// "/v1/datapoint".
+func (r *sfxReceiver) handleV1Req(resp http.ResponseWriter, req *http.Request) {
+	ctx := r.startMetricsReceiveOp(req)
+
+	body, _, ok := r.readBody(ctx, resp, req, invalidJSONContentRespBody, jsonContentType)
+	if !ok {
 		return
 	}
 
-	msg := &sfxpb.DataPointUploadMessage{}
-	if err = msg.Unmarshal(body); err != nil {
+	datapoints, err := decodeJSONDatapointsV1(body)
+	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
-	if len(msg.Datapoints) == 0 {
+	r.consumeDatapoints(ctx, resp, req, datapoints)
+}
+
+func (r *sfxReceiver) startMetricsReceiveOp(req *http.Request) context.Context {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	return obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+}
+
+// consumeDatapoints converts the data points and passes them to the next
+// consumer, writing the response of the request.
+func (r *sfxReceiver) consumeDatapoints(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	datapoints []*sfxpb.DataPoint,
+) {
+	if len(datapoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	md, _ := SignalFxV2ToMetricsData(r.logger, msg.Datapoints)
+	md, _ := SignalFxV2ToMetricsData(r.logger, datapoints)
 
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
@@ -230,12 +267,12 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	err = r.nextConsumer.ConsumeMetricsData(ctx, *md)
+	err := r.nextConsumer.ConsumeMetricsData(ctx, *md)
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Datapoints),
-		len(msg.Datapoints),
+		len(datapoints),
+		len(datapoints),
 		err)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
@@ -246,6 +283,101 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 	resp.Write(okRespBody)
 }
 
+func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx, _ = trace.StartSpan(ctx, "receiver/"+r.config.Name()+"/EventReceive")
+
+	if len(r.eventConsumers) == 0 {
+		r.failRequest(ctx, resp, http.StatusNotImplemented, noEventExportersRespBody, errNoEventExporters)
+		return
+	}
+
+	body, _, ok := r.readBody(ctx, resp, req, invalidContentRespBody, protobufContentType)
+	if !ok {
+		return
+	}
+
+	msg := &sfxpb.EventUploadMessage{}
+	if err := msg.Unmarshal(body); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	events := signalFxV2Events(r.logger, msg.Events)
+	if len(events) > 0 {
+		accessToken := ""
+		if r.config.AccessTokenPassthrough {
+			accessToken = req.Header.Get(splunk.SFxAccessTokenHeader)
+		}
+		for _, consume := range r.eventConsumers {
+			if err := consume(ctx, events, accessToken); err != nil {
+				r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
+				return
+			}
+		}
+	}
+
+	trace.FromContext(ctx).End()
+	resp.WriteHeader(http.StatusAccepted)
+	resp.Write(okRespBody)
+}
+
+// readBody validates the request and reads its, possibly compressed, body,
+// returning it with its content type which must be one of the given ones. On
+// failure the response is written and false is returned.
+func (r *sfxReceiver) readBody(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	invalidContentTypeRespBody []byte,
+	contentTypes ...string,
+) ([]byte, string, bool) {
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return nil, "", false
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get(httpContentTypeHeader))
+	validContentType := false
+	for _, ct := range contentTypes {
+		if contentType == ct {
+			validContentType = true
+			break
+		}
+	}
+	if !validContentType {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentTypeRespBody, nil)
+		return nil, "", false
+	}
+
+	encoding := req.Header.Get(httpContentEncodingHeader)
+	if encoding != "" && encoding != gzipEncoding {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
+		return nil, "", false
+	}
+
+	bodyReader := req.Body
+	if encoding == gzipEncoding {
+		var err error
+		bodyReader, err = gzip.NewReader(bodyReader)
+		if err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
+			return nil, "", false
+		}
+	}
+
+	body, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
+		return nil, "", false
+	}
+	return body, contentType, true
+}
+
 func (r *sfxReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,9 +37,11 @@ import (
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -161,6 +164,157 @@ func Test_signalfxeceiver_EndToEnd(t *testing.T) {
 	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
 }
 
+func Test_signalfxeceiver_EventsEndToEnd(t *testing.T) {
+	port := testutil.GetAvailablePort(t)
+	addr := fmt.Sprintf("localhost:%d", port)
+	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.AccessTokenPassthrough = true
+	cfg.EventExporters = []string{"events"}
+	r, err := New(zap.NewNop(), *cfg, new(exportertest.SinkMetricsExporterOld))
+	require.NoError(t, err)
+
+	eventExp := &mockEventExporter{}
+	host := &mockHost{
+		exporters: map[configmodels.Exporter]component.Exporter{
+			&configmodels.ExporterSettings{NameVal: "events"}: eventExp,
+		},
+	}
+	require.NoError(t, r.Start(context.Background(), host))
+	runtime.Gosched()
+	defer r.Shutdown(context.Background())
+
+	category := sfxpb.EventCategory_USER_DEFINED
+	str := "v1.2.3"
+	events := []*sfxpb.Event{
+		{
+			EventType: "deploy",
+			Category:  &category,
+			Timestamp: 1574092046000,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "service", Value: "api"},
+			},
+			Properties: []*sfxpb.Property{
+				{Key: "version", Value: &sfxpb.PropertyValue{StrValue: &str}},
+			},
+		},
+	}
+
+	expCfg := &signalfxexporter.Config{
+		IngestURL:   "http://" + addr + "/v2/datapoint",
+		APIURL:      "http://localhost",
+		AccessToken: "access_token",
+	}
+	expCfg.AccessTokenPassthrough = true
+	exp, err := signalfxexporter.New(expCfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, testutil.WaitForPort(t, port))
+	defer exp.Shutdown(context.Background())
+
+	require.Implements(t, (*EventExporter)(nil), exp)
+	require.NoError(t, exp.(EventExporter).ConsumeSignalFxEvents(context.Background(), events, "request_token"))
+
+	assert.Equal(t, events, eventExp.events)
+	assert.Equal(t, []string{"request_token"}, eventExp.accessTokens)
+}
+
+func Test_sfxReceiver_handleEventReq(t *testing.T) {
+	config := (&Factory{}).CreateDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	msgBytes, err := (&sfxpb.EventUploadMessage{
+		Events: []*sfxpb.Event{{EventType: "deploy", Timestamp: time.Now().Unix() * 1e3}},
+	}).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		req              *http.Request
+		consumeErr       error
+		noEventExporters bool
+		wantStatus       int
+		wantBody         string
+		wantEvents       int
+	}{
+		{
+			name: "no_event_exporters",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			noEventExporters: true,
+			wantStatus:       http.StatusNotImplemented,
+			wantBody:         responseNoEventExporters,
+		},
+		{
+			name:       "incorrect_content_type",
+			req:        httptest.NewRequest("POST", "http://localhost/v2/event", nil),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   responseInvalidContentType,
+		},
+		{
+			name: "bad_data_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader([]byte{1, 2, 3, 4}))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   responseErrUnmarshalBody,
+		},
+		{
+			name: "msg_accepted",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			wantStatus: http.StatusAccepted,
+			wantBody:   responseOK,
+			wantEvents: 1,
+		},
+		{
+			name: "consumer_error",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			consumeErr: errors.New("consumer error"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   responseErrNextConsumer,
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv, err := New(zap.NewNop(), *config, new(exportertest.SinkMetricsExporterOld))
+			require.NoError(t, err)
+
+			eventExp := &mockEventExporter{err: tt.consumeErr}
+			r := rcv.(*sfxReceiver)
+			if !tt.noEventExporters {
+				r.eventConsumers = []eventConsumer{eventExp.ConsumeSignalFxEvents}
+			}
+
+			w := httptest.NewRecorder()
+			r.handleEventReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+			assert.Len(t, eventExp.events, tt.wantEvents)
+		})
+	}
+}
+
 func Test_sfxReceiver_handleReq(t *testing.T) {
 	config := (&Factory{}).CreateDefaultConfig().(*Config)
 	config.Endpoint = "localhost:0" // Actually not creating the endpoint
@@ -190,7 +344,7 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusUnsupportedMediaType, status)
-				assert.Equal(t, responseInvalidContentType, body)
+				assert.Equal(t, responseInvalidContentTypeDatapoint, body)
 			},
 		},
 		{
@@ -295,6 +449,32 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 				assert.Equal(t, responseErrGzipReader, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `{"gauge": [{"metric": "g", "value": 1, "dimensions": {"k": "v"}}],
+					"cumulative_counter": [{"metric": "c", "value": 2.5, "timestamp": 1574092046000}]}`
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(`{"gauge": {}}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -319,6 +499,71 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_handleV1Req(t *testing.T) {
+	config := (&Factory{}).CreateDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+		wantMetrics int
+	}{
+		{
+			name:        "protobuf_not_supported",
+			contentType: "application/x-protobuf",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    responseInvalidContentTypeJSON,
+		},
+		{
+			name:        "bad_json_in_body",
+			contentType: "application/json",
+			body:        `{"source": "host1", "metric": `,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+		{
+			name:        "msg_accepted",
+			contentType: "application/json",
+			body:        `{"source": "host1", "metric": "m1", "value": 1} {"source": "host1", "metric": "m2", "value": 2.5}`,
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkMetricsExporterOld)
+			rcv, err := New(zap.NewNop(), *config, sink)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "http://localhost/v1/datapoint", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			rcv.(*sfxReceiver).handleV1Req(w, req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+
+			got := sink.AllMetrics()
+			if tt.wantMetrics == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			assert.Len(t, got[0].Metrics, tt.wantMetrics)
+		})
+	}
+}
+
 func Test_sfxReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := (&Factory{}).CreateDefaultConfig().(*Config)
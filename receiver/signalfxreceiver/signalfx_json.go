@@ -0,0 +1,182 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// sfxJSONDatapointV2 is a data point of the SignalFx JSON format of
+// "/v2/datapoint", see
+// https://developers.signalfx.com/ingest_data_reference.html#operation/Send%20Metrics.
+type sfxJSONDatapointV2 struct {
+	Metric     string                 `json:"metric"`
+	Timestamp  int64                  `json:"timestamp"`
+	Value      interface{}            `json:"value"`
+	Dimensions map[string]interface{} `json:"dimensions"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// sfxJSONDatapointV1 is a data point of the legacy SignalFx JSON format of
+// "/v1/datapoint".
+type sfxJSONDatapointV1 struct {
+	Source string      `json:"source"`
+	Metric string      `json:"metric"`
+	Value  interface{} `json:"value"`
+}
+
+// sfxSourceDimension is the dimension the source of the "/v1/datapoint" data
+// points is set to.
+const sfxSourceDimension = "sf_source"
+
+var sfxJSONMetricTypes = map[string]sfxpb.MetricType{
+	"gauge":              sfxpb.MetricType_GAUGE,
+	"counter":            sfxpb.MetricType_COUNTER,
+	"cumulative_counter": sfxpb.MetricType_CUMULATIVE_COUNTER,
+}
+
+// decodeJSONDatapointsV2 decodes a body of the form
+// {"gauge": [...], "counter": [...], "cumulative_counter": [...]} to SignalFx
+// proto data points. The properties of the data points are added to their
+// dimensions.
+func decodeJSONDatapointsV2(body []byte) ([]*sfxpb.DataPoint, error) {
+	var msg map[string][]*sfxJSONDatapointV2
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&msg); err != nil {
+		return nil, err
+	}
+
+	// Sort the metric types so the order of the data points is stable.
+	types := make([]string, 0, len(msg))
+	for t := range msg {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var datapoints []*sfxpb.DataPoint
+	for _, t := range types {
+		metricType, ok := sfxJSONMetricTypes[t]
+		if !ok {
+			return nil, fmt.Errorf("unknown metric type %q", t)
+		}
+		for _, jsonDP := range msg[t] {
+			if jsonDP == nil {
+				continue
+			}
+			mt := metricType
+			dp := &sfxpb.DataPoint{
+				Metric:     jsonDP.Metric,
+				Timestamp:  jsonDP.Timestamp,
+				Value:      jsonDatum(jsonDP.Value),
+				MetricType: &mt,
+			}
+			dp.Dimensions = appendJSONDimensions(dp.Dimensions, jsonDP.Dimensions)
+			dp.Dimensions = appendJSONDimensions(dp.Dimensions, jsonDP.Properties)
+			datapoints = append(datapoints, dp)
+		}
+	}
+	return datapoints, nil
+}
+
+// decodeJSONDatapointsV1 decodes a body of one or more objects of the form
+// {"source": "...", "metric": "...", "value": ...} to SignalFx proto gauges.
+func decodeJSONDatapointsV1(body []byte) ([]*sfxpb.DataPoint, error) {
+	var datapoints []*sfxpb.DataPoint
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	for {
+		var jsonDP sfxJSONDatapointV1
+		if err := decoder.Decode(&jsonDP); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		metricType := sfxpb.MetricType_GAUGE
+		dp := &sfxpb.DataPoint{
+			Metric:     jsonDP.Metric,
+			Value:      jsonDatum(jsonDP.Value),
+			MetricType: &metricType,
+		}
+		if jsonDP.Source != "" {
+			dp.Dimensions = []*sfxpb.Dimension{{Key: sfxSourceDimension, Value: jsonDP.Source}}
+		}
+		datapoints = append(datapoints, dp)
+	}
+	return datapoints, nil
+}
+
+// jsonDatum converts the JSON value of a data point. Values of other types
+// than number and string result in an empty datum, and the data point is then
+// dropped during the conversion to metrics.
+func jsonDatum(v interface{}) sfxpb.Datum {
+	var datum sfxpb.Datum
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			datum.IntValue = &i
+		} else if f, err := val.Float64(); err == nil {
+			datum.DoubleValue = &f
+		}
+	case string:
+		datum.StrValue = &val
+	}
+	return datum
+}
+
+// appendJSONDimensions appends the JSON dimensions, sorted by key, skipping
+// the ones already present.
+func appendJSONDimensions(dims []*sfxpb.Dimension, jsonDims map[string]interface{}) []*sfxpb.Dimension {
+	keys := make([]string, 0, len(jsonDims))
+	for k := range jsonDims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+KEYS:
+	for _, k := range keys {
+		for _, dim := range dims {
+			if dim.Key == k {
+				continue KEYS
+			}
+		}
+		value, ok := jsonDimensionValue(jsonDims[k])
+		if !ok {
+			continue
+		}
+		dims = append(dims, &sfxpb.Dimension{Key: k, Value: value})
+	}
+	return dims
+}
+
+func jsonDimensionValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case json.Number:
+		return val.String(), true
+	case bool:
+		return strconv.FormatBool(val), true
+	}
+	return "", false
+}
@@ -0,0 +1,162 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDecodeJSONDatapointsV2(t *testing.T) {
+	body := `{
+		"gauge": [
+			{"metric": "g_int", "value": 3, "dimensions": {"host": "h1", "port": 80}},
+			{"metric": "g_double", "value": 1.5, "timestamp": 1574092046000,
+				"dimensions": {"host": "h1"}, "properties": {"host": "ignored", "env": "prod", "up": true}},
+			{"metric": "g_str", "value": "2.5"}
+		],
+		"counter": [
+			{"metric": "c", "value": 10}
+		],
+		"cumulative_counter": [
+			{"metric": "cc", "value": 1e3}
+		]
+	}`
+
+	datapoints, err := decodeJSONDatapointsV2([]byte(body))
+	require.NoError(t, err)
+
+	i3, i10 := int64(3), int64(10)
+	d15, d1000 := 1.5, 1000.0
+	s25 := "2.5"
+	gauge := sfxpb.MetricType_GAUGE
+	counter := sfxpb.MetricType_COUNTER
+	cumulative := sfxpb.MetricType_CUMULATIVE_COUNTER
+	want := []*sfxpb.DataPoint{
+		{
+			Metric:     "c",
+			Value:      sfxpb.Datum{IntValue: &i10},
+			MetricType: &counter,
+		},
+		{
+			Metric:     "cc",
+			Value:      sfxpb.Datum{DoubleValue: &d1000},
+			MetricType: &cumulative,
+		},
+		{
+			Metric:     "g_int",
+			Value:      sfxpb.Datum{IntValue: &i3},
+			MetricType: &gauge,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "host", Value: "h1"},
+				{Key: "port", Value: "80"},
+			},
+		},
+		{
+			Metric:     "g_double",
+			Timestamp:  1574092046000,
+			Value:      sfxpb.Datum{DoubleValue: &d15},
+			MetricType: &gauge,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "host", Value: "h1"},
+				{Key: "env", Value: "prod"},
+				{Key: "up", Value: "true"},
+			},
+		},
+		{
+			Metric:     "g_str",
+			Value:      sfxpb.Datum{StrValue: &s25},
+			MetricType: &gauge,
+		},
+	}
+	assert.Equal(t, want, datapoints)
+
+	// Counters keep their cumulative semantics once converted.
+	md, dropped := SignalFxV2ToMetricsData(zap.NewNop(), datapoints)
+	assert.Equal(t, 0, dropped)
+	require.Len(t, md.Metrics, 5)
+	assert.Equal(t, "CUMULATIVE_INT64", md.Metrics[0].MetricDescriptor.Type.String())
+	assert.Equal(t, "CUMULATIVE_DOUBLE", md.Metrics[1].MetricDescriptor.Type.String())
+}
+
+func TestDecodeJSONDatapointsV2Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "invalid_json",
+			body: `{"gauge": [`,
+		},
+		{
+			name: "not_a_list",
+			body: `{"gauge": {"metric": "m", "value": 1}}`,
+		},
+		{
+			name: "unknown_metric_type",
+			body: `{"histogram": [{"metric": "m", "value": 1}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeJSONDatapointsV2([]byte(tt.body))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeJSONDatapointsV1(t *testing.T) {
+	body := `{"source": "host1", "metric": "m1", "value": 1}
+		{"metric": "m2", "value": 2.5}
+		{"source": "host1", "metric": "m3", "value": true}`
+
+	datapoints, err := decodeJSONDatapointsV1([]byte(body))
+	require.NoError(t, err)
+
+	i1 := int64(1)
+	d25 := 2.5
+	gauge := sfxpb.MetricType_GAUGE
+	want := []*sfxpb.DataPoint{
+		{
+			Metric:     "m1",
+			Value:      sfxpb.Datum{IntValue: &i1},
+			MetricType: &gauge,
+			Dimensions: []*sfxpb.Dimension{{Key: "sf_source", Value: "host1"}},
+		},
+		{
+			Metric:     "m2",
+			Value:      sfxpb.Datum{DoubleValue: &d25},
+			MetricType: &gauge,
+		},
+		{
+			Metric:     "m3",
+			MetricType: &gauge,
+			Dimensions: []*sfxpb.Dimension{{Key: "sf_source", Value: "host1"}},
+		},
+	}
+	assert.Equal(t, want, datapoints)
+
+	// The data point without a valid value is dropped.
+	md, dropped := SignalFxV2ToMetricsData(zap.NewNop(), datapoints)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, md.Metrics, 2)
+
+	_, err = decodeJSONDatapointsV1([]byte(`{"metric": "m1", "value": 1} [`))
+	assert.Error(t, err)
+}
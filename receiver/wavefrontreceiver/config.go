@@ -19,6 +19,7 @@ import (
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 // Config defines configuration for the Wavefront receiver.
@@ -29,7 +30,23 @@ type Config struct {
 	// TCPIdleTimeout is the timout for idle TCP connections.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
+	// TLSSetting enables TLS on the TCP connections and, if a client CA file
+	// is set, the verification of the client certificates.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls_settings"`
+
 	// ExtractCollectdTags instructs the Wavefront receiver to attempt to extract
 	// tags in the CollectD format from the metric name. The default is false.
 	ExtractCollectdTags bool `mapstructure:"extract_collectd_tags"`
+
+	// DeltaCounters selects how the delta counters, whose names are prefixed
+	// with ∆ or Δ, are converted to cumulative metrics: "cumulative"
+	// accumulates the deltas of each time series, "delta" keeps the deltas and
+	// starts each point at the timestamp of the previous point of its time
+	// series. The default is "cumulative".
+	DeltaCounters string `mapstructure:"delta_counters"`
+
+	// DeltaCountersIdleTimeout is the duration after which the state of a delta
+	// counter time series that did not receive points is discarded, a new
+	// point then starts a new time series. The default is 5 minutes.
+	DeltaCountersIdleTimeout time.Duration `mapstructure:"delta_counters_idle_timeout"`
 }
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -55,8 +56,17 @@ func TestLoadConfig(t *testing.T) {
 			TCPAddr: confignet.TCPAddr{
 				Endpoint: "localhost:8080",
 			},
-			TCPIdleTimeout:      5 * time.Second,
-			ExtractCollectdTags: true,
+			TCPIdleTimeout:           5 * time.Second,
+			ExtractCollectdTags:      true,
+			DeltaCounters:            "delta",
+			DeltaCountersIdleTimeout: 10 * time.Minute,
+			TLSSetting: &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CertFile: "/var/lib/mycert.pem",
+					KeyFile:  "/var/lib/mykey.key",
+				},
+				ClientCAFile: "/var/lib/myca.pem",
+			},
 		},
 		r1)
 }
@@ -0,0 +1,171 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// deltaCountersCumulative accumulates the deltas of each delta counter time
+	// series into a cumulative time series.
+	deltaCountersCumulative = "cumulative"
+	// deltaCountersDelta keeps the deltas, each point of a delta counter time
+	// series starts at the timestamp of the previous point.
+	deltaCountersDelta = "delta"
+
+	// deltaCountersIdleTimeoutDefault is the default duration after which the
+	// state of idle delta counter time series is discarded.
+	deltaCountersIdleTimeoutDefault = 5 * time.Minute
+)
+
+// Delta counters are prefixed with either the increment (U+2206) or the greek
+// delta (U+0394) character, see
+// https://docs.wavefront.com/delta_counters.html.
+var deltaCounterPrefixes = []string{"∆", "Δ"}
+
+var errDeltaCountersNotSupported = errors.New("wavefront delta counters are not supported by this parser")
+
+// trimDeltaCounterPrefix returns the name without its delta counter prefix,
+// and whether it had one.
+func trimDeltaCounterPrefix(name string) (string, bool) {
+	for _, prefix := range deltaCounterPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return name[len(prefix):], true
+		}
+	}
+	return name, false
+}
+
+// deltaCounters converts the points of delta counters to cumulative points,
+// either accumulating the deltas or starting each point at the previous one
+// of its time series. It is safe for concurrent use since a single parser is
+// shared by all connections of a receiver.
+type deltaCounters struct {
+	accumulate bool
+	// now is used to measure the time series idle time, it can be replaced on
+	// tests.
+	now func() time.Time
+
+	mu     sync.Mutex
+	series *protocol.SeriesStates
+}
+
+// deltaSeriesState is the state kept for each delta counter time series. The
+// total is kept as an int64 so that it keeps its precision until a double
+// delta is seen, from then on the total and all the points of the time series
+// are doubles.
+type deltaSeriesState struct {
+	startTime     *timestamp.Timestamp
+	lastTimestamp *timestamp.Timestamp
+	double        bool
+	int64Total    int64
+	doubleTotal   float64
+}
+
+func newDeltaCounters(mode string, idleTimeout time.Duration) (*deltaCounters, error) {
+	var accumulate bool
+	switch mode {
+	case "", deltaCountersCumulative:
+		accumulate = true
+	case deltaCountersDelta:
+	default:
+		return nil, fmt.Errorf("invalid delta_counters %q, must be either %q or %q",
+			mode, deltaCountersCumulative, deltaCountersDelta)
+	}
+
+	if idleTimeout < 0 {
+		return nil, fmt.Errorf("invalid delta counters idle timeout: %v", idleTimeout)
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = deltaCountersIdleTimeoutDefault
+	}
+
+	return &deltaCounters{
+		accumulate: accumulate,
+		now:        time.Now,
+		series:     protocol.NewSeriesStates(idleTimeout),
+	}, nil
+}
+
+// convert turns the gauge parsed for a delta counter into a cumulative metric.
+func (dc *deltaCounters) convert(metric *metricspb.Metric) {
+	descriptor := metric.MetricDescriptor
+	switch descriptor.Type {
+	case metricspb.MetricDescriptor_GAUGE_INT64:
+		descriptor.Type = metricspb.MetricDescriptor_CUMULATIVE_INT64
+	case metricspb.MetricDescriptor_GAUGE_DOUBLE:
+		descriptor.Type = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+	default:
+		return
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	now := dc.now()
+	for _, ts := range metric.Timeseries {
+		for _, point := range ts.Points {
+			key := protocol.SeriesKey(descriptor.Name, descriptor.LabelKeys, ts.LabelValues)
+			state, ok := dc.series.Get(key, now).(*deltaSeriesState)
+			if !ok {
+				state = &deltaSeriesState{
+					startTime:     point.Timestamp,
+					lastTimestamp: point.Timestamp,
+				}
+			}
+
+			// Once a double delta was seen the time series only has doubles.
+			switch v := point.Value.(type) {
+			case *metricspb.Point_Int64Value:
+				if state.double {
+					point.Value = &metricspb.Point_DoubleValue{DoubleValue: float64(v.Int64Value)}
+					descriptor.Type = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+				}
+			case *metricspb.Point_DoubleValue:
+				if !state.double {
+					state.double = true
+					state.doubleTotal = float64(state.int64Total)
+				}
+			}
+
+			if dc.accumulate {
+				switch v := point.Value.(type) {
+				case *metricspb.Point_Int64Value:
+					state.int64Total += v.Int64Value
+					v.Int64Value = state.int64Total
+				case *metricspb.Point_DoubleValue:
+					state.doubleTotal += v.DoubleValue
+					v.DoubleValue = state.doubleTotal
+				}
+				ts.StartTimestamp = state.startTime
+			} else {
+				ts.StartTimestamp = state.lastTimestamp
+			}
+			state.lastTimestamp = point.Timestamp
+			dc.series.Set(key, state, now)
+		}
+	}
+}
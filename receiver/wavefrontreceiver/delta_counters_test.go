@@ -0,0 +1,203 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_deltaCounters(t *testing.T) {
+	type point struct {
+		line      string
+		want      *metricspb.Metric
+		wantStart int64
+	}
+	tests := []struct {
+		name   string
+		mode   string
+		points []point
+	}{
+		{
+			name: "cumulative",
+			mode: deltaCountersCumulative,
+			points: []point{
+				{
+					line:      "∆requests.count 5 1582230020 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_INT64, "app", 1582230020, 5),
+					wantStart: 1582230020,
+				},
+				{
+					line:      "Δrequests.count 3 1582230080 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_INT64, "app", 1582230080, 8),
+					wantStart: 1582230020,
+				},
+				{
+					line:      "∆requests.count 2 1582230080 source=other",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_INT64, "other", 1582230080, 2),
+					wantStart: 1582230080,
+				},
+				{
+					line:      "\"∆requests.count\" 0.5 1582230140 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, "app", 1582230140, 8.5),
+					wantStart: 1582230020,
+				},
+				{
+					line:      "∆requests.count 0.25 1582230200 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, "app", 1582230200, 8.75),
+					wantStart: 1582230020,
+				},
+			},
+		},
+		{
+			name: "delta",
+			mode: deltaCountersDelta,
+			points: []point{
+				{
+					line:      "∆requests.count 5 1582230020 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_INT64, "app", 1582230020, 5),
+					wantStart: 1582230020,
+				},
+				{
+					line:      "∆requests.count 3 1582230080 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_INT64, "app", 1582230080, 3),
+					wantStart: 1582230020,
+				},
+				{
+					line:      "∆requests.count 1.5 1582230140 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, "app", 1582230140, 1.5),
+					wantStart: 1582230080,
+				},
+				{
+					line:      "∆requests.count 2 1582230200 source=app",
+					want:      buildDeltaMetric(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, "app", 1582230200, 2),
+					wantStart: 1582230140,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc, err := newDeltaCounters(tt.mode, time.Minute)
+			require.NoError(t, err)
+			p := WavefrontParser{deltaCounters: dc}
+			for _, pt := range tt.points {
+				got, err := p.Parse(pt.line)
+				require.NoError(t, err)
+				pt.want.Timeseries[0].StartTimestamp = &timestamp.Timestamp{Seconds: pt.wantStart}
+				assert.Equal(t, pt.want, got, pt.line)
+			}
+		})
+	}
+}
+
+func Test_deltaCounters_idleSeries(t *testing.T) {
+	dc, err := newDeltaCounters(deltaCountersCumulative, time.Minute)
+	require.NoError(t, err)
+	now := time.Unix(1582230020, 0)
+	dc.now = func() time.Time { return now }
+	p := WavefrontParser{deltaCounters: dc}
+
+	got, err := p.Parse("∆requests.count 5 1582230020 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), got.Timeseries[0].Points[0].GetInt64Value())
+
+	now = now.Add(30 * time.Second)
+	got, err = p.Parse("∆requests.count 5 1582230050 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), got.Timeseries[0].Points[0].GetInt64Value())
+
+	// The series was idle for too long, a new one is started.
+	now = now.Add(2 * time.Minute)
+	got, err = p.Parse("∆requests.count 5 1582230170 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), got.Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, int64(1582230170), got.Timeseries[0].StartTimestamp.GetSeconds())
+	assert.Equal(t, 1, dc.series.Len())
+}
+
+func Test_deltaCounters_int64Precision(t *testing.T) {
+	dc, err := newDeltaCounters(deltaCountersCumulative, time.Minute)
+	require.NoError(t, err)
+	p := WavefrontParser{deltaCounters: dc}
+
+	got, err := p.Parse("∆requests.count 9007199254740993 1582230020 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), got.Timeseries[0].Points[0].GetInt64Value())
+
+	got, err = p.Parse("∆requests.count 2 1582230080 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, int64(9007199254740995), got.Timeseries[0].Points[0].GetInt64Value())
+}
+
+func Test_deltaCounters_mixedValueTypes(t *testing.T) {
+	dc, err := newDeltaCounters(deltaCountersCumulative, time.Minute)
+	require.NoError(t, err)
+	p := WavefrontParser{deltaCounters: dc}
+
+	got, err := p.Parse("∆hits 5 1582230020 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, got.MetricDescriptor.Type)
+	assert.Equal(t, int64(5), got.Timeseries[0].Points[0].GetInt64Value())
+
+	got, err = p.Parse("∆hits 2.5 1582230080 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, got.MetricDescriptor.Type)
+	assert.Equal(t, 7.5, got.Timeseries[0].Points[0].GetDoubleValue())
+
+	// The time series stays a double one once a double delta was seen.
+	got, err = p.Parse("∆hits 1 1582230140 source=app")
+	require.NoError(t, err)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, got.MetricDescriptor.Type)
+	assert.Equal(t, 8.5, got.Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, 1, dc.series.Len())
+}
+
+func Test_newDeltaCounters(t *testing.T) {
+	dc, err := newDeltaCounters("", 0)
+	require.NoError(t, err)
+	assert.True(t, dc.accumulate)
+
+	_, err = newDeltaCounters("rate", 0)
+	assert.Error(t, err)
+
+	_, err = newDeltaCounters(deltaCountersDelta, -time.Second)
+	assert.Error(t, err)
+
+	_, err = (&WavefrontParser{}).Parse("∆requests.count 5 source=app")
+	assert.Equal(t, errDeltaCountersNotSupported, err)
+}
+
+func buildDeltaMetric(
+	typ metricspb.MetricDescriptor_Type,
+	source string,
+	seconds int64,
+	value float64,
+) *metricspb.Metric {
+	point := &metricspb.Point{
+		Timestamp: &timestamp.Timestamp{Seconds: seconds},
+	}
+	if typ == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+		point.Value = &metricspb.Point_Int64Value{Int64Value: int64(value)}
+	} else {
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: value}
+	}
+	return buildMetric(typ, "requests.count", []string{"source"}, []string{source}, point)
+}
@@ -16,16 +16,15 @@ package wavefrontreceiver
 
 import (
 	"context"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/converter"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
@@ -63,7 +62,9 @@ func (f *Factory) CreateDefaultConfig() configmodels.Receiver {
 		TCPAddr: confignet.TCPAddr{
 			Endpoint: "localhost:2003",
 		},
-		TCPIdleTimeout: transport.TCPIdleTimeoutDefault,
+		TCPIdleTimeout:           transport.TCPIdleTimeoutDefault,
+		DeltaCounters:            deltaCountersCumulative,
+		DeltaCountersIdleTimeout: deltaCountersIdleTimeoutDefault,
 	}
 }
 
@@ -75,7 +76,12 @@ func (f *Factory) CreateTraceReceiver(
 	consumer consumer.TraceConsumerOld,
 ) (component.TraceReceiver, error) {
 
-	return nil, configerror.ErrDataTypeIsNotSupported
+	r, err := getOrCreateReceiver(logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.registerTraceConsumer(converter.NewInternalToOCTraceConverter(consumer))
+	return r, nil
 }
 
 // CreateMetricsReceiver creates a metrics receiver based on provided config.
@@ -86,27 +92,45 @@ func (f *Factory) CreateMetricsReceiver(
 	consumer consumer.MetricsConsumerOld,
 ) (component.MetricsReceiver, error) {
 
-	rCfg := cfg.(*Config)
-
-	// Wavefront is very similar to Carbon: it is TCP based in which each received
-	// text line represents a single metric data point. They differ on the format
-	// of their textual representation.
-	//
-	// The Wavefront receiver leverages the Carbon receiver code by implementing
-	// a dedicated parser for its format.
-	carbonCfg := carbonreceiver.Config{
-		ReceiverSettings: rCfg.ReceiverSettings,
-		NetAddr: confignet.NetAddr{
-			Endpoint:  rCfg.Endpoint,
-			Transport: "tcp",
-		},
-		TCPIdleTimeout: rCfg.TCPIdleTimeout,
-		Parser: &protocol.Config{
-			Type: "plaintext", // TODO: update after other parsers are implemented for Carbon receiver.
-			Config: &WavefrontParser{
-				ExtractCollectdTags: rCfg.ExtractCollectdTags,
-			},
-		},
+	r, err := getOrCreateReceiver(logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.registerMetricsConsumer(consumer)
+	return r, nil
+}
+
+// getOrCreateReceiver returns the receiver of the config, creating it on the
+// first call. Metrics and spans are sent to the same endpoint, so the metrics
+// and trace pipelines using a config share its receiver.
+func getOrCreateReceiver(logger *zap.Logger, rCfg *Config) (*wavefrontReceiver, error) {
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+
+	if r, ok := receivers[rCfg]; ok {
+		return r, nil
 	}
-	return carbonreceiver.New(logger, carbonCfg, consumer)
+	r, err := newWavefrontReceiver(logger, rCfg)
+	if err != nil {
+		return nil, err
+	}
+	receivers[rCfg] = r
+	return r, nil
 }
+
+// removeReceiver removes the receiver of the config, once it is shut down, so
+// that the pipelines built afterwards create a new one.
+func removeReceiver(rCfg *Config, r *wavefrontReceiver) {
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+
+	if receivers[rCfg] == r {
+		delete(receivers, rCfg)
+	}
+}
+
+// receivers holds the receivers created for each config.
+var (
+	receiversMu sync.Mutex
+	receivers   = make(map[*Config]*wavefrontReceiver)
+)
@@ -19,9 +19,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
-	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
 	"go.uber.org/zap"
 )
 
@@ -46,6 +49,51 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
 	mReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkTraceExporterOld))
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	assert.Nil(t, err, "receiver creation failed")
+	assert.Same(t, tReceiver, mReceiver, "receiver not shared by metrics and traces")
+}
+
+func TestCreateReceiverAfterShutdown(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+
+	mReceiver, err := factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	require.NoError(t, err)
+	require.NoError(t, mReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, mReceiver.Shutdown(context.Background()))
+
+	// The receiver of the config is created again, on the same endpoint.
+	tReceiver, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkTraceExporterOld))
+	require.NoError(t, err)
+	assert.NotSame(t, mReceiver, tReceiver)
+	require.NoError(t, tReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tReceiver.Shutdown(context.Background()))
+}
+
+func TestCreateReceiverInvalidTLS(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.TLSSetting = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "./testdata/missing.crt",
+			KeyFile:  "./testdata/missing.key",
+		},
+	}
+
+	mReceiver, err := factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidDeltaCounters(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.DeltaCounters = "rate"
+
+	mReceiver, err := factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), cfg, new(exportertest.SinkMetricsExporterOld))
+	assert.Error(t, err)
 	assert.Nil(t, mReceiver)
 }
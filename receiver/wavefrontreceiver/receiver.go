@@ -0,0 +1,193 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+var errEmptyEndpoint = errors.New("empty endpoint")
+
+// wavefrontReceiver receives the metrics and the spans sent to a Wavefront
+// endpoint. It is shared by the metrics and trace pipelines using the same
+// config, so that both are served by a single listener.
+type wavefrontReceiver struct {
+	sync.Mutex
+	config *Config
+
+	server   transport.LineServer
+	reporter *reporter
+	parser   protocol.Parser
+
+	metricsConsumer consumer.MetricsConsumerOld
+	traceConsumer   consumer.TraceConsumer
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.MetricsReceiver = (*wavefrontReceiver)(nil)
+var _ component.TraceReceiver = (*wavefrontReceiver)(nil)
+
+// newWavefrontReceiver creates the receiver for the given config, its
+// consumers are registered by the pipelines using it.
+func newWavefrontReceiver(logger *zap.Logger, rCfg *Config) (*wavefrontReceiver, error) {
+	if rCfg.Endpoint == "" {
+		return nil, errEmptyEndpoint
+	}
+
+	deltaCounters, err := newDeltaCounters(rCfg.DeltaCounters, rCfg.DeltaCountersIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wavefront is very similar to Carbon: it is TCP based in which each received
+	// text line represents a single metric data point. They differ on the format
+	// of their textual representation, and Wavefront also sends spans on the
+	// same connections.
+	//
+	// The Wavefront receiver leverages the Carbon transport code, the lines it
+	// receives are passed to handleLine. The metric lines are parsed by a
+	// dedicated parser for the Wavefront format.
+	parser, err := (&WavefrontParser{
+		ExtractCollectdTags: rCfg.ExtractCollectdTags,
+		deltaCounters:       deltaCounters,
+	}).BuildParser()
+	if err != nil {
+		return nil, err
+	}
+
+	// This should be the last one built, or if any other error is raised after
+	// it, the server should be closed.
+	server, err := transport.NewTCPLineServer(rCfg.Endpoint, rCfg.TCPIdleTimeout, rCfg.TLSSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wavefrontReceiver{
+		config:   rCfg,
+		server:   server,
+		reporter: newReporter(rCfg.Name(), logger),
+		parser:   parser,
+	}, nil
+}
+
+func (r *wavefrontReceiver) registerMetricsConsumer(mc consumer.MetricsConsumerOld) {
+	r.Lock()
+	defer r.Unlock()
+	r.metricsConsumer = mc
+}
+
+func (r *wavefrontReceiver) registerTraceConsumer(tc consumer.TraceConsumer) {
+	r.Lock()
+	defer r.Unlock()
+	r.traceConsumer = tc
+}
+
+// Start starts the shared listener, only the first call of the pipelines
+// using the receiver has an effect.
+func (r *wavefrontReceiver) Start(_ context.Context, host component.Host) error {
+	r.startOnce.Do(func() {
+		go func() {
+			if err := r.server.ListenAndServe(r.handleLine, r.reporter); err != nil {
+				host.ReportFatalError(err)
+			}
+		}()
+	})
+	return nil
+}
+
+// Shutdown stops the shared listener, only the first call of the pipelines
+// using the receiver has an effect. The receiver is no longer shared
+// afterwards: the pipelines built later for the same config get a new one.
+func (r *wavefrontReceiver) Shutdown(context.Context) error {
+	var err error
+	r.stopOnce.Do(func() {
+		removeReceiver(r.config, r)
+		err = r.server.Close()
+	})
+	return err
+}
+
+// handleLine passes the line on to the metrics or the trace pipeline,
+// according to its content. The lines of the pipelines not using the receiver
+// are dropped.
+func (r *wavefrontReceiver) handleLine(line string) error {
+	if isSpanLogsLine(line) || isSpanLine(line) {
+		return r.handleTraces(line)
+	}
+	return r.handleMetrics(line)
+}
+
+func (r *wavefrontReceiver) handleMetrics(line string) error {
+	r.Lock()
+	mc := r.metricsConsumer
+	r.Unlock()
+	if mc == nil {
+		return nil
+	}
+
+	ctx := r.reporter.OnDataReceived(context.Background())
+	metric, err := r.parser.Parse(line)
+	if err != nil {
+		r.reporter.OnTranslationError(ctx, err)
+		r.reporter.OnMetricsProcessed(ctx, 1, 1, nil)
+		return nil
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: []*metricspb.Metric{metric},
+	}
+	err = mc.ConsumeMetricsData(ctx, md)
+	r.reporter.OnMetricsProcessed(ctx, 1, 0, err)
+	return err
+}
+
+func (r *wavefrontReceiver) handleTraces(line string) error {
+	r.Lock()
+	tc := r.traceConsumer
+	r.Unlock()
+	if tc == nil {
+		return nil
+	}
+
+	ctx := r.reporter.OnTracesReceived(context.Background())
+	td, err := parseTraces(line)
+	if err != nil {
+		r.reporter.OnTranslationError(ctx, err)
+		r.reporter.OnTracesProcessed(ctx, 0, nil)
+		return nil
+	}
+
+	numSpans := td.SpanCount()
+	if numSpans == 0 {
+		r.reporter.OnTracesProcessed(ctx, 0, nil)
+		return nil
+	}
+	err = tc.ConsumeTraces(ctx, td)
+	r.reporter.OnTracesProcessed(ctx, numSpans, err)
+	return err
+}
@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testutil"
 	"go.uber.org/zap"
 )
@@ -49,6 +50,35 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
 	defer rcvr.Shutdown(context.Background())
 
+	histogram := func(name string) *metricspb.Metric {
+		return buildMetric(
+			metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+			name,
+			[]string{"source"},
+			[]string{"e2e"},
+			&metricspb.Point{
+				Timestamp: &timestamp.Timestamp{Seconds: 1582231120},
+				Value: &metricspb.Point_DistributionValue{
+					DistributionValue: &metricspb.DistributionValue{
+						Count: 2,
+						Sum:   3,
+						BucketOptions: &metricspb.DistributionValue_BucketOptions{
+							Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+								Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+									Bounds: []float64{1.5},
+								},
+							},
+						},
+						Buckets: []*metricspb.DistributionValue_Bucket{
+							{Count: 0},
+							{Count: 2},
+						},
+					},
+				},
+			},
+		)
+	}
+
 	tests := []struct {
 		name string
 		msg  string
@@ -122,6 +152,14 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "histogram.granularities",
+			msg:  "!M 1582231120 #2 1.5 latency source=e2e\n!H 1582231120 #2 1.5 latency source=e2e\n",
+			want: []*metricspb.Metric{
+				histogram("latency.m"),
+				histogram("latency.h"),
+			},
+		},
 	}
 	for _, tt := range tests {
 		conn, err := net.Dial("tcp", addr)
@@ -144,6 +182,49 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 	}
 }
 
+func Test_wavefrontreceiver_TracesEndToEnd(t *testing.T) {
+	factory := &Factory{}
+	rCfg := factory.CreateDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	rCfg.Endpoint = addr
+	metricsConsumer := waitableMetricsConsumer{}
+	mRcvr, err := factory.CreateMetricsReceiver(context.Background(), zap.NewNop(), rCfg, &metricsConsumer)
+	require.NoError(t, err)
+	traceConsumer := new(exportertest.SinkTraceExporterOld)
+	tRcvr, err := factory.CreateTraceReceiver(context.Background(), zap.NewNop(), rCfg, traceConsumer)
+	require.NoError(t, err)
+
+	require.NoError(t, mRcvr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tRcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer mRcvr.Shutdown(context.Background())
+	defer tRcvr.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	metricsConsumer.Add(1)
+	msg := "getAllUsers source=e2e traceId=7b3bf470-9456-11e8-9eb6-529269fb1459" +
+		" spanId=0313bafe-9457-11e8-9eb6-529269fb1459 1552949776000 343\n" +
+		"single.metric 1 1582231120 source=e2e\n"
+	_, err = fmt.Fprint(conn, msg)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	metricsConsumer.Wait()
+	metrics := metricsConsumer.PullReceivedMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "single.metric", metrics[0].MetricDescriptor.Name)
+
+	require.Eventually(t, func() bool {
+		return len(traceConsumer.AllTraces()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	spans := traceConsumer.AllTraces()[0].Spans
+	require.Len(t, spans, 1)
+	assert.Equal(t, "getAllUsers", spans[0].Name.GetValue())
+}
+
 type waitableMetricsConsumer struct {
 	sync.WaitGroup
 	mtx     sync.Mutex
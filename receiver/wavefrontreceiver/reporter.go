@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const (
+	// The transport and the format reported by the receiver.
+	tcpTransport    = "tcp"
+	wavefrontFormat = "wavefront"
+)
+
+// reporter implements the transport.Reporter interface, used for the metric
+// lines and the transport events, and reports the span lines, according to
+// the Collector metric observability package.
+type reporter struct {
+	name          string
+	logger        *zap.Logger
+	sugaredLogger *zap.SugaredLogger // Used for generic debug logging
+}
+
+var _ (transport.Reporter) = (*reporter)(nil)
+
+func newReporter(receiverName string, logger *zap.Logger) *reporter {
+	return &reporter{
+		name:          receiverName,
+		logger:        logger,
+		sugaredLogger: logger.Sugar(),
+	}
+}
+
+// OnDataReceived is called when a metric line is received. The returned
+// context should be passed to OnTranslationError and OnMetricsProcessed.
+func (r *reporter) OnDataReceived(ctx context.Context) context.Context {
+	ctx = obsreport.ReceiverContext(ctx, r.name, tcpTransport, r.name)
+	return obsreport.StartMetricsReceiveOp(ctx, r.name, tcpTransport)
+}
+
+// OnTranslationError is called when a metric or a span line can't be
+// converted to the internal format of the Collector.
+func (r *reporter) OnTranslationError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	r.logger.Debug(
+		"Wavefront translation error",
+		zap.String("receiver", r.name),
+		zap.Error(err))
+}
+
+// OnMetricsProcessed is called when the metric line is processed, err being
+// the error returned by the next consumer.
+func (r *reporter) OnMetricsProcessed(
+	ctx context.Context,
+	numReceivedTimeseries int,
+	numInvalidTimeseries int,
+	err error,
+) {
+	if err != nil {
+		r.logger.Debug(
+			"Wavefront receiver failed to push metrics into pipeline",
+			zap.String("receiver", r.name),
+			zap.Int("numReceivedTimeseries", numReceivedTimeseries),
+			zap.Int("numInvalidTimeseries", numInvalidTimeseries),
+			zap.Error(err))
+	}
+
+	numTimeseries := numReceivedTimeseries - numInvalidTimeseries
+	obsreport.EndMetricsReceiveOp(ctx, wavefrontFormat, numTimeseries, numTimeseries, err)
+}
+
+// OnTracesReceived is called when a span or span logs line is received. The
+// returned context should be passed to OnTranslationError and
+// OnTracesProcessed.
+func (r *reporter) OnTracesReceived(ctx context.Context) context.Context {
+	ctx = obsreport.ReceiverContext(ctx, r.name, tcpTransport, r.name)
+	return obsreport.StartTraceDataReceiveOp(ctx, r.name, tcpTransport)
+}
+
+// OnTracesProcessed is called when the span or span logs line is processed,
+// err being the error returned by the next consumer.
+func (r *reporter) OnTracesProcessed(ctx context.Context, numReceivedSpans int, err error) {
+	if err != nil {
+		r.logger.Debug(
+			"Wavefront receiver failed to push spans into pipeline",
+			zap.String("receiver", r.name),
+			zap.Int("numReceivedSpans", numReceivedSpans),
+			zap.Error(err))
+	}
+
+	obsreport.EndTraceDataReceiveOp(ctx, wavefrontFormat, numReceivedSpans, err)
+}
+
+func (r *reporter) OnDebugf(template string, args ...interface{}) {
+	if r.logger.Check(zap.DebugLevel, "debug") != nil {
+		r.sugaredLogger.Debugf(template, args...)
+	}
+}
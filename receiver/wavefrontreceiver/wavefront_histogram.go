@@ -0,0 +1,181 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// histogramGranularities maps the prefixes of the Wavefront histograms
+// aggregated by minute, hour and day to the suffix added to their name, as
+// Wavefront does, so that each granularity is its own series.
+var histogramGranularities = map[string]string{
+	"!M": ".m",
+	"!H": ".h",
+	"!D": ".d",
+}
+
+type centroid struct {
+	value float64
+	count int64
+}
+
+// isHistogramLine reports whether the line holds a Wavefront histogram.
+func isHistogramLine(line string) bool {
+	return strings.HasPrefix(line, "!")
+}
+
+// parseHistogram converts a Wavefront histogram, see
+// https://docs.wavefront.com/proxies_histograms.html#histogram-data-format-syntax,
+// to a distribution. Each histogram line is in the following format:
+//
+// 	"{!M | !H | !D} [<timestamp>] #<count> <centroid> [#<count> <centroid> ...] <metricName> source=<source> [pointTags]"
+//
+// The centroids, with their counts, are preserved as the buckets of the
+// distribution: each centroid is the lower bound of the bucket holding its
+// count, and the first bucket is always empty. The name of the distribution
+// is suffixed by ".m", ".h" or ".d" according to the granularity.
+func (wp *WavefrontParser) parseHistogram(line string) (*metricspb.Metric, error) {
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid wavefront histogram [%s]", line)
+	}
+	nameSuffix, ok := histogramGranularities[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("invalid wavefront histogram [%s]", line)
+	}
+	rest := parts[1]
+
+	var ts timestamp.Timestamp
+	parts = strings.SplitN(rest, " ", 2)
+	if strings.HasPrefix(parts[0], "#") {
+		// Timestamp can be omitted, get current time.
+		ts.Seconds = time.Now().Unix()
+	} else {
+		unixTime, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("invalid timestamp for wavefront histogram [%s]", line)
+		}
+		ts.Seconds = unixTime
+		rest = parts[1]
+	}
+
+	var centroids []centroid
+	for strings.HasPrefix(rest, "#") {
+		parts = strings.SplitN(rest, " ", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid centroid for wavefront histogram [%s]", line)
+		}
+		count, err := strconv.ParseInt(parts[0][1:], 10, 64)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid centroid count for wavefront histogram [%s]", line)
+		}
+		value, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid centroid for wavefront histogram [%s]: %v", line, err)
+		}
+		centroids = append(centroids, centroid{value: value, count: count})
+		rest = parts[2]
+	}
+	if len(centroids) == 0 {
+		return nil, fmt.Errorf("missing centroids for wavefront histogram [%s]", line)
+	}
+
+	metricName, tags := splitName(rest)
+	metricName = unDoubleQuote(metricName)
+	if metricName == "" {
+		return nil, fmt.Errorf("empty name for wavefront histogram [%s]", line)
+	}
+
+	labelKeys, labelValues, err := buildLabels(tags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wavefront histogram [%s]: %v", line, err)
+	}
+	if wp.ExtractCollectdTags {
+		metricName, labelKeys, labelValues = wp.injectCollectDLabels(metricName, labelKeys, labelValues)
+	}
+	metricName += nameSuffix
+
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      metricName,
+			Type:      metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+			LabelKeys: labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: labelValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &ts,
+						Value: &metricspb.Point_DistributionValue{
+							DistributionValue: buildDistribution(centroids),
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// buildDistribution returns the distribution with a bucket per distinct
+// centroid, whose lower bound is the centroid.
+func buildDistribution(centroids []centroid) *metricspb.DistributionValue {
+	sort.SliceStable(centroids, func(i, j int) bool {
+		return centroids[i].value < centroids[j].value
+	})
+
+	var bounds []float64
+	buckets := []*metricspb.DistributionValue_Bucket{{}}
+	var count int64
+	var sum float64
+	for _, c := range centroids {
+		count += c.count
+		sum += float64(c.count) * c.value
+		if n := len(bounds); n > 0 && bounds[n-1] == c.value {
+			buckets[n].Count += c.count
+			continue
+		}
+		bounds = append(bounds, c.value)
+		buckets = append(buckets, &metricspb.DistributionValue_Bucket{Count: c.count})
+	}
+
+	mean := sum / float64(count)
+	var sumOfSquaredDeviation float64
+	for _, c := range centroids {
+		sumOfSquaredDeviation += float64(c.count) * (c.value - mean) * (c.value - mean)
+	}
+
+	return &metricspb.DistributionValue{
+		Count:                 count,
+		Sum:                   sum,
+		SumOfSquaredDeviation: sumOfSquaredDeviation,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
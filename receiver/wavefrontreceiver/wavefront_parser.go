@@ -32,6 +32,10 @@ import (
 // into the internal format of the Collector
 type WavefrontParser struct {
 	ExtractCollectdTags bool `mapstructure:"extract_collectd_tags"`
+
+	// deltaCounters converts the delta counters, they are rejected when it is
+	// nil.
+	deltaCounters *deltaCounters
 }
 
 var _ (protocol.Parser) = (*WavefrontParser)(nil)
@@ -58,13 +62,22 @@ func (wp *WavefrontParser) BuildParser() (protocol.Parser, error) {
 // 	"<metricName> <metricValue> [<timestamp>] source=<source> [pointTags]"
 //
 // Detailed description of each element is available on the link above.
+//
+// Delta counters, whose names are prefixed with ∆ or Δ, are converted to
+// cumulative metrics without the prefix, see deltaCounters.
+//
+// Lines holding histograms are parsed by parseHistogram.
 func (wp *WavefrontParser) Parse(line string) (*metricspb.Metric, error) {
+	if isHistogramLine(line) {
+		return wp.parseHistogram(line)
+	}
+
 	parts := strings.SplitN(line, " ", 3)
 	if len(parts) < 3 {
 		return nil, fmt.Errorf("invalid wavefront metric [%s]", line)
 	}
 
-	metricName := unDoubleQuote(parts[0])
+	metricName, isDeltaCounter := trimDeltaCounterPrefix(unDoubleQuote(parts[0]))
 	if metricName == "" {
 		return nil, fmt.Errorf("empty name for wavefront metric [%s]", line)
 	}
@@ -135,6 +148,13 @@ func (wp *WavefrontParser) Parse(line string) (*metricspb.Metric, error) {
 			},
 		},
 	}
+
+	if isDeltaCounter {
+		if wp.deltaCounters == nil {
+			return nil, errDeltaCountersNotSupported
+		}
+		wp.deltaCounters.convert(metric)
+	}
 	return metric, nil
 }
 
@@ -169,13 +189,28 @@ func (wp *WavefrontParser) injectCollectDLabels(
 }
 
 func buildLabels(tags string) (keys []*metricspb.LabelKey, values []*metricspb.LabelValue, err error) {
+	err = parseTags(tags, func(key, value string) {
+		keys = append(keys, &metricspb.LabelKey{Key: key})
+		values = append(values, &metricspb.LabelValue{
+			Value:    value,
+			HasValue: true})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, values, nil
+}
+
+// parseTags calls addTag for each of the space separated, and optionally
+// double-quoted, key=value pairs of tags.
+func parseTags(tags string, addTag func(key, value string)) error {
 	if tags == "" {
-		return
+		return nil
 	}
 	for {
 		parts := strings.SplitN(tags, "=", 2)
 		if len(parts) != 2 {
-			return nil, nil, fmt.Errorf("failed to break key for [%s]", tags)
+			return fmt.Errorf("failed to break key for [%s]", tags)
 		}
 
 		key := parts[0]
@@ -215,10 +250,7 @@ func buildLabels(tags string) (keys []*metricspb.LabelKey, values []*metricspb.L
 			tagLen += i
 		}
 
-		keys = append(keys, &metricspb.LabelKey{Key: key})
-		values = append(values, &metricspb.LabelValue{
-			Value:    value,
-			HasValue: true})
+		addTag(key, value)
 
 		tags = strings.TrimLeft(tags[tagLen:], " ")
 		if tags == "" {
@@ -226,7 +258,7 @@ func buildLabels(tags string) (keys []*metricspb.LabelKey, values []*metricspb.L
 		}
 	}
 
-	return
+	return nil
 }
 
 func unDoubleQuote(s string) string {
@@ -255,6 +255,82 @@ func Test_wavefrontParser_Parse(t *testing.T) {
 				},
 			),
 		},
+		{
+			line: "!M 1533529977 #20 30.0 #10 6 #2 30 request.latency source=appServer1 region=us-west",
+			want: buildMetric(
+				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+				"request.latency.m",
+				[]string{"source", "region"},
+				[]string{"appServer1", "us-west"},
+				&metricspb.Point{
+					Timestamp: &timestamp.Timestamp{Seconds: 1533529977},
+					Value: &metricspb.Point_DistributionValue{
+						DistributionValue: &metricspb.DistributionValue{
+							Count:                 32,
+							Sum:                   720,
+							SumOfSquaredDeviation: 3960,
+							BucketOptions: &metricspb.DistributionValue_BucketOptions{
+								Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+									Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+										Bounds: []float64{6, 30},
+									},
+								},
+							},
+							Buckets: []*metricspb.DistributionValue_Bucket{
+								{Count: 0},
+								{Count: 10},
+								{Count: 22},
+							},
+						},
+					},
+				},
+			),
+		},
+		{
+			line:             "!D #3 1.5 \"daily latency\" source=tst",
+			missingTimestamp: true,
+			want: buildMetric(
+				metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+				"daily latency.d",
+				[]string{"source"},
+				[]string{"tst"},
+				&metricspb.Point{
+					Value: &metricspb.Point_DistributionValue{
+						DistributionValue: &metricspb.DistributionValue{
+							Count: 3,
+							Sum:   4.5,
+							BucketOptions: &metricspb.DistributionValue_BucketOptions{
+								Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+									Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+										Bounds: []float64{1.5},
+									},
+								},
+							},
+							Buckets: []*metricspb.DistributionValue_Bucket{
+								{Count: 0},
+								{Count: 3},
+							},
+						},
+					},
+				},
+			),
+		},
+		{
+			line:    "!W 1533529977 #20 30.0 request.latency source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!H 1533529977 request.latency source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!H 1533529977 #0 30.0 request.latency source=tst",
+			wantErr: true,
+		},
+		{
+			line:    "!H 1533529977 #2 xyz request.latency source=tst",
+			wantErr: true,
+		},
 		{
 			line:    "incorrect.tags 1.23 1582230000 1582230020",
 			wantErr: true,
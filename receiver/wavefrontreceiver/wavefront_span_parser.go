@@ -0,0 +1,303 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// Tags of Wavefront spans with a special meaning, see
+// https://docs.wavefront.com/trace_data_details.html#span-tags.
+const (
+	traceIDTag  = "traceId"
+	spanIDTag   = "spanId"
+	parentTag   = "parent"
+	followsTag  = "followsFrom"
+	sourceTag   = "source"
+	serviceTag  = "service"
+	spanKindTag = "span.kind"
+	spanLogsTag = "_spanLogs"
+
+	// spanLogEventField is the span log field naming the event.
+	spanLogEventField = "event"
+	// defaultSpanEventName is the name of the span events converted from span
+	// logs without an event field.
+	defaultSpanEventName = "log"
+)
+
+var spanKinds = map[string]pdata.SpanKind{
+	"client":   pdata.SpanKindCLIENT,
+	"server":   pdata.SpanKindSERVER,
+	"producer": pdata.SpanKindPRODUCER,
+	"consumer": pdata.SpanKindCONSUMER,
+	"internal": pdata.SpanKindINTERNAL,
+}
+
+// wavefrontSpanLogs holds the span logs of a span, sent as a JSON line after
+// the span. The span itself is embedded by the Wavefront SDKs.
+type wavefrontSpanLogs struct {
+	TraceID string             `json:"traceId"`
+	SpanID  string             `json:"spanId"`
+	Logs    []wavefrontSpanLog `json:"logs"`
+	Span    string             `json:"span"`
+}
+
+type wavefrontSpanLog struct {
+	// Timestamp is in microseconds since the epoch.
+	Timestamp int64             `json:"timestamp"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// isSpanLine reports whether the line holds a span rather than a metric: the
+// name of a span is directly followed by its tags instead of a value.
+func isSpanLine(line string) bool {
+	_, rest := splitName(line)
+	if i := strings.IndexByte(rest, ' '); i != -1 {
+		rest = rest[:i]
+	}
+	return strings.IndexByte(rest, '=') != -1
+}
+
+// splitName splits the line after its name, which is double-quoted if it has
+// spaces.
+func splitName(line string) (string, string) {
+	if strings.HasPrefix(line, `"`) {
+		if i := strings.Index(line[1:], `" `); i != -1 {
+			return line[:i+2], strings.TrimLeft(line[i+3:], " ")
+		}
+	}
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) != 2 {
+		return line, ""
+	}
+	return parts[0], parts[1]
+}
+
+// isSpanLogsLine reports whether the line holds the JSON span logs of a span.
+func isSpanLogsLine(line string) bool {
+	return strings.HasPrefix(line, "{")
+}
+
+// parseTraces converts the span or span logs line to traces. Spans tagged with
+// _spanLogs=true yield no spans: they are only passed on once their span logs,
+// which embed the span, are received.
+func parseTraces(line string) (pdata.Traces, error) {
+	if isSpanLogsLine(line) {
+		return parseSpanLogs(line)
+	}
+
+	td, hasSpanLogs, err := parseSpan(line)
+	if err != nil {
+		return td, err
+	}
+	if hasSpanLogs {
+		return pdata.NewTraces(), nil
+	}
+	return td, nil
+}
+
+// parseSpan converts a Wavefront span, see
+// https://docs.wavefront.com/trace_data_details.html#wavefront-span-format,
+// to traces holding a single span. It also reports whether the span has span
+// logs.
+//
+// Each span line is in the following format:
+//
+// 	"<operationName> source=<source> <spanTags> <start_milliseconds> <duration_milliseconds>"
+//
+// The spanTags must include traceId and spanId, whose values are UUIDs.
+func parseSpan(line string) (pdata.Traces, bool, error) {
+	td := pdata.NewTraces()
+
+	name, rest := splitName(line)
+	name = unDoubleQuote(name)
+	if name == "" {
+		return td, false, fmt.Errorf("empty name for wavefront span [%s]", line)
+	}
+
+	// The start and duration are the last two elements of the line.
+	i := strings.LastIndexByte(rest, ' ')
+	if i == -1 {
+		return td, false, fmt.Errorf("missing duration for wavefront span [%s]", line)
+	}
+	duration, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return td, false, fmt.Errorf("invalid duration for wavefront span [%s]: %v", line, err)
+	}
+	rest = strings.TrimRight(rest[:i], " ")
+	i = strings.LastIndexByte(rest, ' ')
+	if i == -1 {
+		return td, false, fmt.Errorf("missing start for wavefront span [%s]", line)
+	}
+	start, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil {
+		return td, false, fmt.Errorf("invalid start for wavefront span [%s]: %v", line, err)
+	}
+	tags := rest[:i]
+
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+
+	span.SetName(name)
+	span.SetStartTime(pdata.TimestampUnixNano(start * 1e6))
+	span.SetEndTime(pdata.TimestampUnixNano((start + duration) * 1e6))
+
+	var traceID, spanID, parentID, followsID string
+	var hasSpanLogs bool
+	err = parseTags(tags, func(key, value string) {
+		switch key {
+		case traceIDTag:
+			traceID = value
+		case spanIDTag:
+			spanID = value
+		case parentTag:
+			parentID = value
+		case followsTag:
+			followsID = value
+		case spanLogsTag:
+			hasSpanLogs = value == "true"
+		case sourceTag:
+			rs.Resource().Attributes().UpsertString(sourceTag, value)
+		case serviceTag:
+			rs.Resource().Attributes().UpsertString(conventions.AttributeServiceName, value)
+		case spanKindTag:
+			if kind, ok := spanKinds[strings.ToLower(value)]; ok {
+				span.SetKind(kind)
+			} else {
+				span.Attributes().UpsertString(key, value)
+			}
+		default:
+			span.Attributes().UpsertString(key, value)
+		}
+	})
+	if err != nil {
+		return td, false, fmt.Errorf("invalid wavefront span [%s]: %v", line, err)
+	}
+
+	tid, err := parseUUID(traceID)
+	if err != nil {
+		return td, false, fmt.Errorf("invalid traceId for wavefront span [%s]: %v", line, err)
+	}
+	span.SetTraceID(pdata.NewTraceID(tid))
+
+	sid, err := parseSpanID(spanID)
+	if err != nil {
+		return td, false, fmt.Errorf("invalid spanId for wavefront span [%s]: %v", line, err)
+	}
+	span.SetSpanID(sid)
+
+	// A span that follows from another one has it as its parent, unless the
+	// span has an explicit parent.
+	if parentID == "" {
+		parentID = followsID
+	}
+	if parentID != "" {
+		pid, err := parseSpanID(parentID)
+		if err != nil {
+			return td, false, fmt.Errorf("invalid parent for wavefront span [%s]: %v", line, err)
+		}
+		span.SetParentSpanID(pid)
+	}
+
+	return td, hasSpanLogs, nil
+}
+
+// parseSpanLogs converts the span embedded in the span logs to traces, with the
+// span logs as events of the span. See
+// https://docs.wavefront.com/trace_data_details.html#span-logs.
+func parseSpanLogs(line string) (pdata.Traces, error) {
+	var spanLogs wavefrontSpanLogs
+	if err := json.Unmarshal([]byte(line), &spanLogs); err != nil {
+		return pdata.NewTraces(), fmt.Errorf("invalid wavefront span logs [%s]: %v", line, err)
+	}
+	if spanLogs.Span == "" {
+		return pdata.NewTraces(), fmt.Errorf("missing span in wavefront span logs [%s]", line)
+	}
+
+	td, _, err := parseSpan(spanLogs.Span)
+	if err != nil {
+		return td, err
+	}
+
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	if spanLogs.SpanID != "" {
+		sid, err := parseSpanID(spanLogs.SpanID)
+		if err != nil || sid.String() != span.SpanID().String() {
+			return td, fmt.Errorf("span logs do not match their span [%s]", line)
+		}
+	}
+
+	events := span.Events()
+	events.Resize(len(spanLogs.Logs))
+	for i, log := range spanLogs.Logs {
+		event := events.At(i)
+		event.SetTimestamp(pdata.TimestampUnixNano(log.Timestamp * 1e3))
+		event.SetName(defaultSpanEventName)
+		if name, ok := log.Fields[spanLogEventField]; ok {
+			event.SetName(name)
+		}
+
+		// Sort the fields to get consistent attributes.
+		keys := make([]string, 0, len(log.Fields))
+		for k := range log.Fields {
+			if k != spanLogEventField {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			event.Attributes().UpsertString(k, log.Fields[k])
+		}
+	}
+
+	return td, nil
+}
+
+// parseUUID returns the 16 bytes of a UUID, with or without its hyphens.
+func parseUUID(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil || len(b) != 16 {
+		return nil, fmt.Errorf("invalid UUID %q", s)
+	}
+	return b, nil
+}
+
+// parseSpanID returns the span ID of the UUID of a Wavefront span. The UUID is
+// folded to 8 bytes by XOR-ing its halves, which leaves IDs of 8 bytes padded
+// with zeros unchanged.
+func parseSpanID(s string) (pdata.SpanID, error) {
+	b, err := parseUUID(s)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	for i := range id {
+		id[i] = b[i] ^ b[i+8]
+	}
+	return pdata.NewSpanID(id), nil
+}
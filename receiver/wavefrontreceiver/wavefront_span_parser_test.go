@@ -0,0 +1,213 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	testTraceID  = "7b3bf470-9456-11e8-9eb6-529269fb1459"
+	testSpanID   = "0313bafe-9457-11e8-8f3c-2d1a7b0e64c1"
+	testParentID = "00000000-0000-0000-5c2d-6b9e0f1a3748"
+)
+
+func Test_parseSpan(t *testing.T) {
+	tests := []struct {
+		name            string
+		line            string
+		want            func() pdata.Traces
+		wantHasSpanLogs bool
+		wantErr         bool
+	}{
+		{
+			name: "full_span",
+			line: "getAllUsers source=localhost traceId=" + testTraceID + " spanId=" + testSpanID +
+				" parent=" + testParentID + " application=Wavefront service=auth span.kind=server" +
+				" http.method=GET 1552949776000 343",
+			want: func() pdata.Traces {
+				td, span := buildSpan("getAllUsers", testParentID, 1552949776000, 343)
+				resAttrs := td.ResourceSpans().At(0).Resource().Attributes()
+				resAttrs.UpsertString("source", "localhost")
+				resAttrs.UpsertString(conventions.AttributeServiceName, "auth")
+				span.SetKind(pdata.SpanKindSERVER)
+				span.Attributes().UpsertString("application", "Wavefront")
+				span.Attributes().UpsertString("http.method", "GET")
+				return td
+			},
+		},
+		{
+			name: "quoted_name_and_tags",
+			line: "\"get users\" source=\"my host\" traceId=" + testTraceID + " spanId=" + testSpanID +
+				" custom=\"a b\" 1552949776000 1",
+			want: func() pdata.Traces {
+				td, span := buildSpan("get users", "", 1552949776000, 1)
+				td.ResourceSpans().At(0).Resource().Attributes().UpsertString("source", "my host")
+				span.Attributes().UpsertString("custom", "a b")
+				return td
+			},
+		},
+		{
+			name: "follows_from",
+			line: "consume source=h traceId=" + testTraceID + " spanId=" + testSpanID +
+				" followsFrom=" + testParentID + " 1552949776000 10",
+			want: func() pdata.Traces {
+				td, _ := buildSpan("consume", testParentID, 1552949776000, 10)
+				td.ResourceSpans().At(0).Resource().Attributes().UpsertString("source", "h")
+				return td
+			},
+		},
+		{
+			name: "with_span_logs",
+			line: "op source=h traceId=" + testTraceID + " spanId=" + testSpanID +
+				" _spanLogs=true 1552949776000 10",
+			want: func() pdata.Traces {
+				td, _ := buildSpan("op", "", 1552949776000, 10)
+				td.ResourceSpans().At(0).Resource().Attributes().UpsertString("source", "h")
+				return td
+			},
+			wantHasSpanLogs: true,
+		},
+		{
+			name:    "missing_trace_id",
+			line:    "op source=h spanId=" + testSpanID + " 1552949776000 10",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_span_id",
+			line:    "op source=h traceId=" + testTraceID + " spanId=xyz 1552949776000 10",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_duration",
+			line:    "op source=h traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 xyz",
+			wantErr: true,
+		},
+		{
+			name:    "missing_start",
+			line:    "op source=h traceId=" + testTraceID + " spanId=" + testSpanID + " 10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasSpanLogs, err := parseSpan(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want(), got)
+			assert.Equal(t, tt.wantHasSpanLogs, hasSpanLogs)
+		})
+	}
+}
+
+func Test_parseSpanLogs(t *testing.T) {
+	span := "op source=h traceId=" + testTraceID + " spanId=" + testSpanID + " _spanLogs=true 1552949776000 10"
+	line := `{"traceId":"` + testTraceID + `","spanId":"` + testSpanID + `","logs":[` +
+		`{"timestamp":1552949776001000,"fields":{"event":"error","error.kind":"exception","message":"boom"}},` +
+		`{"timestamp":1552949776002000,"fields":{"k":"v"}}` +
+		`],"span":"` + span + `"}`
+
+	got, err := parseSpanLogs(line)
+	require.NoError(t, err)
+
+	want, wantSpan := buildSpan("op", "", 1552949776000, 10)
+	want.ResourceSpans().At(0).Resource().Attributes().UpsertString("source", "h")
+	events := wantSpan.Events()
+	events.Resize(2)
+	events.At(0).SetName("error")
+	events.At(0).SetTimestamp(pdata.TimestampUnixNano(1552949776001000000))
+	events.At(0).Attributes().UpsertString("error.kind", "exception")
+	events.At(0).Attributes().UpsertString("message", "boom")
+	events.At(1).SetName("log")
+	events.At(1).SetTimestamp(pdata.TimestampUnixNano(1552949776002000000))
+	events.At(1).Attributes().UpsertString("k", "v")
+	assert.Equal(t, want, got)
+
+	_, err = parseSpanLogs(`{"traceId":"` + testTraceID + `","spanId":"` + testSpanID + `","logs":[]}`)
+	assert.Error(t, err, "span logs without span")
+
+	_, err = parseSpanLogs(`{"spanId":"` + testParentID + `","logs":[],"span":"` + span + `"}`)
+	assert.Error(t, err, "span logs of another span")
+
+	_, err = parseSpanLogs(`{"logs":`)
+	assert.Error(t, err, "invalid JSON")
+}
+
+func Test_parseTraces(t *testing.T) {
+	span := "op source=h traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 10"
+	td, err := parseTraces(span)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, td.SpanCount())
+
+	// Spans with span logs are passed on with their span logs.
+	spanWithLogs := "op source=h traceId=" + testTraceID + " spanId=" + testSpanID + " _spanLogs=true 1552949776000 10"
+	td, err = parseTraces(spanWithLogs)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, td.SpanCount())
+
+	td, err = parseTraces(`{"logs":[],"span":"` + spanWithLogs + `"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, td.SpanCount())
+
+	_, err = parseTraces("op source=h 1552949776000 10")
+	assert.Error(t, err)
+}
+
+func Test_parseSpanID(t *testing.T) {
+	id, err := parseSpanID(testParentID)
+	require.NoError(t, err)
+	assert.Equal(t, "5c2d6b9e0f1a3748", id.String())
+
+	id, err = parseSpanID("0313bafe945711e88f3c2d1a7b0e64c1")
+	require.NoError(t, err)
+	assert.Equal(t, "8c2f97e4ef597529", id.String())
+
+	_, err = parseSpanID("0313bafe-9457")
+	assert.Error(t, err)
+}
+
+// buildSpan returns traces holding a single span of the test trace and span
+// IDs, and that span.
+func buildSpan(name, parentID string, startMs, durationMs int64) (pdata.Traces, pdata.Span) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+
+	tid, _ := parseUUID(testTraceID)
+	span.SetTraceID(pdata.NewTraceID(tid))
+	sid, _ := parseSpanID(testSpanID)
+	span.SetSpanID(sid)
+	if parentID != "" {
+		pid, _ := parseSpanID(parentID)
+		span.SetParentSpanID(pid)
+	}
+	span.SetName(name)
+	span.SetStartTime(pdata.TimestampUnixNano(startMs * 1e6))
+	span.SetEndTime(pdata.TimestampUnixNano((startMs + durationMs) * 1e6))
+	return td, span
+}